@@ -4,8 +4,8 @@ package v1alpha1
 type PortEntry struct {
 	// 应用监听的端口号
 	Port uint16 `json:"port"`
-	// 端口使用的协议
-	// +kubebuilder:validation:Enum=TCP;UDP;TCPUDP;TCP_SSL;QUIC
+	// 端口使用的协议，HTTP 和 HTTPS 协议不分配端口，而是在端口池的共享七层监听器上分配转发规则（需端口池配置 layer7）
+	// +kubebuilder:validation:Enum=TCP;UDP;TCPUDP;TCP_SSL;QUIC;HTTP;HTTPS
 	Protocol string `json:"protocol"`
 	// 使用的端口池列表
 	Pools []string `json:"pools"`
@@ -15,6 +15,18 @@ type PortEntry struct {
 	// 包含服务端证书的 ID 的 Secret 名称。仅对 TCP_SSL 和 QUIC 协议有效。
 	// +optional
 	CertSecretName *string `json:"certSecretName,omitempty"`
+	// 额外的服务端证书 Secret 名称列表，用于同时配置多本不同算法类型（如 RSA 和 ECC）的服务端证书，
+	// 需同时指定 certSecretName，多本服务端证书的算法类型不能重复。仅对 TCP_SSL 和 QUIC 协议有效。
+	// +optional
+	ExtraCertSecretNames []string `json:"extraCertSecretNames,omitempty"`
+	// 包含客户端 CA 证书的 Secret 名称，指定后监听器开启双向认证（SSLMode 为 MUTUAL），需同时指定 certSecretName。
+	// Secret 中可以使用 qcloud_ca_cert_id 字段指定 CA 证书 ID，或使用 ca.crt 字段提供 CA 证书内容（自动上传到 SSL 证书服务）。
+	// 仅对 TCP_SSL 和 QUIC 协议有效。
+	// +optional
+	CACertSecretName *string `json:"caCertSecretName,omitempty"`
+	// 覆盖端口池监听器模板中的健康检查配置，enabled 总是生效，其它字段非空时生效。
+	// +optional
+	HealthCheck *HealthCheck `json:"healthCheck,omitempty"`
 }
 
 type CLBBindingState string
@@ -24,6 +36,7 @@ const (
 	CLBBindingStateBound                  CLBBindingState = "Bound"
 	CLBBindingStateNoBackend              CLBBindingState = "NoBackend"
 	CLBBindingStateWaitBackend            CLBBindingState = "WaitBackend"
+	CLBBindingStateWaitBackendReady       CLBBindingState = "WaitBackendReady"
 	CLBBindingStateNodeTypeNotSupported   CLBBindingState = "NodeTypeNotSupported"
 	CLBBindingStateDisabled               CLBBindingState = "Disabled"
 	CLBBindingStateFailed                 CLBBindingState = "Failed"
@@ -54,6 +67,12 @@ type PortBindingStatus struct {
 	// 服务端证书 ID（仅在 TCP_SSL 和 QUIC 协议下有效）
 	// +optional
 	CertId *string `json:"certId,omitempty"`
+	// 额外的服务端证书 ID 列表（仅在 TCP_SSL 和 QUIC 协议下有效）
+	// +optional
+	ExtraCertIds []string `json:"extraCertIds,omitempty"`
+	// 客户端 CA 证书 ID，不为空时监听器开启双向认证（仅在 TCP_SSL 和 QUIC 协议下有效）
+	// +optional
+	CACertId *string `json:"caCertId,omitempty"`
 	// 使用的端口池
 	Pool string `json:"pool"`
 	// 地域信息
@@ -71,6 +90,18 @@ type PortBindingStatus struct {
 	// 用于确定注册后端时使用 Pod/Node 的 IPv4 还是 IPv6 地址
 	// +optional
 	AddressIPVersion *string `json:"addressIPVersion,omitempty"`
+	// 转发规则的域名（仅在 HTTP 和 HTTPS 协议下有效）
+	// +optional
+	Domain *string `json:"domain,omitempty"`
+	// 转发规则的 URL（仅在 HTTP 和 HTTPS 协议下有效）
+	// +optional
+	Url *string `json:"url,omitempty"`
+	// 转发规则的 ID（仅在 HTTP 和 HTTPS 协议下有效）
+	// +optional
+	LocationId *string `json:"locationId,omitempty"`
+	// 摘流前后端的权重，后端重新就绪时恢复为该权重
+	// +optional
+	OriginalWeight *int64 `json:"originalWeight,omitempty"`
 }
 
 // CLBBindingSpec defines the desired state of CLBPodBinding.
@@ -80,4 +111,15 @@ type CLBBindingSpec struct {
 	Disabled *bool `json:"disabled,omitempty"`
 	// 需要绑定的端口配置列表
 	Ports []PortEntry `json:"ports"`
+	// 摘流时间（秒），覆盖端口池中的 drainSeconds，解绑后端前先将其权重设为 0 并等待该时间
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=3600
+	// +optional
+	DrainSeconds *int64 `json:"drainSeconds,omitempty"`
+	// 是否等待后端就绪后再绑定到 CLB，覆盖端口池中的 waitBackendReady
+	// +optional
+	WaitBackendReady *bool `json:"waitBackendReady,omitempty"`
+	// 等待就绪的容器端口名称，指定后只需声明了该端口的容器就绪即可绑定，否则需等待 Pod Ready
+	// +optional
+	ReadinessPortName *string `json:"readinessPortName,omitempty"`
 }
@@ -0,0 +1,114 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CLBListenerBackend 定义监听器绑定的后端
+type CLBListenerBackend struct {
+	// 后端 IP
+	IP string `json:"ip"`
+	// 后端端口
+	Port uint16 `json:"port"`
+	// 后端权重，为空时使用默认权重，为 0 时 CLB 不再向该后端转发新连接（摘流）
+	// +optional
+	Weight *int64 `json:"weight,omitempty"`
+}
+
+// CLBListenerSpec defines the desired state of CLBListener.
+type CLBListenerSpec struct {
+	// 负载均衡器 ID
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="Value is immutable"
+	LoadbalancerID string `json:"loadbalancerID"`
+	// 负载均衡器所在地域
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="Value is immutable"
+	Region string `json:"region"`
+	// 监听器端口
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="Value is immutable"
+	Port uint16 `json:"port"`
+	// 监听器端口段的结束端口（当使用端口段时）
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="Value is immutable"
+	// +optional
+	EndPort *uint16 `json:"endPort,omitempty"`
+	// 监听器协议
+	// +kubebuilder:validation:Enum=TCP;UDP;TCP_SSL;QUIC
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="Value is immutable"
+	Protocol string `json:"protocol"`
+	// 服务端证书 ID（仅在 TCP_SSL 和 QUIC 协议下有效）
+	// +optional
+	CertId *string `json:"certId,omitempty"`
+	// 额外的服务端证书 ID 列表，多本服务端证书的算法类型不能重复（仅在 TCP_SSL 和 QUIC 协议下有效）
+	// +optional
+	ExtraCertIds []string `json:"extraCertIds,omitempty"`
+	// 客户端 CA 证书 ID，不为空时监听器开启双向认证（仅在 TCP_SSL 和 QUIC 协议下有效）
+	// +optional
+	CACertId *string `json:"caCertId,omitempty"`
+	// 创建监听器时使用的监听器名称，记录了监听器的归属，用于识别和复用已有的监听器
+	// +optional
+	ListenerName *string `json:"listenerName,omitempty"`
+	// 监听器配置（健康检查、调度算法等），修改后会同步到监听器
+	// +optional
+	ListenerTemplate *ListenerTemplate `json:"listenerTemplate,omitempty"`
+	// 是否是预创建的监听器，预创建的监听器只会被复用，不会被创建，删除 CLBListener 时也只解绑后端，不删除监听器
+	// +optional
+	Precreated bool `json:"precreated,omitempty"`
+	// 监听器绑定的后端，不在列表中的后端会被解绑
+	// +optional
+	Backends []CLBListenerBackend `json:"backends,omitempty"`
+}
+
+type CLBListenerState string
+
+const (
+	CLBListenerStatePending  CLBListenerState = "Pending"
+	CLBListenerStateSynced   CLBListenerState = "Synced"
+	CLBListenerStateFailed   CLBListenerState = "Failed"
+	CLBListenerStateDeleting CLBListenerState = "Deleting"
+)
+
+// CLBListenerStatus defines the observed state of CLBListener.
+type CLBListenerStatus struct {
+	// 监听器 ID
+	// +optional
+	ListenerID string `json:"listenerID,omitempty"`
+	// 状态: Pending/Synced/Failed/Deleting
+	// +kubebuilder:default=Pending
+	State CLBListenerState `json:"state"`
+	// 状态信息
+	// +optional
+	Message string `json:"message,omitempty"`
+	// 最近一次同步成功的 spec 对应的 generation
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=clbl
+// +kubebuilder:printcolumn:name="LoadBalancer",type="string",JSONPath=".spec.loadbalancerID",description="LoadBalancer ID"
+// +kubebuilder:printcolumn:name="Port",type="integer",JSONPath=".spec.port",description="Port"
+// +kubebuilder:printcolumn:name="Protocol",type="string",JSONPath=".spec.protocol",description="Protocol"
+// +kubebuilder:printcolumn:name="Listener",type="string",JSONPath=".status.listenerID",description="Listener ID"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state",description="State"
+
+// CLBListener is the Schema for the clblisteners API.
+type CLBListener struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CLBListenerSpec   `json:"spec,omitempty"`
+	Status CLBListenerStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CLBListenerList contains a list of CLBListener.
+type CLBListenerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CLBListener `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CLBListener{}, &CLBListenerList{})
+}
@@ -70,12 +70,97 @@ type CLBPortPoolSpec struct {
 	// 均衡器来补充可分配监听器数量。
 	// +optional
 	AutoCreate *AutoCreateConfig `json:"autoCreate,omitempty"`
+	// 监听器模板，控制器为该端口池创建监听器时使用，修改后会同步到已绑定的监听器。
+	// +optional
+	ListenerTemplate *ListenerTemplate `json:"listenerTemplate,omitempty"`
+	// 摘流时间（秒）。解绑后端前先将其在 CLB 上的权重设为 0，等待摘流时间后再解绑，避免存量连接被立即中断。
+	// 默认为 0，即立即解绑。Pod/Node 可通过 networking.cloud.tencent.com/clb-drain-seconds 注解覆盖。
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=3600
+	// +optional
+	DrainSeconds *int64 `json:"drainSeconds,omitempty"`
+	// 是否等待后端（Pod/Node）就绪后再绑定到 CLB。启用后，端口会正常分配，但后端就绪后才会注册到监听器，
+	// 后端变为未就绪时会将其权重设为 0。Pod/Node 可通过 networking.cloud.tencent.com/clb-wait-backend-ready 注解覆盖。
+	// +optional
+	WaitBackendReady *bool `json:"waitBackendReady,omitempty"`
+	// 七层转发配置。端口映射使用 HTTP/HTTPS 协议时，不再为每个 Pod/Node 分配独立的监听器，而是在 CLB 的共享
+	// 七层监听器上为其创建一条转发规则（域名 + URL），从而用一个监听器承载大量 Pod，节省监听器配额。
+	// +optional
+	Layer7 *Layer7Config `json:"layer7,omitempty"`
 }
 
 func (pool *CLBPortPool) GetRegion() string {
 	return util.GetRegionFromPtr(pool.Spec.Region)
 }
 
+// IsProtocolPrecreated 判断指定协议是否预创建了监听器，未启用预创建时不限制。
+// TCPUDP 协议同时需要 TCP 和 UDP 监听器，两者都预创建了才算预创建。
+func (spec *CLBPortPoolSpec) IsProtocolPrecreated(protocol string) bool {
+	lcp := spec.ListenerPrecreate
+	if lcp == nil || !lcp.Enabled {
+		return true
+	}
+	tcp := util.GetValue(lcp.TCP) > 0
+	udp := util.GetValue(lcp.UDP) > 0
+	switch protocol {
+	case "TCP":
+		return tcp
+	case "UDP":
+		return udp
+	case "TCPUDP":
+		return tcp && udp
+	case "HTTP", "HTTPS": // 七层协议使用端口范围外的共享监听器，不受预创建限制
+		return true
+	default: // TCP_SSL / QUIC 等预创建不支持的协议
+		return false
+	}
+}
+
+// Layer7Config 定义七层转发规则的配置
+type Layer7Config struct {
+	// 转发规则的域名模板，支持用双花括号引用以下变量：pod 或 node（Pod 或 Node 的名称）、namespace（命名空间）、
+	// port（应用端口），用法见端口池文档。需保证渲染出的域名 + URL 不会重复，否则不同的 Pod/Node 会争抢同一条转发规则，
+	// 因此 domain 和 url 中必须引用 pod 和 namespace，或引用 node。
+	// +kubebuilder:validation:MinLength=1
+	Domain string `json:"domain"`
+	// 转发规则的 URL 模板，支持的变量与 domain 相同，默认为 /。
+	// +optional
+	Url *string `json:"url,omitempty"`
+	// HTTP 共享监听器的端口，默认为 80，不能在端口池的端口范围内。
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="Value is immutable"
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	HTTPPort *uint16 `json:"httpPort,omitempty"`
+	// HTTPS 共享监听器的端口，默认为 443，不能在端口池的端口范围内。
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="Value is immutable"
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	HTTPSPort *uint16 `json:"httpsPort,omitempty"`
+	// HTTPS 共享监听器的服务端证书 ID，使用 HTTPS 协议时必填，通常使用与域名模板匹配的泛域名证书。
+	// +optional
+	CertId *string `json:"certId,omitempty"`
+}
+
+// 七层共享监听器的默认端口
+const (
+	DefaultLayer7HTTPPort  uint16 = 80
+	DefaultLayer7HTTPSPort uint16 = 443
+)
+
+// GetListenerPort 获取指定七层协议的共享监听器端口
+func (c *Layer7Config) GetListenerPort(protocol string) uint16 {
+	if protocol == "HTTPS" {
+		if c.HTTPSPort != nil {
+			return *c.HTTPSPort
+		}
+		return DefaultLayer7HTTPSPort
+	}
+	if c.HTTPPort != nil {
+		return *c.HTTPPort
+	}
+	return DefaultLayer7HTTPPort
+}
+
 // AutoCreateConfig 定义自动创建 CLB 的配置
 type AutoCreateConfig struct {
 	// 是否启用自动创建
@@ -101,6 +186,85 @@ type ListenerPrecreateConfig struct {
 	UDP *uint16 `json:"udp,omitempty"`
 }
 
+// ListenerTemplate 定义监听器模板，未指定的字段使用 CLB 的默认值，且不会对账已有监听器的对应配置。
+type ListenerTemplate struct {
+	// 健康检查配置，不指定时创建的监听器默认关闭健康检查。
+	// +optional
+	HealthCheck *HealthCheck `json:"healthCheck,omitempty"`
+	// 监听器转发的方式，可选值：WRR（按权重轮询）、LEAST_CONN（按最小连接数），CLB 默认为 WRR。
+	// +kubebuilder:validation:Enum=WRR;LEAST_CONN
+	// +optional
+	Scheduler *string `json:"scheduler,omitempty"`
+	// 会话保持时间，单位：秒。可选值：30~3600，0 表示不开启会话保持。仅对 TCP/UDP 监听器生效。
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=3600
+	// +optional
+	SessionExpireTime *int64 `json:"sessionExpireTime,omitempty"`
+	// 会话保持类型，可选值：NORMAL（默认会话保持类型）、QUIC_CID（根据 QUIC Connection ID 做会话保持，
+	// 只支持 UDP 协议，且 scheduler 必须为 WRR）。仅对 TCP/UDP 监听器生效。
+	// +kubebuilder:validation:Enum=NORMAL;QUIC_CID
+	// +optional
+	SessionType *string `json:"sessionType,omitempty"`
+	// 空闲连接超时时间，单位：秒。CLB 默认 TCP 监听器为 900，UDP 监听器为 300。共享型和独占型实例可选值：10~900，
+	// 性能容量型实例可选值：10~1980。仅对 TCP/UDP 监听器生效。
+	// +kubebuilder:validation:Minimum=10
+	// +kubebuilder:validation:Maximum=1980
+	// +optional
+	IdleConnectTimeout *int64 `json:"idleConnectTimeout,omitempty"`
+	// 解绑后端服务时是否向客户端发送 RST 并触发重新调度。仅对 TCP/UDP 监听器生效。
+	// +optional
+	DeregisterTargetRst *bool `json:"deregisterTargetRst,omitempty"`
+}
+
+// HealthCheck 定义监听器的健康检查配置，字段含义参考 CLB API 的 HealthCheck 结构：
+// https://cloud.tencent.com/document/api/214/30694#HealthCheck
+type HealthCheck struct {
+	// 是否开启健康检查
+	Enabled bool `json:"enabled"`
+	// 健康检查的响应超时时间，单位：秒，可选值：2~60，默认值：2。响应超时时间要小于检查间隔时间。
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Maximum=60
+	// +optional
+	TimeOut *int64 `json:"timeOut,omitempty"`
+	// 健康检查探测间隔时间，单位：秒，可选值：2~300，默认值：5。
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Maximum=300
+	// +optional
+	IntervalTime *int64 `json:"intervalTime,omitempty"`
+	// 健康阈值，表示连续检查成功多少次后认定后端服务健康，可选值：2~10，默认值：3。
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	HealthNum *int64 `json:"healthNum,omitempty"`
+	// 不健康阈值，表示连续检查失败多少次后认定后端服务不健康，可选值：2~10，默认值：3。
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	UnHealthNum *int64 `json:"unHealthNum,omitempty"`
+	// 自定义探测相关参数。健康检查端口，默认为后端服务的端口。
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	CheckPort *int64 `json:"checkPort,omitempty"`
+	// 健康检查使用的协议，TCP 监听器可选 TCP、CUSTOM，UDP 监听器可选 PING、CUSTOM，
+	// TCP_SSL 和 QUIC 监听器可选 TCP、CUSTOM。
+	// +kubebuilder:validation:Enum=TCP;PING;CUSTOM
+	// +optional
+	CheckType *string `json:"checkType,omitempty"`
+	// 自定义探测相关参数。健康检查协议 checkType 为 CUSTOM 时，输入格式，可选值：HEX 或 TEXT。
+	// +kubebuilder:validation:Enum=HEX;TEXT
+	// +optional
+	ContextType *string `json:"contextType,omitempty"`
+	// 自定义探测相关参数。健康检查协议 checkType 为 CUSTOM 时，健康检查发送的请求内容，最长 500 字符。
+	// +kubebuilder:validation:MaxLength=500
+	// +optional
+	SendContext *string `json:"sendContext,omitempty"`
+	// 自定义探测相关参数。健康检查协议 checkType 为 CUSTOM 时，健康检查返回的结果，最长 500 字符。
+	// +kubebuilder:validation:MaxLength=500
+	// +optional
+	RecvContext *string `json:"recvContext,omitempty"`
+}
+
 // CreateLBParameters 定义创建负载均衡器的参数
 type CreateLBParameters struct {
 	// 仅适用于公网负载均衡。目前仅广州、上海、南京、济南、杭州、福州、北京、石家庄、武汉、长沙、成都、重庆地域支持静态单线 IP 线路类型，如需体验，请联系商务经理申请。申请通过后，即可选择中国移动（CMCC）、中国联通（CUCC）或中国电信（CTCC）的运营商类型，网络计费模式只能使用按带宽包计费(BANDWIDTH_PACKAGE)。 如果不指定本参数，则默认使用BGP。可通过 DescribeResources 接口查询一个地域所支持的Isp。
@@ -113,6 +277,13 @@ type CreateLBParameters struct {
 	AddressIPVersion *string `json:"addressIPVersion,omitempty"`
 	// Target是否放通来自CLB的流量。开启放通（true）：只验证CLB上的安全组；不开启放通（false）：需同时验证CLB和后端实例上的安全组。默认值为 true。
 	LoadBalancerPassToTarget *bool `json:"loadBalancerPassToTarget,omitempty"`
+	// 负载均衡实例绑定的安全组 ID 列表，最多 5 个。负载均衡创建后立即绑定，控制器会持续对账自动创建的负载均衡的安全组，
+	// 端口池中已有的负载均衡（非自动创建）的安全组与之不一致时只产生事件，不做修改。
+	// +kubebuilder:validation:MaxItems=5
+	SecurityGroups []string `json:"securityGroups,omitempty"`
+	// 是否在 securityGroups 的安全组中维护放通端口池端口范围（startPort 到 endPort）的 TCP 和 UDP 入站规则（来源 0.0.0.0/0），
+	// 规则描述中记录端口池名称，端口范围变化时原地更新规则。默认为 false。
+	SyncSecurityGroupRules *bool `json:"syncSecurityGroupRules,omitempty"`
 	// 是否创建域名化负载均衡。
 	DynamicVip *bool `json:"dynamicVip,omitempty"`
 	// 负载均衡后端目标设备所属的网络 ID，如vpc-12345678，可以通过 DescribeVpcs 接口获取。 不填此参数则默认为当前集群所在 VPC。创建内网负载均衡实例时，此参数必填。
@@ -191,6 +362,10 @@ type CLBPortPoolStatus struct {
 	Quota uint16 `json:"quota"`
 	// 负载均衡器状态列表
 	LoadbalancerStatuses []LoadBalancerStatus `json:"loadbalancerStatuses,omitempty"`
+	// 自动创建的负载均衡已绑定的端口池安全组，securityGroups 被清空时据此解绑
+	SecurityGroups []string `json:"securityGroups,omitempty"`
+	// 已维护放通端口池端口范围入站规则的安全组，安全组被移除、关闭 syncSecurityGroupRules 或删除端口池时据此清理规则
+	SecurityGroupRules []string `json:"securityGroupRules,omitempty"`
 }
 
 type CLBPortPoolState string
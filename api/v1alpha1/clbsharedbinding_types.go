@@ -0,0 +1,77 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CLBSharedBindingSpec defines the desired state of CLBSharedBinding.
+type CLBSharedBindingSpec struct {
+	// 选择需要绑定到监听器的 Pod（同命名空间），为空时不选择任何 Pod
+	Selector map[string]string `json:"selector"`
+	// 应用监听的端口号
+	Port uint16 `json:"port"`
+	// 端口使用的协议
+	// +kubebuilder:validation:Enum=TCP;UDP
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="Value is immutable"
+	Protocol string `json:"protocol"`
+	// 使用的端口池
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="Value is immutable"
+	Pool string `json:"pool"`
+	// 后端默认权重，为空时使用 CLB 的默认权重，可以通过 Pod 注解 networking.cloud.tencent.com/clb-weight 单独覆盖
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Weight *int64 `json:"weight,omitempty"`
+	// 覆盖端口池监听器模板中的健康检查配置，enabled 总是生效，其它字段非空时生效。
+	// +optional
+	HealthCheck *HealthCheck `json:"healthCheck,omitempty"`
+}
+
+// CLBSharedBindingStatus defines the observed state of CLBSharedBinding.
+type CLBSharedBindingStatus struct {
+	// 绑定状态
+	// +kubebuilder:default=Pending
+	State CLBBindingState `json:"state"`
+	// 状态信息
+	// +optional
+	Message string `json:"message,omitempty"`
+	// 分配到的端口
+	// +optional
+	PortBinding *PortBindingStatus `json:"portBinding,omitempty"`
+	// 监听器的外部访问地址（host:port）
+	// +optional
+	Address string `json:"address,omitempty"`
+	// 当前绑定到监听器的后端数量
+	// +optional
+	Backends int `json:"backends,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=csb
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state",description="State"
+// +kubebuilder:printcolumn:name="Address",type="string",JSONPath=".status.address",description="Address"
+// +kubebuilder:printcolumn:name="Backends",type="integer",JSONPath=".status.backends",description="Backends"
+
+// CLBSharedBinding is the Schema for the clbsharedbindings API.
+// 从端口池中分配一个端口，该端口的监听器同时绑定所有选中的 Pod，由 CLB 在这些 Pod 之间负载均衡。
+type CLBSharedBinding struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CLBSharedBindingSpec   `json:"spec,omitempty"`
+	Status CLBSharedBindingStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CLBSharedBindingList contains a list of CLBSharedBinding.
+type CLBSharedBindingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CLBSharedBinding `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CLBSharedBinding{}, &CLBSharedBindingList{})
+}
@@ -0,0 +1,525 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/tkestack/tke-extend-network-controller/api/v1beta1"
+)
+
+// v1alpha1 与 v1beta1（Hub）之间的转换。
+//
+// v1beta1 的 status 用 phase + conditions 代替 v1alpha1 的 state + message：
+//   - CLBBinding 的 state 中混杂了阶段和错误原因，转换为 v1beta1 时拆分为 phase 和 Ready condition 的 reason，
+//     转换回 v1alpha1 时如果 Ready 的 reason 是属于该 phase 的 state，则还原为该 state，否则使用 phase。
+//   - CLBPortPool 的 state 与 phase 一一对应，message 记录在 Ready condition 中。
+//
+// v1alpha1 无法表达的 conditions（其它类型的 condition、lastTransitionTime 等）在转换为 v1alpha1 时
+// 连同 phase 以 JSON 记录到 v1beta1StatusAnnotation 注解中，转换回 v1beta1 时，如果 v1alpha1 的 state 和 message
+// 没有被修改过则原样还原，保证双向转换都不丢失信息。仅当从 v1alpha1 字段无法推导出相同的 conditions 时才写入该注解。
+
+const v1beta1StatusAnnotation = "networking.cloud.tencent.com/v1beta1-status"
+
+// v1beta1Status 记录在注解中的 v1beta1 status 字段
+type v1beta1Status struct {
+	Phase      string             `json:"phase"`
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+var _ conversion.Convertible = &CLBPortPool{}
+var _ conversion.Convertible = &CLBPodBinding{}
+var _ conversion.Convertible = &CLBNodeBinding{}
+
+// ConvertTo converts this CLBPortPool to the Hub version (v1beta1).
+func (src *CLBPortPool) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.CLBPortPool)
+	recorded, err := popStatus(&src.ObjectMeta, &dst.ObjectMeta)
+	if err != nil {
+		return err
+	}
+	convertCLBPortPoolSpecToV1beta1(&src.Spec, &dst.Spec)
+	dst.Status.Phase = v1beta1.CLBPortPoolPhase(src.Status.State)
+	dst.Status.Quota = src.Status.Quota
+	dst.Status.LoadBalancerStatuses = nil
+	for _, lb := range src.Status.LoadbalancerStatuses {
+		dst.Status.LoadBalancerStatuses = append(dst.Status.LoadBalancerStatuses, v1beta1.LoadBalancerStatus{
+			AutoCreated:      lb.AutoCreated,
+			State:            v1beta1.LoadBalancerState(lb.State),
+			LoadBalancerID:   lb.LoadbalancerID,
+			LoadBalancerName: lb.LoadbalancerName,
+			Ips:              lb.Ips,
+			Hostname:         lb.Hostname,
+			Allocated:        lb.Allocated,
+			AddressIPVersion: lb.AddressIPVersion,
+		})
+	}
+	dst.Status.SecurityGroups = src.Status.SecurityGroups
+	dst.Status.SecurityGroupRules = src.Status.SecurityGroupRules
+	dst.Status.Conditions = portPoolConditions(&src.ObjectMeta, &src.Status, recorded)
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this version.
+func (dst *CLBPortPool) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.CLBPortPool)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	convertCLBPortPoolSpecFromV1beta1(&src.Spec, &dst.Spec)
+	dst.Status.State, dst.Status.Message = portPoolStateFromV1beta1(string(src.Status.Phase), src.Status.Conditions)
+	dst.Status.Quota = src.Status.Quota
+	dst.Status.LoadbalancerStatuses = nil
+	for _, lb := range src.Status.LoadBalancerStatuses {
+		dst.Status.LoadbalancerStatuses = append(dst.Status.LoadbalancerStatuses, LoadBalancerStatus{
+			AutoCreated:      lb.AutoCreated,
+			State:            LoadBalancerState(lb.State),
+			LoadbalancerID:   lb.LoadBalancerID,
+			LoadbalancerName: lb.LoadBalancerName,
+			Ips:              lb.Ips,
+			Hostname:         lb.Hostname,
+			Allocated:        lb.Allocated,
+			AddressIPVersion: lb.AddressIPVersion,
+		})
+	}
+	dst.Status.SecurityGroups = src.Status.SecurityGroups
+	dst.Status.SecurityGroupRules = src.Status.SecurityGroupRules
+	recorded := &v1beta1Status{Phase: string(src.Status.Phase), Conditions: src.Status.Conditions}
+	return pushStatus(&dst.ObjectMeta, recorded, portPoolConditions(&dst.ObjectMeta, &dst.Status, nil))
+}
+
+func portPoolStateFromV1beta1(phase string, conditions []metav1.Condition) (CLBPortPoolState, *string) {
+	if ready := meta.FindStatusCondition(conditions, v1beta1.CLBPortPoolConditionReady); ready != nil && ready.Message != "" {
+		return CLBPortPoolState(phase), &ready.Message
+	}
+	return CLBPortPoolState(phase), nil
+}
+
+func portPoolConditions(objMeta *metav1.ObjectMeta, status *CLBPortPoolStatus, recorded *v1beta1Status) []metav1.Condition {
+	if recorded != nil && recorded.Phase == string(status.State) {
+		// state 和 message 没有被 v1alpha1 的客户端修改过，原样还原
+		if state, message := portPoolStateFromV1beta1(recorded.Phase, recorded.Conditions); state == status.State && equality.Semantic.DeepEqual(message, status.Message) {
+			return recorded.Conditions
+		}
+	}
+	var conditions []metav1.Condition
+	if recorded != nil {
+		conditions = recorded.Conditions
+	}
+	if status.State == "" {
+		return conditions
+	}
+	message := ""
+	if status.Message != nil {
+		message = *status.Message
+	}
+	readyStatus := metav1.ConditionFalse
+	if status.State == CLBPortPoolStateActive || status.State == CLBPortPoolStateScaling {
+		readyStatus = metav1.ConditionTrue
+	}
+	return setReadyCondition(objMeta, conditions, v1beta1.CLBPortPoolConditionReady, readyStatus, string(status.State), message)
+}
+
+// ConvertTo converts this CLBPodBinding to the Hub version (v1beta1).
+func (src *CLBPodBinding) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.CLBPodBinding)
+	recorded, err := popStatus(&src.ObjectMeta, &dst.ObjectMeta)
+	if err != nil {
+		return err
+	}
+	convertCLBBindingSpecToV1beta1(&src.Spec, &dst.Spec)
+	convertCLBBindingStatusToV1beta1(&src.ObjectMeta, &src.Status, &dst.Status, recorded)
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this version.
+func (dst *CLBPodBinding) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.CLBPodBinding)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	convertCLBBindingSpecFromV1beta1(&src.Spec, &dst.Spec)
+	return convertCLBBindingStatusFromV1beta1(&src.Status, &dst.ObjectMeta, &dst.Status)
+}
+
+// ConvertTo converts this CLBNodeBinding to the Hub version (v1beta1).
+func (src *CLBNodeBinding) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.CLBNodeBinding)
+	recorded, err := popStatus(&src.ObjectMeta, &dst.ObjectMeta)
+	if err != nil {
+		return err
+	}
+	convertCLBBindingSpecToV1beta1(&src.Spec, &dst.Spec)
+	convertCLBBindingStatusToV1beta1(&src.ObjectMeta, &src.Status, &dst.Status, recorded)
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this version.
+func (dst *CLBNodeBinding) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.CLBNodeBinding)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	convertCLBBindingSpecFromV1beta1(&src.Spec, &dst.Spec)
+	return convertCLBBindingStatusFromV1beta1(&src.Status, &dst.ObjectMeta, &dst.Status)
+}
+
+// bindingStatePhases 记录 v1alpha1 中每个 state 对应的 v1beta1 phase，不在其中的 state 作为 phase 原样转换
+var bindingStatePhases = map[CLBBindingState]v1beta1.CLBBindingPhase{
+	CLBBindingStatePending:                v1beta1.CLBBindingPhasePending,
+	CLBBindingStateNoBackend:              v1beta1.CLBBindingPhasePending,
+	CLBBindingStateWaitBackend:            v1beta1.CLBBindingPhasePending,
+	CLBBindingStateNodeTypeNotSupported:   v1beta1.CLBBindingPhasePending,
+	CLBBindingStatePortPoolNotFound:       v1beta1.CLBBindingPhasePending,
+	CLBBindingStateNoPortAvailable:        v1beta1.CLBBindingPhasePending,
+	CLBBindingStatePortPoolNotAllocatable: v1beta1.CLBBindingPhasePending,
+	CLBBindingStateAllocated:              v1beta1.CLBBindingPhaseAllocated,
+	CLBBindingStateWaitBackendReady:       v1beta1.CLBBindingPhaseAllocated,
+	CLBBindingStateBound:                  v1beta1.CLBBindingPhaseBound,
+	CLBBindingStateDisabled:               v1beta1.CLBBindingPhaseDisabled,
+	CLBBindingStateDeleting:               v1beta1.CLBBindingPhaseDeleting,
+	CLBBindingStateFailed:                 v1beta1.CLBBindingPhaseFailed,
+}
+
+func bindingPhase(state CLBBindingState) v1beta1.CLBBindingPhase {
+	if phase, ok := bindingStatePhases[state]; ok {
+		return phase
+	}
+	return v1beta1.CLBBindingPhase(state)
+}
+
+func bindingStateFromV1beta1(phase v1beta1.CLBBindingPhase, conditions []metav1.Condition) (CLBBindingState, string) {
+	ready := meta.FindStatusCondition(conditions, v1beta1.CLBBindingConditionReady)
+	if ready == nil {
+		return CLBBindingState(phase), ""
+	}
+	if state := CLBBindingState(ready.Reason); bindingPhase(state) == phase {
+		return state, ready.Message
+	}
+	return CLBBindingState(phase), ready.Message
+}
+
+func convertCLBBindingStatusToV1beta1(objMeta *metav1.ObjectMeta, in *CLBBindingStatus, out *v1beta1.CLBBindingStatus, recorded *v1beta1Status) {
+	out.Phase = bindingPhase(in.State)
+	out.PortBindings = nil
+	for _, pb := range in.PortBindings {
+		out.PortBindings = append(out.PortBindings, v1beta1.PortBindingStatus{
+			Port:                pb.Port,
+			Protocol:            pb.Protocol,
+			CertId:              pb.CertId,
+			ExtraCertIds:        pb.ExtraCertIds,
+			CACertId:            pb.CACertId,
+			Pool:                pb.Pool,
+			Region:              pb.Region,
+			LoadBalancerId:      pb.LoadbalancerId,
+			LoadBalancerPort:    pb.LoadbalancerPort,
+			LoadBalancerEndPort: pb.LoadbalancerEndPort,
+			ListenerId:          pb.ListenerId,
+			AddressIPVersion:    pb.AddressIPVersion,
+			Domain:              pb.Domain,
+			Url:                 pb.Url,
+			LocationId:          pb.LocationId,
+			OriginalWeight:      pb.OriginalWeight,
+		})
+	}
+	out.Conditions = bindingConditions(objMeta, in, recorded)
+}
+
+func convertCLBBindingStatusFromV1beta1(in *v1beta1.CLBBindingStatus, objMeta *metav1.ObjectMeta, out *CLBBindingStatus) error {
+	out.State, out.Message = bindingStateFromV1beta1(in.Phase, in.Conditions)
+	out.PortBindings = nil
+	for _, pb := range in.PortBindings {
+		out.PortBindings = append(out.PortBindings, PortBindingStatus{
+			Port:                pb.Port,
+			Protocol:            pb.Protocol,
+			CertId:              pb.CertId,
+			ExtraCertIds:        pb.ExtraCertIds,
+			CACertId:            pb.CACertId,
+			Pool:                pb.Pool,
+			Region:              pb.Region,
+			LoadbalancerId:      pb.LoadBalancerId,
+			LoadbalancerPort:    pb.LoadBalancerPort,
+			LoadbalancerEndPort: pb.LoadBalancerEndPort,
+			ListenerId:          pb.ListenerId,
+			AddressIPVersion:    pb.AddressIPVersion,
+			Domain:              pb.Domain,
+			Url:                 pb.Url,
+			LocationId:          pb.LocationId,
+			OriginalWeight:      pb.OriginalWeight,
+		})
+	}
+	recorded := &v1beta1Status{Phase: string(in.Phase), Conditions: in.Conditions}
+	return pushStatus(objMeta, recorded, bindingConditions(objMeta, out, nil))
+}
+
+func bindingConditions(objMeta *metav1.ObjectMeta, status *CLBBindingStatus, recorded *v1beta1Status) []metav1.Condition {
+	if recorded != nil && recorded.Phase == string(bindingPhase(status.State)) {
+		// state 和 message 没有被 v1alpha1 的客户端修改过，原样还原
+		if state, message := bindingStateFromV1beta1(v1beta1.CLBBindingPhase(recorded.Phase), recorded.Conditions); state == status.State && message == status.Message {
+			return recorded.Conditions
+		}
+	}
+	var conditions []metav1.Condition
+	if recorded != nil {
+		conditions = recorded.Conditions
+	}
+	if status.State == "" && status.Message == "" {
+		return conditions
+	}
+	readyStatus := metav1.ConditionFalse
+	reason := string(status.State)
+	switch status.State {
+	case CLBBindingStateBound:
+		readyStatus = metav1.ConditionTrue
+	case "":
+		// condition 的 reason 不能为空
+		readyStatus = metav1.ConditionUnknown
+		reason = string(metav1.ConditionUnknown)
+	}
+	return setReadyCondition(objMeta, conditions, v1beta1.CLBBindingConditionReady, readyStatus, reason, status.Message)
+}
+
+// setReadyCondition 设置 Ready condition。没有其它 conditions 时（对象由 v1alpha1 创建），
+// 使用对象的创建时间作为 lastTransitionTime，保证同一个 v1alpha1 对象多次转换的结果一致。
+func setReadyCondition(objMeta *metav1.ObjectMeta, conditions []metav1.Condition, conditionType string, status metav1.ConditionStatus, reason, message string) []metav1.Condition {
+	cond := metav1.Condition{
+		Type:    conditionType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}
+	if len(conditions) == 0 {
+		cond.LastTransitionTime = objMeta.CreationTimestamp
+		return []metav1.Condition{cond}
+	}
+	conditions = append([]metav1.Condition(nil), conditions...)
+	meta.SetStatusCondition(&conditions, cond)
+	return conditions
+}
+
+// popStatus 将 v1alpha1 对象的 metadata 复制到 v1beta1 对象，并取出注解中记录的 v1beta1 status
+func popStatus(src, dst *metav1.ObjectMeta) (*v1beta1Status, error) {
+	*dst = *src.DeepCopy()
+	data, ok := dst.Annotations[v1beta1StatusAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	delete(dst.Annotations, v1beta1StatusAnnotation)
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+	recorded := &v1beta1Status{}
+	if err := json.Unmarshal([]byte(data), recorded); err != nil {
+		return nil, err
+	}
+	return recorded, nil
+}
+
+// pushStatus 如果从 v1alpha1 字段推导出的 conditions 与 v1beta1 的不一致，将 v1beta1 的 status 记录到注解中
+func pushStatus(dst *metav1.ObjectMeta, recorded *v1beta1Status, derived []metav1.Condition) error {
+	if equality.Semantic.DeepEqual(recorded.Conditions, derived) {
+		return nil
+	}
+	data, err := json.Marshal(recorded)
+	if err != nil {
+		return err
+	}
+	if dst.Annotations == nil {
+		dst.Annotations = make(map[string]string)
+	}
+	dst.Annotations[v1beta1StatusAnnotation] = string(data)
+	return nil
+}
+
+func convertHealthCheckToV1beta1(in *HealthCheck) *v1beta1.HealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := v1beta1.HealthCheck(*in)
+	return &out
+}
+
+func convertHealthCheckFromV1beta1(in *v1beta1.HealthCheck) *HealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := HealthCheck(*in)
+	return &out
+}
+
+func convertCLBBindingSpecToV1beta1(in *CLBBindingSpec, out *v1beta1.CLBBindingSpec) {
+	out.Disabled = in.Disabled
+	out.DrainSeconds = in.DrainSeconds
+	out.WaitBackendReady = in.WaitBackendReady
+	out.ReadinessPortName = in.ReadinessPortName
+	out.Ports = nil
+	for _, port := range in.Ports {
+		out.Ports = append(out.Ports, v1beta1.PortEntry{
+			Port:                   port.Port,
+			Protocol:               port.Protocol,
+			Pools:                  port.Pools,
+			UseSamePortAcrossPools: port.UseSamePortAcrossPools,
+			CertSecretName:         port.CertSecretName,
+			ExtraCertSecretNames:   port.ExtraCertSecretNames,
+			CACertSecretName:       port.CACertSecretName,
+			HealthCheck:            convertHealthCheckToV1beta1(port.HealthCheck),
+		})
+	}
+}
+
+func convertCLBBindingSpecFromV1beta1(in *v1beta1.CLBBindingSpec, out *CLBBindingSpec) {
+	out.Disabled = in.Disabled
+	out.DrainSeconds = in.DrainSeconds
+	out.WaitBackendReady = in.WaitBackendReady
+	out.ReadinessPortName = in.ReadinessPortName
+	out.Ports = nil
+	for _, port := range in.Ports {
+		out.Ports = append(out.Ports, PortEntry{
+			Port:                   port.Port,
+			Protocol:               port.Protocol,
+			Pools:                  port.Pools,
+			UseSamePortAcrossPools: port.UseSamePortAcrossPools,
+			CertSecretName:         port.CertSecretName,
+			ExtraCertSecretNames:   port.ExtraCertSecretNames,
+			CACertSecretName:       port.CACertSecretName,
+			HealthCheck:            convertHealthCheckFromV1beta1(port.HealthCheck),
+		})
+	}
+}
+
+func convertCLBPortPoolSpecToV1beta1(in *CLBPortPoolSpec, out *v1beta1.CLBPortPoolSpec) {
+	out.StartPort = in.StartPort
+	out.EndPort = in.EndPort
+	out.ListenerQuota = in.ListenerQuota
+	out.ListenerPrecreate = nil
+	if in.ListenerPrecreate != nil {
+		lp := v1beta1.ListenerPrecreateConfig(*in.ListenerPrecreate)
+		out.ListenerPrecreate = &lp
+	}
+	out.SegmentLength = in.SegmentLength
+	out.Region = in.Region
+	out.LbPolicy = in.LbPolicy
+	out.LbBlacklist = in.LbBlacklist
+	out.ExistingLoadBalancerIDs = in.ExsistedLoadBalancerIDs
+	out.AutoCreate = nil
+	if in.AutoCreate != nil {
+		out.AutoCreate = &v1beta1.AutoCreateConfig{
+			Enabled:          in.AutoCreate.Enabled,
+			MaxLoadBalancers: in.AutoCreate.MaxLoadBalancers,
+		}
+		if p := in.AutoCreate.Parameters; p != nil {
+			params := &v1beta1.CreateLBParameters{
+				VipIsp:                   p.VipIsp,
+				BandwidthPackageId:       p.BandwidthPackageId,
+				AddressIPVersion:         p.AddressIPVersion,
+				LoadBalancerPassToTarget: p.LoadBalancerPassToTarget,
+				SecurityGroups:           p.SecurityGroups,
+				SyncSecurityGroupRules:   p.SyncSecurityGroupRules,
+				DynamicVip:               p.DynamicVip,
+				VpcId:                    p.VpcId,
+				Vip:                      p.Vip,
+				ProjectId:                p.ProjectId,
+				LoadBalancerName:         p.LoadBalancerName,
+				LoadBalancerType:         p.LoadBalancerType,
+				MasterZoneId:             p.MasterZoneId,
+				ZoneId:                   p.ZoneId,
+				SubnetId:                 p.SubnetId,
+				SlaType:                  p.SlaType,
+				LBChargeType:             p.LBChargeType,
+			}
+			for _, tag := range p.Tags {
+				params.Tags = append(params.Tags, v1beta1.TagInfo(tag))
+			}
+			if p.InternetAccessible != nil {
+				ia := v1beta1.InternetAccessible(*p.InternetAccessible)
+				params.InternetAccessible = &ia
+			}
+			out.AutoCreate.Parameters = params
+		}
+	}
+	out.ListenerTemplate = nil
+	if t := in.ListenerTemplate; t != nil {
+		out.ListenerTemplate = &v1beta1.ListenerTemplate{
+			HealthCheck:         convertHealthCheckToV1beta1(t.HealthCheck),
+			Scheduler:           t.Scheduler,
+			SessionExpireTime:   t.SessionExpireTime,
+			SessionType:         t.SessionType,
+			IdleConnectTimeout:  t.IdleConnectTimeout,
+			DeregisterTargetRst: t.DeregisterTargetRst,
+		}
+	}
+	out.DrainSeconds = in.DrainSeconds
+	out.WaitBackendReady = in.WaitBackendReady
+	out.Layer7 = nil
+	if in.Layer7 != nil {
+		l7 := v1beta1.Layer7Config(*in.Layer7)
+		out.Layer7 = &l7
+	}
+}
+
+func convertCLBPortPoolSpecFromV1beta1(in *v1beta1.CLBPortPoolSpec, out *CLBPortPoolSpec) {
+	out.StartPort = in.StartPort
+	out.EndPort = in.EndPort
+	out.ListenerQuota = in.ListenerQuota
+	out.ListenerPrecreate = nil
+	if in.ListenerPrecreate != nil {
+		lp := ListenerPrecreateConfig(*in.ListenerPrecreate)
+		out.ListenerPrecreate = &lp
+	}
+	out.SegmentLength = in.SegmentLength
+	out.Region = in.Region
+	out.LbPolicy = in.LbPolicy
+	out.LbBlacklist = in.LbBlacklist
+	out.ExsistedLoadBalancerIDs = in.ExistingLoadBalancerIDs
+	out.AutoCreate = nil
+	if in.AutoCreate != nil {
+		out.AutoCreate = &AutoCreateConfig{
+			Enabled:          in.AutoCreate.Enabled,
+			MaxLoadBalancers: in.AutoCreate.MaxLoadBalancers,
+		}
+		if p := in.AutoCreate.Parameters; p != nil {
+			params := &CreateLBParameters{
+				VipIsp:                   p.VipIsp,
+				BandwidthPackageId:       p.BandwidthPackageId,
+				AddressIPVersion:         p.AddressIPVersion,
+				LoadBalancerPassToTarget: p.LoadBalancerPassToTarget,
+				SecurityGroups:           p.SecurityGroups,
+				SyncSecurityGroupRules:   p.SyncSecurityGroupRules,
+				DynamicVip:               p.DynamicVip,
+				VpcId:                    p.VpcId,
+				Vip:                      p.Vip,
+				ProjectId:                p.ProjectId,
+				LoadBalancerName:         p.LoadBalancerName,
+				LoadBalancerType:         p.LoadBalancerType,
+				MasterZoneId:             p.MasterZoneId,
+				ZoneId:                   p.ZoneId,
+				SubnetId:                 p.SubnetId,
+				SlaType:                  p.SlaType,
+				LBChargeType:             p.LBChargeType,
+			}
+			for _, tag := range p.Tags {
+				params.Tags = append(params.Tags, TagInfo(tag))
+			}
+			if p.InternetAccessible != nil {
+				ia := InternetAccessible(*p.InternetAccessible)
+				params.InternetAccessible = &ia
+			}
+			out.AutoCreate.Parameters = params
+		}
+	}
+	out.ListenerTemplate = nil
+	if t := in.ListenerTemplate; t != nil {
+		out.ListenerTemplate = &ListenerTemplate{
+			HealthCheck:         convertHealthCheckFromV1beta1(t.HealthCheck),
+			Scheduler:           t.Scheduler,
+			SessionExpireTime:   t.SessionExpireTime,
+			SessionType:         t.SessionType,
+			IdleConnectTimeout:  t.IdleConnectTimeout,
+			DeregisterTargetRst: t.DeregisterTargetRst,
+		}
+	}
+	out.DrainSeconds = in.DrainSeconds
+	out.WaitBackendReady = in.WaitBackendReady
+	out.Layer7 = nil
+	if in.Layer7 != nil {
+		l7 := Layer7Config(*in.Layer7)
+		out.Layer7 = &l7
+	}
+}
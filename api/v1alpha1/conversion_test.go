@@ -0,0 +1,113 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tkestack/tke-extend-network-controller/api/v1beta1"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+)
+
+func TestCLBPodBindingConversionRoundTrip(t *testing.T) {
+	objMeta := metav1.ObjectMeta{
+		Namespace:         "default",
+		Name:              "test",
+		CreationTimestamp: metav1.NewTime(time.Unix(1700000000, 0)),
+		Annotations:       map[string]string{"foo": "bar"},
+	}
+	spec := CLBBindingSpec{
+		Ports: []PortEntry{{Port: 80, Protocol: "TCP", Pools: []string{"pool-a"}, HealthCheck: &HealthCheck{Enabled: true}}},
+	}
+	// v1alpha1 -> v1beta1 -> v1alpha1
+	for _, state := range []CLBBindingState{"", CLBBindingStateNoPortAvailable, CLBBindingStateWaitBackendReady, CLBBindingStateBound, "Unknown"} {
+		src := &CLBPodBinding{ObjectMeta: objMeta, Spec: spec}
+		src.Status.State = state
+		src.Status.Message = "msg"
+		src.Status.PortBindings = []PortBindingStatus{{Port: 80, Protocol: "TCP", Pool: "pool-a", LoadbalancerId: "lb-xxx", LoadbalancerPort: 30000, ListenerId: "lbl-xxx"}}
+		hub := &v1beta1.CLBPodBinding{}
+		if err := src.ConvertTo(hub); err != nil {
+			t.Fatal(err)
+		}
+		dst := &CLBPodBinding{}
+		if err := dst.ConvertFrom(hub); err != nil {
+			t.Fatal(err)
+		}
+		if !equality.Semantic.DeepEqual(src, dst) {
+			t.Errorf("state %q: round trip mismatch:\n%+v\n%+v", state, src, dst)
+		}
+	}
+
+	// v1beta1 -> v1alpha1 -> v1beta1，包含 v1alpha1 无法表达的 conditions
+	hub := &v1beta1.CLBPodBinding{ObjectMeta: objMeta}
+	hub.Status.Phase = v1beta1.CLBBindingPhasePending
+	hub.Status.Conditions = []metav1.Condition{
+		{Type: v1beta1.CLBBindingConditionReady, Status: metav1.ConditionFalse, Reason: "PortPoolNotFound", Message: "pool not found", LastTransitionTime: metav1.NewTime(time.Unix(1700000100, 0))},
+		{Type: "Synced", Status: metav1.ConditionTrue, Reason: "Synced", LastTransitionTime: metav1.NewTime(time.Unix(1700000200, 0))},
+	}
+	alpha := &CLBPodBinding{}
+	if err := alpha.ConvertFrom(hub); err != nil {
+		t.Fatal(err)
+	}
+	if alpha.Status.State != CLBBindingStatePortPoolNotFound || alpha.Status.Message != "pool not found" {
+		t.Errorf("unexpected v1alpha1 status: %+v", alpha.Status)
+	}
+	back := &v1beta1.CLBPodBinding{}
+	if err := alpha.ConvertTo(back); err != nil {
+		t.Fatal(err)
+	}
+	if !equality.Semantic.DeepEqual(hub, back) {
+		t.Errorf("round trip mismatch:\n%+v\n%+v", hub, back)
+	}
+
+	// v1alpha1 的客户端修改了 state 后，Ready condition 跟随更新，其它 conditions 保留
+	alpha.Status.State = CLBBindingStateBound
+	alpha.Status.Message = ""
+	if err := alpha.ConvertTo(back); err != nil {
+		t.Fatal(err)
+	}
+	ready := back.Status.Conditions[0]
+	if back.Status.Phase != v1beta1.CLBBindingPhaseBound || ready.Status != metav1.ConditionTrue || len(back.Status.Conditions) != 2 {
+		t.Errorf("unexpected v1beta1 status: %+v", back.Status)
+	}
+}
+
+func TestCLBPortPoolConversionRoundTrip(t *testing.T) {
+	src := &CLBPortPool{ObjectMeta: metav1.ObjectMeta{Name: "pool-a"}}
+	src.Spec.StartPort = 30000
+	src.Spec.ExsistedLoadBalancerIDs = []string{"lb-xxx"}
+	src.Spec.ListenerPrecreate = &ListenerPrecreateConfig{Enabled: true, TCP: util.GetPtr(uint16(10))}
+	src.Status.State = CLBPortPoolStateActive
+	src.Status.Quota = 50
+	src.Status.LoadbalancerStatuses = []LoadBalancerStatus{{LoadbalancerID: "lb-xxx", LoadbalancerName: "test", State: LoadBalancerStateRunning, Allocated: 1}}
+	src.Status.SecurityGroupRules = []string{"sg-xxx"}
+	hub := &v1beta1.CLBPortPool{}
+	if err := src.ConvertTo(hub); err != nil {
+		t.Fatal(err)
+	}
+	if hub.Status.Phase != v1beta1.CLBPortPoolPhaseActive || hub.Spec.ExistingLoadBalancerIDs[0] != "lb-xxx" || hub.Status.LoadBalancerStatuses[0].LoadBalancerID != "lb-xxx" {
+		t.Errorf("unexpected v1beta1 object: %+v", hub)
+	}
+	dst := &CLBPortPool{}
+	if err := dst.ConvertFrom(hub); err != nil {
+		t.Fatal(err)
+	}
+	if !equality.Semantic.DeepEqual(src, dst) {
+		t.Errorf("round trip mismatch:\n%+v\n%+v", src, dst)
+	}
+
+	// Ready condition 使用了与 phase 不同的 reason
+	hub.Status.Conditions = []metav1.Condition{{Type: v1beta1.CLBPortPoolConditionReady, Status: metav1.ConditionTrue, Reason: "QuotaAvailable"}}
+	if err := dst.ConvertFrom(hub); err != nil {
+		t.Fatal(err)
+	}
+	back := &v1beta1.CLBPortPool{}
+	if err := dst.ConvertTo(back); err != nil {
+		t.Fatal(err)
+	}
+	if !equality.Semantic.DeepEqual(hub, back) {
+		t.Errorf("round trip mismatch:\n%+v\n%+v", hub, back)
+	}
+}
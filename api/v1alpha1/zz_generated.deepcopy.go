@@ -24,6 +24,36 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AllocatableCLBInfo) DeepCopyInto(out *AllocatableCLBInfo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AllocatableCLBInfo.
+func (in *AllocatableCLBInfo) DeepCopy() *AllocatableCLBInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(AllocatableCLBInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AllocatedCLBInfo) DeepCopyInto(out *AllocatedCLBInfo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AllocatedCLBInfo.
+func (in *AllocatedCLBInfo) DeepCopy() *AllocatedCLBInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(AllocatedCLBInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AutoCreateConfig) DeepCopyInto(out *AutoCreateConfig) {
 	*out = *in
@@ -64,6 +94,21 @@ func (in *CLBBindingSpec) DeepCopyInto(out *CLBBindingSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DrainSeconds != nil {
+		in, out := &in.DrainSeconds, &out.DrainSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.WaitBackendReady != nil {
+		in, out := &in.WaitBackendReady, &out.WaitBackendReady
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ReadinessPortName != nil {
+		in, out := &in.ReadinessPortName, &out.ReadinessPortName
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CLBBindingSpec.
@@ -98,6 +143,152 @@ func (in *CLBBindingStatus) DeepCopy() *CLBBindingStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CLBListener) DeepCopyInto(out *CLBListener) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CLBListener.
+func (in *CLBListener) DeepCopy() *CLBListener {
+	if in == nil {
+		return nil
+	}
+	out := new(CLBListener)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CLBListener) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CLBListenerBackend) DeepCopyInto(out *CLBListenerBackend) {
+	*out = *in
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CLBListenerBackend.
+func (in *CLBListenerBackend) DeepCopy() *CLBListenerBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(CLBListenerBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CLBListenerList) DeepCopyInto(out *CLBListenerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CLBListener, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CLBListenerList.
+func (in *CLBListenerList) DeepCopy() *CLBListenerList {
+	if in == nil {
+		return nil
+	}
+	out := new(CLBListenerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CLBListenerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CLBListenerSpec) DeepCopyInto(out *CLBListenerSpec) {
+	*out = *in
+	if in.EndPort != nil {
+		in, out := &in.EndPort, &out.EndPort
+		*out = new(uint16)
+		**out = **in
+	}
+	if in.CertId != nil {
+		in, out := &in.CertId, &out.CertId
+		*out = new(string)
+		**out = **in
+	}
+	if in.ExtraCertIds != nil {
+		in, out := &in.ExtraCertIds, &out.ExtraCertIds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CACertId != nil {
+		in, out := &in.CACertId, &out.CACertId
+		*out = new(string)
+		**out = **in
+	}
+	if in.ListenerName != nil {
+		in, out := &in.ListenerName, &out.ListenerName
+		*out = new(string)
+		**out = **in
+	}
+	if in.ListenerTemplate != nil {
+		in, out := &in.ListenerTemplate, &out.ListenerTemplate
+		*out = new(ListenerTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]CLBListenerBackend, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CLBListenerSpec.
+func (in *CLBListenerSpec) DeepCopy() *CLBListenerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CLBListenerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CLBListenerStatus) DeepCopyInto(out *CLBListenerStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CLBListenerStatus.
+func (in *CLBListenerStatus) DeepCopy() *CLBListenerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CLBListenerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CLBNodeBinding) DeepCopyInto(out *CLBNodeBinding) {
 	*out = *in
@@ -350,6 +541,26 @@ func (in *CLBPortPoolSpec) DeepCopyInto(out *CLBPortPoolSpec) {
 		*out = new(AutoCreateConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ListenerTemplate != nil {
+		in, out := &in.ListenerTemplate, &out.ListenerTemplate
+		*out = new(ListenerTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DrainSeconds != nil {
+		in, out := &in.DrainSeconds, &out.DrainSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.WaitBackendReady != nil {
+		in, out := &in.WaitBackendReady, &out.WaitBackendReady
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Layer7 != nil {
+		in, out := &in.Layer7, &out.Layer7
+		*out = new(Layer7Config)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CLBPortPoolSpec.
@@ -377,6 +588,16 @@ func (in *CLBPortPoolStatus) DeepCopyInto(out *CLBPortPoolStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SecurityGroups != nil {
+		in, out := &in.SecurityGroups, &out.SecurityGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecurityGroupRules != nil {
+		in, out := &in.SecurityGroupRules, &out.SecurityGroupRules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CLBPortPoolStatus.
@@ -389,6 +610,117 @@ func (in *CLBPortPoolStatus) DeepCopy() *CLBPortPoolStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CLBSharedBinding) DeepCopyInto(out *CLBSharedBinding) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CLBSharedBinding.
+func (in *CLBSharedBinding) DeepCopy() *CLBSharedBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(CLBSharedBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CLBSharedBinding) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CLBSharedBindingList) DeepCopyInto(out *CLBSharedBindingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CLBSharedBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CLBSharedBindingList.
+func (in *CLBSharedBindingList) DeepCopy() *CLBSharedBindingList {
+	if in == nil {
+		return nil
+	}
+	out := new(CLBSharedBindingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CLBSharedBindingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CLBSharedBindingSpec) DeepCopyInto(out *CLBSharedBindingSpec) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int64)
+		**out = **in
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(HealthCheck)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CLBSharedBindingSpec.
+func (in *CLBSharedBindingSpec) DeepCopy() *CLBSharedBindingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CLBSharedBindingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CLBSharedBindingStatus) DeepCopyInto(out *CLBSharedBindingStatus) {
+	*out = *in
+	if in.PortBinding != nil {
+		in, out := &in.PortBinding, &out.PortBinding
+		*out = new(PortBindingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CLBSharedBindingStatus.
+func (in *CLBSharedBindingStatus) DeepCopy() *CLBSharedBindingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CLBSharedBindingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CreateLBParameters) DeepCopyInto(out *CreateLBParameters) {
 	*out = *in
@@ -412,6 +744,16 @@ func (in *CreateLBParameters) DeepCopyInto(out *CreateLBParameters) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.SecurityGroups != nil {
+		in, out := &in.SecurityGroups, &out.SecurityGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SyncSecurityGroupRules != nil {
+		in, out := &in.SyncSecurityGroupRules, &out.SyncSecurityGroupRules
+		*out = new(bool)
+		**out = **in
+	}
 	if in.DynamicVip != nil {
 		in, out := &in.DynamicVip, &out.DynamicVip
 		*out = new(bool)
@@ -490,17 +832,313 @@ func (in *CreateLBParameters) DeepCopy() *CreateLBParameters {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InternetAccessible) DeepCopyInto(out *InternetAccessible) {
+func (in *DedicatedCLBListener) DeepCopyInto(out *DedicatedCLBListener) {
 	*out = *in
-	if in.InternetChargeType != nil {
-		in, out := &in.InternetChargeType, &out.InternetChargeType
-		*out = new(string)
-		**out = **in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DedicatedCLBListener.
+func (in *DedicatedCLBListener) DeepCopy() *DedicatedCLBListener {
+	if in == nil {
+		return nil
 	}
-	if in.InternetMaxBandwidthOut != nil {
-		in, out := &in.InternetMaxBandwidthOut, &out.InternetMaxBandwidthOut
-		*out = new(int64)
-		**out = **in
+	out := new(DedicatedCLBListener)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DedicatedCLBListener) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DedicatedCLBListenerList) DeepCopyInto(out *DedicatedCLBListenerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DedicatedCLBListener, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DedicatedCLBListenerList.
+func (in *DedicatedCLBListenerList) DeepCopy() *DedicatedCLBListenerList {
+	if in == nil {
+		return nil
+	}
+	out := new(DedicatedCLBListenerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DedicatedCLBListenerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DedicatedCLBListenerSpec) DeepCopyInto(out *DedicatedCLBListenerSpec) {
+	*out = *in
+	if in.LbEndPort != nil {
+		in, out := &in.LbEndPort, &out.LbEndPort
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TargetPod != nil {
+		in, out := &in.TargetPod, &out.TargetPod
+		*out = new(TargetPod)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DedicatedCLBListenerSpec.
+func (in *DedicatedCLBListenerSpec) DeepCopy() *DedicatedCLBListenerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DedicatedCLBListenerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DedicatedCLBListenerStatus) DeepCopyInto(out *DedicatedCLBListenerStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DedicatedCLBListenerStatus.
+func (in *DedicatedCLBListenerStatus) DeepCopy() *DedicatedCLBListenerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DedicatedCLBListenerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DedicatedCLBService) DeepCopyInto(out *DedicatedCLBService) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DedicatedCLBService.
+func (in *DedicatedCLBService) DeepCopy() *DedicatedCLBService {
+	if in == nil {
+		return nil
+	}
+	out := new(DedicatedCLBService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DedicatedCLBService) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DedicatedCLBServiceList) DeepCopyInto(out *DedicatedCLBServiceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DedicatedCLBService, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DedicatedCLBServiceList.
+func (in *DedicatedCLBServiceList) DeepCopy() *DedicatedCLBServiceList {
+	if in == nil {
+		return nil
+	}
+	out := new(DedicatedCLBServiceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DedicatedCLBServiceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DedicatedCLBServicePort) DeepCopyInto(out *DedicatedCLBServicePort) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DedicatedCLBServicePort.
+func (in *DedicatedCLBServicePort) DeepCopy() *DedicatedCLBServicePort {
+	if in == nil {
+		return nil
+	}
+	out := new(DedicatedCLBServicePort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DedicatedCLBServiceSpec) DeepCopyInto(out *DedicatedCLBServiceSpec) {
+	*out = *in
+	if in.MaxPod != nil {
+		in, out := &in.MaxPod, &out.MaxPod
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]DedicatedCLBServicePort, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExistedLbIds != nil {
+		in, out := &in.ExistedLbIds, &out.ExistedLbIds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.LbAutoCreate = in.LbAutoCreate
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DedicatedCLBServiceSpec.
+func (in *DedicatedCLBServiceSpec) DeepCopy() *DedicatedCLBServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DedicatedCLBServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DedicatedCLBServiceStatus) DeepCopyInto(out *DedicatedCLBServiceStatus) {
+	*out = *in
+	if in.AllocatableLb != nil {
+		in, out := &in.AllocatableLb, &out.AllocatableLb
+		*out = make([]AllocatableCLBInfo, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllocatedLb != nil {
+		in, out := &in.AllocatedLb, &out.AllocatedLb
+		*out = make([]AllocatedCLBInfo, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DedicatedCLBServiceStatus.
+func (in *DedicatedCLBServiceStatus) DeepCopy() *DedicatedCLBServiceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DedicatedCLBServiceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthCheck) DeepCopyInto(out *HealthCheck) {
+	*out = *in
+	if in.TimeOut != nil {
+		in, out := &in.TimeOut, &out.TimeOut
+		*out = new(int64)
+		**out = **in
+	}
+	if in.IntervalTime != nil {
+		in, out := &in.IntervalTime, &out.IntervalTime
+		*out = new(int64)
+		**out = **in
+	}
+	if in.HealthNum != nil {
+		in, out := &in.HealthNum, &out.HealthNum
+		*out = new(int64)
+		**out = **in
+	}
+	if in.UnHealthNum != nil {
+		in, out := &in.UnHealthNum, &out.UnHealthNum
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CheckPort != nil {
+		in, out := &in.CheckPort, &out.CheckPort
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CheckType != nil {
+		in, out := &in.CheckType, &out.CheckType
+		*out = new(string)
+		**out = **in
+	}
+	if in.ContextType != nil {
+		in, out := &in.ContextType, &out.ContextType
+		*out = new(string)
+		**out = **in
+	}
+	if in.SendContext != nil {
+		in, out := &in.SendContext, &out.SendContext
+		*out = new(string)
+		**out = **in
+	}
+	if in.RecvContext != nil {
+		in, out := &in.RecvContext, &out.RecvContext
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheck.
+func (in *HealthCheck) DeepCopy() *HealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternetAccessible) DeepCopyInto(out *InternetAccessible) {
+	*out = *in
+	if in.InternetChargeType != nil {
+		in, out := &in.InternetChargeType, &out.InternetChargeType
+		*out = new(string)
+		**out = **in
+	}
+	if in.InternetMaxBandwidthOut != nil {
+		in, out := &in.InternetMaxBandwidthOut, &out.InternetMaxBandwidthOut
+		*out = new(int64)
+		**out = **in
 	}
 	if in.BandwidthpkgSubType != nil {
 		in, out := &in.BandwidthpkgSubType, &out.BandwidthpkgSubType
@@ -519,6 +1157,56 @@ func (in *InternetAccessible) DeepCopy() *InternetAccessible {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LbAutoCreate) DeepCopyInto(out *LbAutoCreate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LbAutoCreate.
+func (in *LbAutoCreate) DeepCopy() *LbAutoCreate {
+	if in == nil {
+		return nil
+	}
+	out := new(LbAutoCreate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Layer7Config) DeepCopyInto(out *Layer7Config) {
+	*out = *in
+	if in.Url != nil {
+		in, out := &in.Url, &out.Url
+		*out = new(string)
+		**out = **in
+	}
+	if in.HTTPPort != nil {
+		in, out := &in.HTTPPort, &out.HTTPPort
+		*out = new(uint16)
+		**out = **in
+	}
+	if in.HTTPSPort != nil {
+		in, out := &in.HTTPSPort, &out.HTTPSPort
+		*out = new(uint16)
+		**out = **in
+	}
+	if in.CertId != nil {
+		in, out := &in.CertId, &out.CertId
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Layer7Config.
+func (in *Layer7Config) DeepCopy() *Layer7Config {
+	if in == nil {
+		return nil
+	}
+	out := new(Layer7Config)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ListenerPrecreateConfig) DeepCopyInto(out *ListenerPrecreateConfig) {
 	*out = *in
@@ -544,6 +1232,51 @@ func (in *ListenerPrecreateConfig) DeepCopy() *ListenerPrecreateConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ListenerTemplate) DeepCopyInto(out *ListenerTemplate) {
+	*out = *in
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(HealthCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scheduler != nil {
+		in, out := &in.Scheduler, &out.Scheduler
+		*out = new(string)
+		**out = **in
+	}
+	if in.SessionExpireTime != nil {
+		in, out := &in.SessionExpireTime, &out.SessionExpireTime
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SessionType != nil {
+		in, out := &in.SessionType, &out.SessionType
+		*out = new(string)
+		**out = **in
+	}
+	if in.IdleConnectTimeout != nil {
+		in, out := &in.IdleConnectTimeout, &out.IdleConnectTimeout
+		*out = new(int64)
+		**out = **in
+	}
+	if in.DeregisterTargetRst != nil {
+		in, out := &in.DeregisterTargetRst, &out.DeregisterTargetRst
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ListenerTemplate.
+func (in *ListenerTemplate) DeepCopy() *ListenerTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ListenerTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoadBalancerStatus) DeepCopyInto(out *LoadBalancerStatus) {
 	*out = *in
@@ -587,6 +1320,16 @@ func (in *PortBindingStatus) DeepCopyInto(out *PortBindingStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ExtraCertIds != nil {
+		in, out := &in.ExtraCertIds, &out.ExtraCertIds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CACertId != nil {
+		in, out := &in.CACertId, &out.CACertId
+		*out = new(string)
+		**out = **in
+	}
 	if in.LoadbalancerEndPort != nil {
 		in, out := &in.LoadbalancerEndPort, &out.LoadbalancerEndPort
 		*out = new(uint16)
@@ -597,6 +1340,26 @@ func (in *PortBindingStatus) DeepCopyInto(out *PortBindingStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Domain != nil {
+		in, out := &in.Domain, &out.Domain
+		*out = new(string)
+		**out = **in
+	}
+	if in.Url != nil {
+		in, out := &in.Url, &out.Url
+		*out = new(string)
+		**out = **in
+	}
+	if in.LocationId != nil {
+		in, out := &in.LocationId, &out.LocationId
+		*out = new(string)
+		**out = **in
+	}
+	if in.OriginalWeight != nil {
+		in, out := &in.OriginalWeight, &out.OriginalWeight
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PortBindingStatus.
@@ -627,6 +1390,21 @@ func (in *PortEntry) DeepCopyInto(out *PortEntry) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ExtraCertSecretNames != nil {
+		in, out := &in.ExtraCertSecretNames, &out.ExtraCertSecretNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CACertSecretName != nil {
+		in, out := &in.CACertSecretName, &out.CACertSecretName
+		*out = new(string)
+		**out = **in
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(HealthCheck)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PortEntry.
@@ -653,3 +1431,18 @@ func (in *TagInfo) DeepCopy() *TagInfo {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetPod) DeepCopyInto(out *TargetPod) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetPod.
+func (in *TargetPod) DeepCopy() *TargetPod {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetPod)
+	in.DeepCopyInto(out)
+	return out
+}
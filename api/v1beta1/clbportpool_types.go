@@ -0,0 +1,414 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CLBPortPoolSpec defines the desired state of CLBPortPool.
+type CLBPortPoolSpec struct {
+	// 端口池的起始端口号
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="Value is immutable"
+	StartPort uint16 `json:"startPort"`
+	// 端口池的结束端口号
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="Value is immutable"
+	EndPort *uint16 `json:"endPort,omitempty"`
+	// 监听器数量配额。仅用在单独调整了指定 CLB 实例监听器数量配额的场景（TOTAL_LISTENER_QUOTA），
+	// 控制器默认会获取账号维度的监听器数量配额作为端口分配的依据，如果 listenerQuota 不为空，
+	// 将以它的值作为该端口池中所有 CLB 监听器数量配额覆盖账号维度的监听器数量配额。
+	//
+	// 注意：如果指定了 listenerQuota，不支持启用 CLB 自动创建，且需自行保证该端口池中所有 CLB
+	// 实例的监听器数量配额均等于 listenerQuota 的值。
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="Value is immutable"
+	ListenerQuota *uint16 `json:"listenerQuota,omitempty"`
+	// 监听器预创建，预先为 lb 创建一些固定的监听器，不销毁，用于加快扩缩容时 CLB 的绑定和解绑速度。
+	//
+	// 注意：预创建监听器的端口池只支持 TCP 和 UDP 协议（不支持 TCP_SSL 和 QUIC）。
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="Value is immutable"
+	ListenerPrecreate *ListenerPrecreateConfig `json:"listenerPrecreate,omitempty"`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="Value is immutable"
+	// 端口段的长度
+	SegmentLength *uint16 `json:"segmentLength,omitempty"`
+	// 地域代码，如ap-chengdu
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="Value is immutable"
+	// +optional
+	Region *string `json:"region,omitempty"`
+	// CLB 分配策略，单个端口池中有多个可分配 CLB ，分配端口时 CLB 的挑选策略。
+	// 可选值：Uniform（均匀分配）、InOrder（顺序分配）、Random（随机分配）。默认值为 Random。
+	//
+	// 若希望减小 DDoS 攻击的影响，建议使用 Uniform 策略，避免业务使用的 IP 过于集中；若希望提高
+	// CLB 的利用率，建议使用 InOrder 策略。
+	// +kubebuilder:validation:Enum=Uniform;InOrder;Random
+	// +optional
+	LbPolicy *string `json:"lbPolicy,omitempty"`
+	// CLB 黑名单，负载均衡实例 ID 的数组，用于禁止某些 CLB 实例被分配端口，可动态追加和移除。
+	// 如果发现某个 CLB 被 DDoS 攻击或其他原因导致不可用，可将该 CLB 的实例 ID 加入到黑名单中，
+	// 避免后续端口分配使用该 CLB。
+	// +optional
+	LbBlacklist []string `json:"lbBlacklist,omitempty"`
+	// 已有负载均衡器实例 ID 列表，可动态追加。
+	// 该列表的负载均衡器将会被端口池用于分配端口映射。
+	// +optional
+	ExistingLoadBalancerIDs []string `json:"existingLoadBalancerIDs,omitempty"`
+	// 自动创建的配置，如果启用，则当端口池中负载均衡器可用监听器数量不足时会自动创建新的负载
+	// 均衡器来补充可分配监听器数量。
+	// +optional
+	AutoCreate *AutoCreateConfig `json:"autoCreate,omitempty"`
+	// 监听器模板，控制器为该端口池创建监听器时使用，修改后会同步到已绑定的监听器。
+	// +optional
+	ListenerTemplate *ListenerTemplate `json:"listenerTemplate,omitempty"`
+	// 摘流时间（秒）。解绑后端前先将其在 CLB 上的权重设为 0，等待摘流时间后再解绑，避免存量连接被立即中断。
+	// 默认为 0，即立即解绑。Pod/Node 可通过 networking.cloud.tencent.com/clb-drain-seconds 注解覆盖。
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=3600
+	// +optional
+	DrainSeconds *int64 `json:"drainSeconds,omitempty"`
+	// 是否等待后端（Pod/Node）就绪后再绑定到 CLB。启用后，端口会正常分配，但后端就绪后才会注册到监听器，
+	// 后端变为未就绪时会将其权重设为 0。Pod/Node 可通过 networking.cloud.tencent.com/clb-wait-backend-ready 注解覆盖。
+	// +optional
+	WaitBackendReady *bool `json:"waitBackendReady,omitempty"`
+	// 七层转发配置。端口映射使用 HTTP/HTTPS 协议时，不再为每个 Pod/Node 分配独立的监听器，而是在 CLB 的共享
+	// 七层监听器上为其创建一条转发规则（域名 + URL），从而用一个监听器承载大量 Pod，节省监听器配额。
+	// +optional
+	Layer7 *Layer7Config `json:"layer7,omitempty"`
+}
+
+// Layer7Config 定义七层转发规则的配置
+type Layer7Config struct {
+	// 转发规则的域名模板，支持用双花括号引用以下变量：pod 或 node（Pod 或 Node 的名称）、namespace（命名空间）、
+	// port（应用端口），用法见端口池文档。需保证渲染出的域名 + URL 不会重复，否则不同的 Pod/Node 会争抢同一条转发规则，
+	// 因此 domain 和 url 中必须引用 pod 和 namespace，或引用 node。
+	// +kubebuilder:validation:MinLength=1
+	Domain string `json:"domain"`
+	// 转发规则的 URL 模板，支持的变量与 domain 相同，默认为 /。
+	// +optional
+	Url *string `json:"url,omitempty"`
+	// HTTP 共享监听器的端口，默认为 80，不能在端口池的端口范围内。
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="Value is immutable"
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	HTTPPort *uint16 `json:"httpPort,omitempty"`
+	// HTTPS 共享监听器的端口，默认为 443，不能在端口池的端口范围内。
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="Value is immutable"
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	HTTPSPort *uint16 `json:"httpsPort,omitempty"`
+	// HTTPS 共享监听器的服务端证书 ID，使用 HTTPS 协议时必填，通常使用与域名模板匹配的泛域名证书。
+	// +optional
+	CertId *string `json:"certId,omitempty"`
+}
+
+// 七层共享监听器的默认端口
+const (
+	DefaultLayer7HTTPPort  uint16 = 80
+	DefaultLayer7HTTPSPort uint16 = 443
+)
+
+// GetListenerPort 获取指定七层协议的共享监听器端口
+func (c *Layer7Config) GetListenerPort(protocol string) uint16 {
+	if protocol == "HTTPS" {
+		if c.HTTPSPort != nil {
+			return *c.HTTPSPort
+		}
+		return DefaultLayer7HTTPSPort
+	}
+	if c.HTTPPort != nil {
+		return *c.HTTPPort
+	}
+	return DefaultLayer7HTTPPort
+}
+
+// AutoCreateConfig 定义自动创建 CLB 的配置
+type AutoCreateConfig struct {
+	// 是否启用自动创建
+	Enabled bool `json:"enabled"`
+	// 自动创建的最大负载均衡器数量
+	// +optional
+	MaxLoadBalancers *uint16 `json:"maxLoadBalancers,omitempty"`
+	// 自动创建参数
+	// +optional
+	Parameters *CreateLBParameters `json:"parameters,omitempty"`
+}
+
+// ListenerPrecreateConfig 定义监听器预创建配置
+type ListenerPrecreateConfig struct {
+	// 是否启用监听器预创建
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="Value is immutable"
+	Enabled bool `json:"enabled"`
+	// TCP 监听器预创建数量
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="Value is immutable"
+	TCP *uint16 `json:"tcp,omitempty"`
+	// UDP 监听器预创建数量
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="Value is immutable"
+	UDP *uint16 `json:"udp,omitempty"`
+}
+
+// ListenerTemplate 定义监听器模板，未指定的字段使用 CLB 的默认值，且不会对账已有监听器的对应配置。
+type ListenerTemplate struct {
+	// 健康检查配置，不指定时创建的监听器默认关闭健康检查。
+	// +optional
+	HealthCheck *HealthCheck `json:"healthCheck,omitempty"`
+	// 监听器转发的方式，可选值：WRR（按权重轮询）、LEAST_CONN（按最小连接数），CLB 默认为 WRR。
+	// +kubebuilder:validation:Enum=WRR;LEAST_CONN
+	// +optional
+	Scheduler *string `json:"scheduler,omitempty"`
+	// 会话保持时间，单位：秒。可选值：30~3600，0 表示不开启会话保持。仅对 TCP/UDP 监听器生效。
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=3600
+	// +optional
+	SessionExpireTime *int64 `json:"sessionExpireTime,omitempty"`
+	// 会话保持类型，可选值：NORMAL（默认会话保持类型）、QUIC_CID（根据 QUIC Connection ID 做会话保持，
+	// 只支持 UDP 协议，且 scheduler 必须为 WRR）。仅对 TCP/UDP 监听器生效。
+	// +kubebuilder:validation:Enum=NORMAL;QUIC_CID
+	// +optional
+	SessionType *string `json:"sessionType,omitempty"`
+	// 空闲连接超时时间，单位：秒。CLB 默认 TCP 监听器为 900，UDP 监听器为 300。共享型和独占型实例可选值：10~900，
+	// 性能容量型实例可选值：10~1980。仅对 TCP/UDP 监听器生效。
+	// +kubebuilder:validation:Minimum=10
+	// +kubebuilder:validation:Maximum=1980
+	// +optional
+	IdleConnectTimeout *int64 `json:"idleConnectTimeout,omitempty"`
+	// 解绑后端服务时是否向客户端发送 RST 并触发重新调度。仅对 TCP/UDP 监听器生效。
+	// +optional
+	DeregisterTargetRst *bool `json:"deregisterTargetRst,omitempty"`
+}
+
+// HealthCheck 定义监听器的健康检查配置，字段含义参考 CLB API 的 HealthCheck 结构：
+// https://cloud.tencent.com/document/api/214/30694#HealthCheck
+type HealthCheck struct {
+	// 是否开启健康检查
+	Enabled bool `json:"enabled"`
+	// 健康检查的响应超时时间，单位：秒，可选值：2~60，默认值：2。响应超时时间要小于检查间隔时间。
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Maximum=60
+	// +optional
+	TimeOut *int64 `json:"timeOut,omitempty"`
+	// 健康检查探测间隔时间，单位：秒，可选值：2~300，默认值：5。
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Maximum=300
+	// +optional
+	IntervalTime *int64 `json:"intervalTime,omitempty"`
+	// 健康阈值，表示连续检查成功多少次后认定后端服务健康，可选值：2~10，默认值：3。
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	HealthNum *int64 `json:"healthNum,omitempty"`
+	// 不健康阈值，表示连续检查失败多少次后认定后端服务不健康，可选值：2~10，默认值：3。
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	UnHealthNum *int64 `json:"unHealthNum,omitempty"`
+	// 自定义探测相关参数。健康检查端口，默认为后端服务的端口。
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	CheckPort *int64 `json:"checkPort,omitempty"`
+	// 健康检查使用的协议，TCP 监听器可选 TCP、CUSTOM，UDP 监听器可选 PING、CUSTOM，
+	// TCP_SSL 和 QUIC 监听器可选 TCP、CUSTOM。
+	// +kubebuilder:validation:Enum=TCP;PING;CUSTOM
+	// +optional
+	CheckType *string `json:"checkType,omitempty"`
+	// 自定义探测相关参数。健康检查协议 checkType 为 CUSTOM 时，输入格式，可选值：HEX 或 TEXT。
+	// +kubebuilder:validation:Enum=HEX;TEXT
+	// +optional
+	ContextType *string `json:"contextType,omitempty"`
+	// 自定义探测相关参数。健康检查协议 checkType 为 CUSTOM 时，健康检查发送的请求内容，最长 500 字符。
+	// +kubebuilder:validation:MaxLength=500
+	// +optional
+	SendContext *string `json:"sendContext,omitempty"`
+	// 自定义探测相关参数。健康检查协议 checkType 为 CUSTOM 时，健康检查返回的结果，最长 500 字符。
+	// +kubebuilder:validation:MaxLength=500
+	// +optional
+	RecvContext *string `json:"recvContext,omitempty"`
+}
+
+// CreateLBParameters 定义创建负载均衡器的参数
+type CreateLBParameters struct {
+	// 仅适用于公网负载均衡。目前仅广州、上海、南京、济南、杭州、福州、北京、石家庄、武汉、长沙、成都、重庆地域支持静态单线 IP 线路类型，如需体验，请联系商务经理申请。申请通过后，即可选择中国移动（CMCC）、中国联通（CUCC）或中国电信（CTCC）的运营商类型，网络计费模式只能使用按带宽包计费(BANDWIDTH_PACKAGE)。 如果不指定本参数，则默认使用BGP。可通过 DescribeResources 接口查询一个地域所支持的Isp。
+	// +kubebuilder:validation:Enum=CMCC;CUCC;CTCC;BGP
+	VipIsp *string `json:"vipIsp,omitempty"`
+	// 带宽包ID，指定此参数时，网络计费方式（InternetAccessible.InternetChargeType）只支持按带宽包计费（BANDWIDTH_PACKAGE），带宽包的属性即为其结算方式。非上移用户购买的 IPv6 负载均衡实例，且运营商类型非 BGP 时 ，不支持指定具体带宽包id。
+	BandwidthPackageId *string `json:"bandwidthPackageId,omitempty"`
+	// 仅适用于公网负载均衡。IP版本，可取值：IPV4、IPV6、IPv6FullChain，不区分大小写，默认值 IPV4。说明：取值为IPV6表示为IPV6 NAT64版本；取值为IPv6FullChain，表示为IPv6版本。
+	// +kubebuilder:validation:Enum=IPV4;IPV6;IPv6FullChain
+	AddressIPVersion *string `json:"addressIPVersion,omitempty"`
+	// Target是否放通来自CLB的流量。开启放通（true）：只验证CLB上的安全组；不开启放通（false）：需同时验证CLB和后端实例上的安全组。默认值为 true。
+	LoadBalancerPassToTarget *bool `json:"loadBalancerPassToTarget,omitempty"`
+	// 负载均衡实例绑定的安全组 ID 列表，最多 5 个。负载均衡创建后立即绑定，控制器会持续对账自动创建的负载均衡的安全组，
+	// 端口池中已有的负载均衡（非自动创建）的安全组与之不一致时只产生事件，不做修改。
+	// +kubebuilder:validation:MaxItems=5
+	SecurityGroups []string `json:"securityGroups,omitempty"`
+	// 是否在 securityGroups 的安全组中维护放通端口池端口范围（startPort 到 endPort）的 TCP 和 UDP 入站规则（来源 0.0.0.0/0），
+	// 规则描述中记录端口池名称，端口范围变化时原地更新规则。默认为 false。
+	SyncSecurityGroupRules *bool `json:"syncSecurityGroupRules,omitempty"`
+	// 是否创建域名化负载均衡。
+	DynamicVip *bool `json:"dynamicVip,omitempty"`
+	// 负载均衡后端目标设备所属的网络 ID，如vpc-12345678，可以通过 DescribeVpcs 接口获取。 不填此参数则默认为当前集群所在 VPC。创建内网负载均衡实例时，此参数必填。
+	VpcId *string `json:"vpcId,omitempty"`
+	// 指定VIP申请负载均衡。此参数选填，不填写此参数时自动分配VIP。IPv4和IPv6类型支持此参数，IPv6 NAT64类型不支持。
+	// 注意：当指定VIP创建内网实例、或公网IPv6 BGP实例时，若VIP不属于指定VPC子网的网段内时，会创建失败；若VIP已被占用，也会创建失败。
+	Vip *string `json:"vip,omitempty"`
+	// 购买负载均衡的同时，给负载均衡打上标签，最大支持20个标签键值对。
+	Tags []TagInfo `json:"tags,omitempty"`
+	// 负载均衡实例所属的项目 ID，可以通过 DescribeProject 接口获取。不填此参数则视为默认项目。
+	ProjectId *int64 `json:"projectId,omitempty"`
+	// 负载均衡实例的名称。规则：1-60 个英文、汉字、数字、连接线“-”或下划线“_”。 注意：如果名称与系统中已有负载均衡实例的名称相同，则系统将会自动生成此次创建的负载均衡实例的名称。
+	LoadBalancerName *string `json:"loadBalancerName,omitempty"`
+	// 负载均衡实例的网络类型：OPEN：公网属性， INTERNAL：内网属性。默认使用 OPEN（公网负载均衡）。
+	// +kubebuilder:validation:Enum=OPEN;INTERNAL
+	LoadBalancerType *string `json:"loadBalancerType,omitempty"`
+	// 仅适用于公网且IP版本为IPv4的负载均衡。设置跨可用区容灾时的主可用区ID，例如 100001 或 ap-guangzhou-1
+	// 注：主可用区是需要承载流量的可用区，备可用区默认不承载流量，主可用区不可用时才使用备可用区。目前仅广州、上海、南京、北京、成都、深圳金融、中国香港、首尔、法兰克福、新加坡地域的 IPv4 版本的 CLB 支持主备可用区。可通过 DescribeResources 接口查询一个地域的主可用区的列表。【如果您需要体验该功能，请通过 工单申请】
+	MasterZoneId *string `json:"masterZoneId,omitempty"`
+	// 仅适用于公网且IP版本为IPv4的负载均衡。可用区ID，指定可用区以创建负载均衡实例。
+	ZoneId *string `json:"zoneId,omitempty"`
+	// 在私有网络内购买内网负载均衡实例的情况下，必须指定子网 ID，内网负载均衡实例的 VIP 将从这个子网中产生。
+	// 创建内网负载均衡实例，或者创建 IPv6FullChain 版本的负载均衡实例，此参数必填。
+	// 创建公网IPv4负载均衡实例时，不支持指定该参数。
+	SubnetId *string `json:"subnetId,omitempty"`
+	// 性能容量型规格。
+	// 若需要创建性能容量型实例，则此参数必填，取值范围：
+	// clb.c2.medium：标准型规格
+	// clb.c3.small：高阶型1规格
+	// clb.c3.medium：高阶型2规格
+	// clb.c4.small：超强型1规格
+	// clb.c4.medium：超强型2规格
+	// clb.c4.large：超强型3规格
+	// clb.c4.xlarge：超强型4规格
+	// 若需要创建共享型实例，则无需填写此参数。
+	// +kubebuilder:validation:Enum=clb.c2.medium;clb.c3.small;clb.c3.medium;clb.c4.small;clb.c4.medium;clb.c4.large;clb.c4.xlarge
+	SlaType *string `json:"slaType,omitempty"`
+	// 负载均衡实例计费类型，取值：POSTPAID_BY_HOUR，PREPAID，默认是POSTPAID_BY_HOUR。
+	// +kubebuilder:validation:Enum=POSTPAID_BY_HOUR;PREPAID
+	LBChargeType *string `json:"lbChargeType,omitempty"`
+	// 仅适用于公网负载均衡。负载均衡的网络计费模式。
+	InternetAccessible *InternetAccessible `json:"internetAccessible,omitempty"`
+}
+
+// TagInfo 定义标签结构
+type TagInfo struct {
+	// 标签的键
+	TagKey string `json:"tagKey"`
+	// 标签的值
+	TagValue string `json:"tagValue"`
+}
+
+// InternetAccessible 定义网络计费相关参数
+type InternetAccessible struct {
+	// TRAFFIC_POSTPAID_BY_HOUR 按流量按小时后计费 ; BANDWIDTH_POSTPAID_BY_HOUR 按带宽按小时后计费; BANDWIDTH_PACKAGE 按带宽包计费;BANDWIDTH_PREPAID按带宽预付费。注意：此字段可能返回 null，表示取不到有效值。
+	// +kubebuilder:validation:Enum=TRAFFIC_POSTPAID_BY_HOUR;BANDWIDTH_POSTPAID_BY_HOUR;BANDWIDTH_PACKAGE;BANDWIDTH_PREPAID
+	InternetChargeType *string `json:"internetChargeType,omitempty"`
+	// 最大出带宽，单位Mbps，仅对公网属性的共享型、性能容量型和独占型 CLB 实例、以及内网属性的性能容量型 CLB 实例生效。
+	// - 对于公网属性的共享型和独占型 CLB 实例，最大出带宽的范围为1Mbps-2048Mbps。
+	// - 对于公网属性和内网属性的性能容量型 CLB实例，最大出带宽的范围为1Mbps-61440Mbps。
+	// （调用CreateLoadBalancer创建LB时不指定此参数则设置为默认值10Mbps。此上限可调整）
+	InternetMaxBandwidthOut *int64 `json:"internetMaxBandwidthOut,omitempty"`
+	// 带宽包的类型，如 SINGLEISP（单线）、BGP（多线）。
+	// +kubebuilder:validation:Enum=SINGLEISP;BGP
+	BandwidthpkgSubType *string `json:"bandwidthpkgSubType,omitempty"`
+}
+
+// CLBPortPoolStatus defines the observed state of CLBPortPool.
+type CLBPortPoolStatus struct {
+	// 端口池阶段: Pending/Active/Scaling/Deleting
+	// +kubebuilder:default=Pending
+	Phase CLBPortPoolPhase `json:"phase"`
+	// 端口池状况，Ready 的 message 记录端口池不可用的原因
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// 监听器数量的 Quota
+	Quota uint16 `json:"quota"`
+	// 负载均衡器状态列表
+	LoadBalancerStatuses []LoadBalancerStatus `json:"loadBalancerStatuses,omitempty"`
+	// 自动创建的负载均衡已绑定的端口池安全组，securityGroups 被清空时据此解绑
+	SecurityGroups []string `json:"securityGroups,omitempty"`
+	// 已维护放通端口池端口范围入站规则的安全组，安全组被移除、关闭 syncSecurityGroupRules 或删除端口池时据此清理规则
+	SecurityGroupRules []string `json:"securityGroupRules,omitempty"`
+}
+
+type CLBPortPoolPhase string
+
+const (
+	CLBPortPoolPhasePending  CLBPortPoolPhase = "Pending"
+	CLBPortPoolPhaseActive   CLBPortPoolPhase = "Active"
+	CLBPortPoolPhaseScaling  CLBPortPoolPhase = "Scaling"
+	CLBPortPoolPhaseDeleting CLBPortPoolPhase = "Deleting"
+)
+
+const (
+	// CLBPortPoolConditionReady 表示端口池是否可以分配端口
+	CLBPortPoolConditionReady = "Ready"
+)
+
+type LoadBalancerState string
+
+const (
+	LoadBalancerStateRunning  LoadBalancerState = "Running"
+	LoadBalancerStateNotFound LoadBalancerState = "NotFound"
+)
+
+// LoadBalancerStatus 定义负载均衡器状态
+type LoadBalancerStatus struct {
+	// 是否自动创建
+	AutoCreated *bool `json:"autoCreated,omitempty"`
+	// CLB 状态（Running/NotFound）
+	State LoadBalancerState `json:"state"`
+	// CLB 实例 ID
+	LoadBalancerID string `json:"loadBalancerID"`
+	// CLB 实例名称
+	LoadBalancerName string `json:"loadBalancerName"`
+	// CLB 实例的 IP 地址
+	Ips []string `json:"ips,omitempty"`
+	// CLB 实例的域名 (域名化 CLB)
+	Hostname *string `json:"hostname,omitempty"`
+	// 已分配的监听器数量
+	Allocated uint16 `json:"allocated"`
+	// CLB 的 IP 版本，可选值：IPV4、IPV6、IPv6FullChain
+	// +optional
+	AddressIPVersion *string `json:"addressIPVersion,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:resource:scope=Cluster,shortName=cpp
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="Phase"
+
+// CLBPortPool is the Schema for the clbportpools API.
+type CLBPortPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CLBPortPoolSpec   `json:"spec,omitempty"`
+	Status CLBPortPoolStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CLBPortPoolList contains a list of CLBPortPool.
+type CLBPortPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CLBPortPool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CLBPortPool{}, &CLBPortPoolList{})
+}
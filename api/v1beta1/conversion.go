@@ -0,0 +1,21 @@
+package v1beta1
+
+// v1beta1 是 CLBPortPool、CLBPodBinding 和 CLBNodeBinding 的存储版本，也是版本转换的中心（Hub），
+// v1alpha1 通过 conversion webhook 与 v1beta1 互相转换。
+//
+// 存储版本迁移：升级后 etcd 中已有的对象仍以 v1alpha1 存储，API Server 读取时会经过 conversion webhook 转换，
+// 直到对象被再次写入才会以 v1beta1 存储。要彻底移除 v1alpha1，需要：
+//  1. 对所有 CLBPortPool、CLBPodBinding、CLBNodeBinding 执行一次不修改内容的更新（如
+//     kubectl get clbportpools -o json | kubectl replace -f -），或使用 kube-storage-version-migrator，
+//     让所有对象以 v1beta1 重新写入。
+//  2. 将 CRD 的 status.storedVersions 更新为只包含 v1beta1。
+//  3. 之后的版本中才能将 v1alpha1 设置为 served: false 并最终移除。
+
+// Hub marks this type as a conversion hub.
+func (*CLBPortPool) Hub() {}
+
+// Hub marks this type as a conversion hub.
+func (*CLBPodBinding) Hub() {}
+
+// Hub marks this type as a conversion hub.
+func (*CLBNodeBinding) Hub() {}
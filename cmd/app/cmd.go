@@ -29,6 +29,9 @@ const (
 	vpcIdFlag                  = "vpcid"
 	clusterIdFlag              = "cluster-id"
 	cloudAPIEndpointSuffixFlag = "cloud-api-endpoint-suffix"
+	dryRunCloudFlag            = "dry-run-cloud"
+	podSchedulingGateFlag      = "pod-scheduling-gate"
+	enableCLBListenerFlag      = "enable-clb-listener"
 )
 
 var (
@@ -40,7 +43,7 @@ func init() {
 	viper.AutomaticEnv()
 	viper.SetEnvKeyReplacer(envReplacer)
 
-	flags := RootCommand.Flags()
+	flags := RootCommand.PersistentFlags()
 	zapOptions.BindFlags(flag.CommandLine)
 	flags.AddGoFlagSet(flag.CommandLine)
 	addStringFlag(flags, metricsBindAddressFlag, "0", "The address the metrics endpoint binds to. Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -51,6 +54,9 @@ func init() {
 	addStringFlag(flags, regionFlag, "", "The region of TKE cluster")
 	addStringFlag(flags, vpcIdFlag, "", "The VPC ID of TKE cluster")
 	addStringFlag(flags, cloudAPIEndpointSuffixFlag, "", "Cloud API endpoint suffix, e.g. 'test' for test env (clb.test.tencentcloudapi.com), empty for production")
+	addBoolFlag(RootCommand.Flags(), podSchedulingGateFlag, false, "Add a scheduling gate to pods with CLB port mapping enabled on creation, the gate is removed after CLB ports are allocated, so that pods are not scheduled while the port pool is exhausted.")
+	addBoolFlag(RootCommand.Flags(), enableCLBListenerFlag, true, "Manage CLB listeners of CLBPodBinding/CLBNodeBinding through CLBListener objects reconciled by a dedicated controller, instead of calling the CLB API directly from the binding controllers. When upgrading an existing cluster, bound ports are migrated on the next reconcile of their binding: a CLBListener is created and adopts the existing listener, listeners of bindings deleted before migration are deleted directly. Disabling it falls back to the deprecated direct mode, which will be removed in a future release. Do not disable it while CLBListener objects still exist.")
+	addBoolFlag(RootCommand.Flags(), dryRunCloudFlag, false, "Shadow mode: record all CLB write API calls instead of executing them, and write nothing to the cluster. Leader election is disabled, the skipped calls are reported via log, the clb_dry_run_calls_total metric and the /debug/dry-run endpoint of the metrics server.")
 }
 
 func addStringFlag(flags *pflag.FlagSet, name, value, usage string) {
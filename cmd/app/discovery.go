@@ -12,6 +12,7 @@ import (
 	memory "k8s.io/client-go/discovery/cached"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 // 手动创建 RESTMapper
@@ -42,6 +43,9 @@ func InitOptionalScheme(config *rest.Config) error {
 	if err := initOKGScheme(restMapper); err != nil {
 		return errors.WithStack(err)
 	}
+	if err := initGatewayAPIScheme(restMapper); err != nil {
+		return errors.WithStack(err)
+	}
 	return nil
 }
 
@@ -79,6 +83,30 @@ func initOKGScheme(restMapper meta.RESTMapper) error {
 	return nil
 }
 
+func initGatewayAPIScheme(restMapper meta.RESTMapper) error {
+	// GatewayClass 和 Gateway 是必须的，路由类型按需启用
+	for _, kind := range []string{"GatewayClass", "Gateway"} {
+		gvk := gatewayv1.SchemeGroupVersion.WithKind(kind)
+		if installed, err := isCRDInstalled(restMapper, gvk); err != nil {
+			return errors.WithStack(err)
+		} else if !installed {
+			return nil
+		}
+	}
+	for _, kind := range []string{"TCPRoute", "UDPRoute", "TLSRoute"} {
+		gvk := gatewayv1.SchemeGroupVersion.WithKind(kind)
+		if installed, err := isCRDInstalled(restMapper, gvk); err != nil {
+			return errors.WithStack(err)
+		} else if installed {
+			clusterinfo.GatewayRouteKinds = append(clusterinfo.GatewayRouteKinds, kind)
+		}
+	}
+	utilruntime.Must(gatewayv1.Install(scheme))
+	clusterinfo.GatewayAPISupported = true
+	setupLog.Info("Gateway API CRD discovered, will enable Gateway API support", "routeKinds", clusterinfo.GatewayRouteKinds)
+	return nil
+}
+
 // 检查 CRD 是否存在的函数
 func isCRDInstalled(restMapper meta.RESTMapper, gvk schema.GroupVersionKind) (bool, error) {
 	_, err := restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
@@ -0,0 +1,42 @@
+package app
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tkestack/tke-extend-network-controller/internal/controller"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+var removeDrainFinalizersCommand = &cobra.Command{
+	Use:   "remove-drain-finalizers",
+	Short: "Remove the drain finalizer from all pods",
+	Long: `Remove the networking.cloud.tencent.com/drain finalizer which is added by the controller to pods with
+drain seconds configured. The finalizer is removed by the controller when the drain period ends, so after the
+controller is uninstalled, deleting pods would be stuck in Terminating state until the finalizer is removed.
+
+It is run by the post-delete hook of the helm chart, run it manually if the controller is uninstalled in
+other ways. Stop the controller before running, otherwise the finalizer is added back to the running pods.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runRemoveDrainFinalizers()
+	},
+}
+
+func init() {
+	RootCommand.AddCommand(removeDrainFinalizersCommand)
+}
+
+func runRemoveDrainFinalizers() {
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(zapOptions)))
+	removed, err := controller.RemoveDrainFinalizers(ctrl.SetupSignalHandler(), newClient())
+	for _, pod := range removed {
+		fmt.Printf("[removed] %s\n", pod)
+	}
+	fmt.Printf("removed: %d\n", len(removed))
+	if err != nil {
+		setupLog.Error(err, "remove drain finalizers failed")
+		os.Exit(1)
+	}
+}
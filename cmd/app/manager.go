@@ -2,10 +2,12 @@ package app
 
 import (
 	"context"
+	"net/http"
 	"os"
 
 	"github.com/spf13/viper"
 
+	"github.com/tkestack/tke-extend-network-controller/internal/cleanupqueue"
 	"github.com/tkestack/tke-extend-network-controller/pkg/clb"
 	"github.com/tkestack/tke-extend-network-controller/pkg/cloudapi"
 	"github.com/tkestack/tke-extend-network-controller/pkg/clusterinfo"
@@ -13,7 +15,9 @@ import (
 	"github.com/tkestack/tke-extend-network-controller/pkg/manager"
 	"github.com/tkestack/tke-extend-network-controller/pkg/userinfo"
 	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
@@ -26,9 +30,8 @@ import (
 
 var setupLog = ctrl.Log.WithName("setup")
 
-func runManager() {
-	ctrl.SetLogger(zap.New(zap.UseFlagOptions(zapOptions)))
-
+// 初始化集群信息和云 API，返回当前地域
+func initCloudAPI() string {
 	region := viper.GetString(regionFlag)
 	if region == "" {
 		var err error
@@ -54,6 +57,18 @@ func runManager() {
 		viper.GetString(secretKeyFlag),
 	)
 	cloudapi.SetEndpointSuffix(viper.GetString(cloudAPIEndpointSuffixFlag))
+	return region
+}
+
+func runManager() {
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(zapOptions)))
+
+	region := initCloudAPI()
+	dryRunCloud := viper.GetBool(dryRunCloudFlag)
+	if dryRunCloud {
+		setupLog.Info("dry-run-cloud enabled, cloud write API calls will be recorded instead of executed")
+		clb.SetDryRun(true)
+	}
 	_, err := clb.Quota.Get(context.Background(), region)
 	if err != nil {
 		setupLog.Error(err, "failed to get clb quota")
@@ -75,6 +90,19 @@ func runManager() {
 	}
 
 	opts := manager.GetOptions(scheme, metricsAddr, probeAddr, enableLeaderElection)
+	if dryRunCloud { // 影子模式：不参与选主，对集群的写操作也只做 dry-run，写入的结果保存在内存中
+		opts.LeaderElection = false
+		opts.NewClient = func(config *rest.Config, options client.Options) (client.Client, error) {
+			c, err := client.New(config, options)
+			if err != nil {
+				return nil, err
+			}
+			return kube.NewShadowClient(c), nil
+		}
+		opts.Metrics.ExtraHandlers = map[string]http.Handler{
+			"/debug/dry-run": clb.DryRunHandler(),
+		}
+	}
 	mgr, err := ctrl.NewManager(
 		ctrl.GetConfigOrDie(),
 		opts,
@@ -89,6 +117,7 @@ func runManager() {
 
 	networkingv1alpha1.Init(mgr)
 	kube.Init(mgr)
+	cleanupqueue.Init(mgr, util.GetCurrentNamespace())
 
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
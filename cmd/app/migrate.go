@@ -0,0 +1,129 @@
+package app
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tkestack/tke-extend-network-controller/internal/migration"
+	"github.com/tkestack/tke-extend-network-controller/pkg/clusterinfo"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/yaml"
+)
+
+const fileFlag = "file"
+
+var exportCommand = &cobra.Command{
+	Use:   "export",
+	Short: "Export port pools and port allocations for cluster migration",
+	Long: `Export the spec and status of all CLBPortPools and the port allocations of all CLBPodBindings and
+CLBNodeBindings to a file, which can be imported into another cluster by the import subcommand.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runExport(cmd)
+	},
+}
+
+var importCommand = &cobra.Command{
+	Use:   "import",
+	Short: "Import port pools and port allocations exported from another cluster",
+	Long: `Import port pools and port allocations exported by the export subcommand. Auto-created CLBs of the
+exported port pools are taken over by the new cluster (they are deleted when the port pools are deleted in the
+new cluster), other CLBs are imported as existed CLBs. The bindings are recreated with their port allocations,
+which are marked as allocated when the controller starts, and the controller renames their listeners to the
+new binding UIDs. Pods and nodes with the same name adopt the imported bindings directly, others can claim them
+by the networking.cloud.tencent.com/claim-clb-binding annotation.
+
+Run it before the controller starts in the new cluster, and do not delete the port pools in the old cluster
+while the old controller is running, otherwise the auto-created CLBs will be deleted.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runImport(cmd)
+	},
+}
+
+func init() {
+	RootCommand.AddCommand(exportCommand, importCommand)
+	exportCommand.Flags().StringP(fileFlag, "f", "", "The file to write to, print to stdout if empty.")
+	importCommand.Flags().StringP(fileFlag, "f", "", "The file exported by the export subcommand.")
+	importCommand.Flags().Bool(dryRunFlag, false, "Only print the import result without creating any object.")
+}
+
+func newClient() client.Client {
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create client")
+		os.Exit(1)
+	}
+	return c
+}
+
+func runExport(cmd *cobra.Command) {
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(zapOptions)))
+	clusterinfo.ClusterId = viper.GetString(clusterIdFlag)
+
+	state, err := migration.Export(ctrl.SetupSignalHandler(), newClient())
+	if err != nil {
+		setupLog.Error(err, "export failed")
+		os.Exit(1)
+	}
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		setupLog.Error(err, "marshal failed")
+		os.Exit(1)
+	}
+	file, _ := cmd.Flags().GetString(fileFlag)
+	if file == "" {
+		fmt.Print(string(data))
+		return
+	}
+	if err := os.WriteFile(file, data, 0644); err != nil {
+		setupLog.Error(err, "write file failed", "file", file)
+		os.Exit(1)
+	}
+	setupLog.Info("exported", "file", file, "pools", len(state.Pools), "bindings", len(state.Bindings))
+}
+
+func runImport(cmd *cobra.Command) {
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(zapOptions)))
+
+	file, _ := cmd.Flags().GetString(fileFlag)
+	if file == "" {
+		setupLog.Error(nil, "--file is required")
+		os.Exit(1)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		setupLog.Error(err, "read file failed", "file", file)
+		os.Exit(1)
+	}
+	state := &migration.State{}
+	if err := yaml.Unmarshal(data, state); err != nil {
+		setupLog.Error(err, "unmarshal failed", "file", file)
+		os.Exit(1)
+	}
+	dryRun, _ := cmd.Flags().GetBool(dryRunFlag)
+	report, err := migration.Import(ctrl.SetupSignalHandler(), newClient(), state, dryRun)
+	if err != nil {
+		setupLog.Error(err, "import failed")
+		os.Exit(1)
+	}
+	action := "imported"
+	if dryRun {
+		action = "to import"
+	}
+	for _, item := range report.Imported {
+		fmt.Printf("[%s] %s\n", action, item)
+	}
+	for _, item := range report.Skipped {
+		fmt.Printf("[skipped] %s\n", item)
+	}
+	for _, item := range report.Failed {
+		fmt.Printf("[failed] %s\n", item)
+	}
+	fmt.Printf("%s: %d, skipped: %d, failed: %d\n", action, len(report.Imported), len(report.Skipped), len(report.Failed))
+	if len(report.Failed) > 0 {
+		os.Exit(1)
+	}
+}
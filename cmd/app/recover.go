@@ -0,0 +1,65 @@
+package app
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tkestack/tke-extend-network-controller/internal/controller"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+const dryRunFlag = "dry-run"
+
+var recoverCommand = &cobra.Command{
+	Use:   "recover",
+	Short: "Rebuild CLBPodBinding and CLBNodeBinding from CLB listeners",
+	Long: `Rebuild lost CLBPodBinding and CLBNodeBinding (e.g. CRD deleted by mistake or etcd restored) from CLB listeners.
+It scans listeners created by the controller on all CLBs of the port pools, matches the registered targets to
+pods and nodes by IP and port, and recreates the bindings with their port binding status. The port allocator
+is rebuilt from the binding status when the controller starts. Listeners and targets that can not be matched
+are only reported, never deleted.
+
+Stop the controller (scale the deployment to 0) before running, otherwise it may allocate new ports for
+the pods and nodes before the bindings are recovered.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runRecover(cmd)
+	},
+}
+
+func init() {
+	RootCommand.AddCommand(recoverCommand)
+	recoverCommand.Flags().Bool(dryRunFlag, false, "Only print the recovery result without creating any binding.")
+}
+
+func runRecover(cmd *cobra.Command) {
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(zapOptions)))
+	initCloudAPI()
+
+	dryRun, _ := cmd.Flags().GetBool(dryRunFlag)
+	report, err := (&controller.BindingRecovery{
+		Client: newClient(),
+		DryRun: dryRun,
+	}).Run(ctrl.SetupSignalHandler())
+	if report != nil {
+		action := "recovered"
+		if dryRun {
+			action = "to recover"
+		}
+		for _, item := range report.Recovered {
+			fmt.Printf("[%s] %s\n", action, item)
+		}
+		for _, item := range report.Skipped {
+			fmt.Printf("[skipped] %s\n", item)
+		}
+		for _, item := range report.Unmatched {
+			fmt.Printf("[unmatched] %s\n", item)
+		}
+		fmt.Printf("%s: %d, skipped: %d, unmatched: %d\n", action, len(report.Recovered), len(report.Skipped), len(report.Unmatched))
+	}
+	if err != nil {
+		setupLog.Error(err, "recover failed")
+		os.Exit(1)
+	}
+}
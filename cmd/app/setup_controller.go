@@ -4,6 +4,8 @@ import (
 	"context"
 	"os"
 
+	"github.com/spf13/viper"
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
 	"github.com/tkestack/tke-extend-network-controller/internal/clbbinding"
 	"github.com/tkestack/tke-extend-network-controller/internal/controller"
 	"github.com/tkestack/tke-extend-network-controller/pkg/clusterinfo"
@@ -24,6 +26,37 @@ func SetupControllers(mgr ctrl.Manager) {
 		os.Exit(1)
 	}
 
+	// CLBListener controller
+	controller.UseCLBListener = viper.GetBool(enableCLBListenerFlag)
+	if err := (&controller.CLBListenerReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("clblistener-controller"),
+	}).SetupWithManager(mgr, util.GetWorkerCount("WORKER_CLB_LISTENER_CONTROLLER")); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CLBListener")
+		os.Exit(1)
+	}
+
+	// DedicatedCLBService controller
+	if err := (&controller.DedicatedCLBServiceReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("dedicatedclbservice-controller"),
+	}).SetupWithManager(mgr, util.GetWorkerCount("WORKER_DEDICATED_CLB_SERVICE_CONTROLLER")); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DedicatedCLBService")
+		os.Exit(1)
+	}
+
+	// DedicatedCLBListener controller
+	if err := (&controller.DedicatedCLBListenerReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("dedicatedclblistener-controller"),
+	}).SetupWithManager(mgr, util.GetWorkerCount("WORKER_DEDICATED_CLB_LISTENER_CONTROLLER")); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DedicatedCLBListener")
+		os.Exit(1)
+	}
+
 	// CLBPodBinding cotroller
 	if err := (&controller.CLBPodBindingReconciler{
 		Client:   mgr.GetClient(),
@@ -34,6 +67,22 @@ func SetupControllers(mgr ctrl.Manager) {
 		os.Exit(1)
 	}
 
+	// 证书 Secret 变化时通过索引找到引用了该 Secret 的 CLBPodBinding
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &networkingv1alpha1.CLBPodBinding{}, controller.CertSecretNameIndex, controller.IndexCertSecretNames); err != nil {
+		setupLog.Error(err, "unable to create field index", "field", controller.CertSecretNameIndex)
+		os.Exit(1)
+	}
+
+	// CLBSharedBinding controller
+	if err := (&controller.CLBSharedBindingReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("clbsharedbinding-controller"),
+	}).SetupWithManager(mgr, util.GetWorkerCount("WORKER_CLB_SHARED_BINDING_CONTROLLER")); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CLBSharedBinding")
+		os.Exit(1)
+	}
+
 	// CLNodeBinding cotroller
 	if err := (&controller.CLBNodeBindingReconciler{
 		Client:   mgr.GetClient(),
@@ -86,6 +135,14 @@ func SetupControllers(mgr ctrl.Manager) {
 		return ret
 	})
 
+	// GC controller
+	if err := (&controller.GCReconciler{
+		Client: mgr.GetClient(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GC")
+		os.Exit(1)
+	}
+
 	// GameServerSet controller
 	if clusterinfo.OKGSupported {
 		if err := (&controller.GameServerSetReconciler{
@@ -96,4 +153,34 @@ func SetupControllers(mgr ctrl.Manager) {
 			os.Exit(1)
 		}
 	}
+
+	// Gateway API 相关 controller，仅在集群安装了 Gateway API CRD 时启用
+	if clusterinfo.GatewayAPISupported {
+		if err := (&controller.GatewayClassReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "GatewayClass")
+			os.Exit(1)
+		}
+		if err := (&controller.GatewayReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("gateway-controller"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Gateway")
+			os.Exit(1)
+		}
+		for _, kind := range clusterinfo.GatewayRouteKinds {
+			if err := (&controller.GatewayRouteReconciler{
+				Client:   mgr.GetClient(),
+				Scheme:   mgr.GetScheme(),
+				Recorder: mgr.GetEventRecorderFor("gatewayroute-controller"),
+				Kind:     kind,
+			}).SetupWithManager(mgr, util.GetWorkerCount("WORKER_GATEWAY_ROUTE_CONTROLLER")); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", kind)
+				os.Exit(1)
+			}
+		}
+	}
 }
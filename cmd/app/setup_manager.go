@@ -4,7 +4,9 @@ import (
 	"context"
 	"os"
 
+	"github.com/spf13/viper"
 	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	networkingv1beta1 "github.com/tkestack/tke-extend-network-controller/api/v1beta1"
 	"github.com/tkestack/tke-extend-network-controller/internal/portpool"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -22,6 +24,7 @@ func init() {
 	// Add API to schema
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))     // k8s native resources
 	utilruntime.Must(networkingv1alpha1.AddToScheme(scheme)) // tke-extend-network-controller CRDs
+	utilruntime.Must(networkingv1beta1.AddToScheme(scheme))  // tke-extend-network-controller CRDs (storage version, 注册后 webhook server 会提供 /convert)
 	// utilruntime.Must(kruisegamev1alpha1.AddToScheme(scheme)) // OKG CRDs
 	// utilruntime.Must(agonesv1.AddToScheme(scheme))           // Agones CRDs
 }
@@ -33,7 +36,10 @@ func SetupManager(mgr ctrl.Manager, opts manager.Options) {
 	}
 
 	SetupControllers(mgr)
-	SetupWebhooks(mgr)
+	// 影子模式下不注册 webhook，避免影子控制器的 Pod 被 webhook Service 选中后修改真实的 Pod
+	if !viper.GetBool(dryRunCloudFlag) {
+		SetupWebhooks(mgr)
+	}
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
@@ -96,5 +102,14 @@ func (i *initCache) Start(ctx context.Context) error {
 			portpool.Allocator.MarkAllocated(bd.Pool, portpool.NewLBKey(bd.LoadbalancerId, bd.Region), bd.LoadbalancerPort, bd.LoadbalancerEndPort, bd.Protocol)
 		}
 	}
+	sbl := &networkingv1alpha1.CLBSharedBindingList{}
+	if err := i.List(ctx, sbl); err != nil {
+		return err
+	}
+	for _, sb := range sbl.Items {
+		if bd := sb.Status.PortBinding; bd != nil {
+			portpool.Allocator.MarkAllocated(bd.Pool, portpool.NewLBKey(bd.LoadbalancerId, bd.Region), bd.LoadbalancerPort, bd.LoadbalancerEndPort, bd.Protocol)
+		}
+	}
 	return nil
 }
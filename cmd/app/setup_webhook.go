@@ -3,6 +3,8 @@ package app
 import (
 	"os"
 
+	"github.com/spf13/viper"
+	webhookv1 "github.com/tkestack/tke-extend-network-controller/internal/webhook/v1"
 	webhook "github.com/tkestack/tke-extend-network-controller/internal/webhook/v1alpha1"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
@@ -12,4 +14,20 @@ func SetupWebhooks(mgr ctrl.Manager) {
 		setupLog.Error(err, "unable to create webhook", "webhook", "CLBPortPool")
 		os.Exit(1)
 	}
+	if err := webhook.SetupCLBPodBindingWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "CLBPodBinding")
+		os.Exit(1)
+	}
+	if err := webhook.SetupCLBNodeBindingWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "CLBNodeBinding")
+		os.Exit(1)
+	}
+	if err := webhookv1.SetupPodWebhookWithManager(mgr, viper.GetBool(podSchedulingGateFlag)); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "Pod")
+		os.Exit(1)
+	}
+	if err := webhookv1.SetupNodeWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "Node")
+		os.Exit(1)
+	}
 }
@@ -2,8 +2,11 @@ package clbbinding
 
 import (
 	"context"
+	"slices"
+	"strings"
 
 	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -22,11 +25,31 @@ type CLBBinding interface {
 	FetchObject(context.Context, client.Client) (client.Object, error)
 }
 
+// Wrap 将 CLBPodBinding/CLBNodeBinding 对象包装为 CLBBinding，不支持的类型返回 nil
+func Wrap(obj client.Object) CLBBinding {
+	switch bd := obj.(type) {
+	case *networkingv1alpha1.CLBPodBinding:
+		return WrapCLBPodBinding(bd)
+	case *networkingv1alpha1.CLBNodeBinding:
+		return WrapCLBNodeBinding(bd)
+	}
+	return nil
+}
+
+// IsPreviousUID 判断 uid 是否为对象注解中记录的原 CLBBinding 的 UID。灾难恢复或集群迁移重建的 CLBBinding 的 UID
+// 发生了变化，而监听器名称中记录的是原 CLBBinding 的 UID，需要据此识别属于自己的监听器。
+func IsPreviousUID(obj client.Object, uid string) bool {
+	uids := obj.GetAnnotations()[constant.PreviousUIDsKey]
+	return uid != "" && uids != "" && slices.Contains(strings.Split(uids, ","), uid)
+}
+
 type Backend interface {
 	client.Object
 	GetIP() string
 	GetIPv6() string
 	GetObject() client.Object
 	GetNode(ctx context.Context) (*corev1.Node, error)
+	// IsReady 判断后端是否就绪，portName 不为空时只判断声明了该容器端口的容器是否就绪（仅 Pod 支持）
+	IsReady(portName string) bool
 	TriggerReconcile()
 }
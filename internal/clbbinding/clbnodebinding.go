@@ -87,6 +87,15 @@ func (b nodeBackend) GetNode(ctx context.Context) (*corev1.Node, error) {
 	return b.Node, nil
 }
 
+func (b nodeBackend) IsReady(portName string) bool {
+	for _, condition := range b.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
 func (b nodeBackend) TriggerReconcile() {
 	eventsource.Node <- event.TypedGenericEvent[client.Object]{
 		Object: b.Node,
@@ -7,7 +7,9 @@ import (
 	agonesv1 "agones.dev/agones/pkg/apis/agones/v1"
 	"github.com/pkg/errors"
 	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
 	"github.com/tkestack/tke-extend-network-controller/pkg/eventsource"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -100,6 +102,33 @@ func (b podBackend) GetNode(ctx context.Context) (*corev1.Node, error) {
 	return node, nil
 }
 
+func (b podBackend) IsReady(portName string) bool {
+	if portName == "" {
+		// 声明了 clb-bound readiness gate 的 Pod 要绑定后才会 Ready，只判断容器是否就绪，避免互相等待
+		conditionType := corev1.PodReady
+		if util.HasReadinessGate(b.Pod, constant.CLBBoundConditionType) {
+			conditionType = corev1.ContainersReady
+		}
+		condition := util.GetPodCondition(b.Pod, conditionType)
+		return condition != nil && condition.Status == corev1.ConditionTrue
+	}
+	for _, container := range b.Pod.Spec.Containers {
+		for _, port := range container.Ports {
+			if port.Name != portName {
+				continue
+			}
+			for _, status := range b.Pod.Status.ContainerStatuses {
+				if status.Name == container.Name {
+					return status.Ready
+				}
+			}
+			return false
+		}
+	}
+	// 没有容器声明该端口，视为未就绪
+	return false
+}
+
 func (b podBackend) TriggerReconcile() {
 	eventsource.Pod <- event.TypedGenericEvent[client.Object]{
 		Object: b.Pod,
@@ -0,0 +1,50 @@
+package clbbinding
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestPodBackendIsReady(t *testing.T) {
+	newPod := func(podReady, gameReady bool) *corev1.Pod {
+		condition := corev1.ConditionFalse
+		if podReady {
+			condition = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "game", Ports: []corev1.ContainerPort{{Name: "game", ContainerPort: 7777}}},
+					{Name: "sidecar", Ports: []corev1.ContainerPort{{Name: "metrics", ContainerPort: 9090}}},
+				},
+			},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: condition}},
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "game", Ready: gameReady},
+					{Name: "sidecar", Ready: podReady},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name      string
+		pod       *corev1.Pod
+		portName  string
+		wantReady bool
+	}{
+		{name: "named port with ready container", pod: newPod(false, true), portName: "game", wantReady: true},
+		{name: "named port with unready container", pod: newPod(true, false), portName: "game", wantReady: false},
+		{name: "unnamed port with ready pod", pod: newPod(true, false), wantReady: true},
+		{name: "unnamed port with unready pod", pod: newPod(false, true), wantReady: false},
+		{name: "no container declares the port", pod: newPod(true, true), portName: "http", wantReady: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (podBackend{Pod: tt.pod}).IsReady(tt.portName); got != tt.wantReady {
+				t.Errorf("expect ready %v, got %v", tt.wantReady, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package clbbinding
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+)
+
+// 端口映射支持的协议
+var supportedProtocols = []string{constant.ProtocolTCP, constant.ProtocolUDP, constant.ProtocolTCPUDP, "TCP_SSL", "QUIC", constant.ProtocolHTTP, constant.ProtocolHTTPS}
+
+// ParsePortMappings 解析 Pod/Node 的 networking.cloud.tencent.com/clb-port-mapping 注解，忽略无法识别的选项
+func ParsePortMappings(anno string) ([]networkingv1alpha1.PortEntry, error) {
+	return parsePortMappings(anno, false)
+}
+
+// ParsePortMappingsStrict 严格解析端口映射注解，不支持的协议和无法识别的选项都会报错，用于准入校验
+func ParsePortMappingsStrict(anno string) ([]networkingv1alpha1.PortEntry, error) {
+	return parsePortMappings(anno, true)
+}
+
+func parsePortMappings(anno string, strict bool) (ports []networkingv1alpha1.PortEntry, err error) {
+	rd := bufio.NewReader(strings.NewReader(anno))
+	for {
+		line, _, err := rd.ReadLine()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		fields := strings.Fields(string(line))
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("invalid port mapping: %s", string(line))
+		}
+		portStr := fields[0]
+		portUint64, err := strconv.ParseUint(portStr, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("bad port number in port mapping: %s", string(line))
+		}
+		port := uint16(portUint64)
+		protocol := fields[1]
+		if strict && !slices.Contains(supportedProtocols, protocol) {
+			return nil, fmt.Errorf("unsupported protocol %q in port mapping (expect one of %s): %s", protocol, strings.Join(supportedProtocols, ", "), string(line))
+		}
+		pools := strings.Split(fields[2], ",")
+		var useSamePortAcrossPools *bool
+		var certSecretName *string
+		var extraCertSecretNames []string
+		var caCertSecretName *string
+		var healthCheck *networkingv1alpha1.HealthCheck
+		ensureHealthCheck := func() *networkingv1alpha1.HealthCheck {
+			if healthCheck == nil { // 指定了健康检查参数，默认开启健康检查
+				healthCheck = &networkingv1alpha1.HealthCheck{Enabled: true}
+			}
+			return healthCheck
+		}
+		if len(fields) >= 4 {
+			options := fields[3]
+			optionList := strings.Split(options, ",")
+			for _, option := range optionList {
+				kv := strings.Split(option, "=")
+				if len(kv) == 1 {
+					switch kv[0] {
+					case "useSamePortAcrossPools":
+						b := true
+						useSamePortAcrossPools = &b
+					default:
+						if strict {
+							return nil, fmt.Errorf("unknown option %q in port mapping: %s", option, string(line))
+						}
+					}
+				} else if len(kv) == 2 {
+					key := kv[0]
+					value := kv[1]
+					switch key {
+					case "certSecret":
+						certSecretName = &value
+					case "extraCertSecret": // 额外的服务端证书，可以指定多次
+						extraCertSecretNames = append(extraCertSecretNames, value)
+					case "caCertSecret": // 客户端 CA 证书，开启双向认证
+						caCertSecretName = &value
+					case "healthCheck": // 覆盖端口池中的健康检查开关：on/off
+						switch value {
+						case "on":
+							ensureHealthCheck().Enabled = true
+						case "off":
+							ensureHealthCheck().Enabled = false
+						default:
+							return nil, fmt.Errorf("bad healthCheck option in port mapping (expect on or off): %s", string(line))
+						}
+					case "healthCheckPort":
+						checkPort, err := strconv.ParseUint(value, 10, 16)
+						if err != nil {
+							return nil, fmt.Errorf("bad healthCheckPort option in port mapping: %s", string(line))
+						}
+						ensureHealthCheck().CheckPort = util.GetPtr(int64(checkPort))
+					case "healthCheckType":
+						ensureHealthCheck().CheckType = &value
+					default:
+						if strict {
+							return nil, fmt.Errorf("unknown option %q in port mapping: %s", key, string(line))
+						}
+					}
+				} else if strict {
+					return nil, fmt.Errorf("bad option %q in port mapping: %s", option, string(line))
+				}
+			}
+		}
+		ports = append(ports, networkingv1alpha1.PortEntry{
+			Port:                   port,
+			Protocol:               protocol,
+			Pools:                  pools,
+			UseSamePortAcrossPools: useSamePortAcrossPools,
+			CertSecretName:         certSecretName,
+			ExtraCertSecretNames:   extraCertSecretNames,
+			CACertSecretName:       caCertSecretName,
+			HealthCheck:            healthCheck,
+		})
+	}
+	return
+}
+
+// GenerateSpec 根据 Pod/Node 的注解生成期望的 CLBBindingSpec
+func GenerateSpec(anno map[string]string) (*networkingv1alpha1.CLBBindingSpec, error) {
+	ports, err := ParsePortMappings(anno[constant.CLBPortMappingsKey])
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	spec := &networkingv1alpha1.CLBBindingSpec{}
+	spec.Ports = ports
+	if anno[constant.EnableCLBPortMappingsKey] == "false" {
+		spec.Disabled = util.GetPtr(true)
+	}
+	switch v := anno[constant.CLBWaitBackendReadyKey]; v {
+	case "":
+	case "true", "false":
+		spec.WaitBackendReady = util.GetPtr(v == "true")
+	default: // 指定的是容器端口名称
+		spec.WaitBackendReady = util.GetPtr(true)
+		spec.ReadinessPortName = &v
+	}
+	if v := anno[constant.CLBDrainSecondsKey]; v != "" {
+		drainSeconds, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || drainSeconds < 0 || drainSeconds > 3600 {
+			return nil, fmt.Errorf("invalid drain seconds %q, must be an integer between 0 and 3600", v)
+		}
+		spec.DrainSeconds = &drainSeconds
+	}
+	return spec, nil
+}
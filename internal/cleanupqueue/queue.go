@@ -0,0 +1,238 @@
+package cleanupqueue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// 持久化清理队列的 ConfigMap 名称
+const ConfigMapName = "tke-extend-network-controller-cleanup-queue"
+
+const (
+	// 队列中最多保留的任务数，单个任务序列化后约 500 字节，避免 ConfigMap 超过 1MiB 的大小限制
+	MaxTasks = 1000
+	// 清理失败的最大重试次数，超过后放弃清理，由用户手动清理遗留的云上资源
+	MaxRetries = 100
+)
+
+// ErrQueueFull 队列已满，不再接受新的任务
+var ErrQueueFull = errors.New("cleanup queue is full")
+
+// Task 待清理的云上资源（一个端口绑定对应的监听器或 rs）
+type Task struct {
+	Region     string `json:"region"`
+	LbId       string `json:"lbId"`
+	ListenerId string `json:"listenerId,omitempty"`
+	Port       uint16 `json:"port"`
+	EndPort    uint16 `json:"endPort,omitempty"`
+	Protocol   string `json:"protocol"`
+	Pool       string `json:"pool"`
+	// 七层端口绑定的转发规则，不为空时只删除转发规则，共享监听器不删除
+	LocationId string `json:"locationId,omitempty"`
+	Domain     string `json:"domain,omitempty"`
+	Url        string `json:"url,omitempty"`
+	// 预创建监听器场景只解绑 rs，不删除监听器
+	DeregisterOnly bool `json:"deregisterOnly,omitempty"`
+	// 所属 CLBBinding 的 UID，用于确认监听器归属，避免误删端口被重新分配后其它 CLBBinding 创建的监听器
+	OwnerUID string `json:"ownerUID,omitempty"`
+	// 灾难恢复或集群迁移重建前的 CLBBinding 的 UID，监听器名称可能还没有改为 OwnerUID
+	PreviousOwnerUIDs []string `json:"previousOwnerUIDs,omitempty"`
+	// 来源，便于排障，如 CLBPodBinding/default/pod-0
+	Source       string    `json:"source,omitempty"`
+	CreationTime time.Time `json:"creationTime"`
+	Retries      int       `json:"retries,omitempty"`
+	LastError    string    `json:"lastError,omitempty"`
+}
+
+// Key 任务的唯一标识，也作为 ConfigMap 的 key（只能包含 [-._a-zA-Z0-9]）。
+// 同一个 CLBBinding 的同一个 lb 端口只保留一个任务；端口被重新分配后，不同 CLBBinding 强制清理同一个端口时各自保留任务，
+// 避免后加入的任务覆盖前一个任务导致前一个 CLBBinding 的监听器泄漏（GC 根据监听器名称中的 UID 确认归属）。
+func (t *Task) Key() string {
+	key := fmt.Sprintf("%s.%s.%d.%s", t.Region, t.LbId, t.Port, strings.ToLower(t.Protocol))
+	if t.LocationId != "" { // 同一个共享监听器上有多条转发规则
+		key += "." + t.LocationId
+	}
+	if t.OwnerUID != "" {
+		key += "." + t.OwnerUID
+	}
+	return key
+}
+
+// Queue 基于 ConfigMap 持久化的清理队列，控制器重启后未完成的清理任务不会丢失
+type Queue struct {
+	client client.Client
+	// 直接读 apiserver，避免 manager 为 ConfigMap 建立全集群的 informer 缓存
+	reader client.Reader
+	key    client.ObjectKey
+	mu     sync.Mutex
+	notify chan struct{}
+}
+
+func newQueue(c client.Client, reader client.Reader, namespace string) *Queue {
+	return &Queue{
+		client: c,
+		reader: reader,
+		key:    client.ObjectKey{Namespace: namespace, Name: ConfigMapName},
+		notify: make(chan struct{}, 1),
+	}
+}
+
+var queue *Queue
+
+func Init(mgr ctrl.Manager, namespace string) {
+	SetClient(mgr.GetClient(), mgr.GetAPIReader(), namespace)
+}
+
+// SetClient 使用指定的 client 初始化清理队列
+func SetClient(c client.Client, reader client.Reader, namespace string) {
+	queue = newQueue(c, reader, namespace)
+}
+
+// Add 将清理任务加入队列并通知 GC 尽快处理，队列已满时返回 ErrQueueFull
+func Add(ctx context.Context, tasks ...*Task) error {
+	return queue.Add(ctx, tasks...)
+}
+
+// List 获取队列中所有的清理任务
+func List(ctx context.Context) ([]*Task, error) {
+	return queue.List(ctx)
+}
+
+// Update 移除已完成的任务，并更新处理失败的任务（重试次数和错误信息）
+func Update(ctx context.Context, done []*Task, failed []*Task) error {
+	return queue.Update(ctx, done, failed)
+}
+
+// Notify 有新任务加入时收到通知
+func Notify() <-chan struct{} {
+	return queue.notify
+}
+
+func (q *Queue) Add(ctx context.Context, tasks ...*Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	now := time.Now()
+	err := q.mutate(ctx, func(data map[string]string) error {
+		added := 0
+		for _, task := range tasks {
+			if _, ok := data[task.Key()]; !ok {
+				added++
+			}
+		}
+		if len(data)+added > MaxTasks {
+			return errors.Wrapf(ErrQueueFull, "%d tasks in queue, %d to add", len(data), added)
+		}
+		for _, task := range tasks {
+			if task.CreationTime.IsZero() {
+				task.CreationTime = now
+			}
+			b, err := json.Marshal(task)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			data[task.Key()] = string(b)
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (q *Queue) List(ctx context.Context) ([]*Task, error) {
+	cm := &corev1.ConfigMap{}
+	if err := q.reader.Get(ctx, q.key, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.WithStack(err)
+	}
+	tasks := make([]*Task, 0, len(cm.Data))
+	for key, value := range cm.Data {
+		task := &Task{}
+		if err := json.Unmarshal([]byte(value), task); err != nil {
+			ctrl.Log.WithName("cleanupqueue").Error(err, "ignore invalid cleanup task", "key", key)
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+func (q *Queue) Update(ctx context.Context, done []*Task, failed []*Task) error {
+	if len(done) == 0 && len(failed) == 0 {
+		return nil
+	}
+	err := q.mutate(ctx, func(data map[string]string) error {
+		for _, task := range done {
+			delete(data, task.Key())
+		}
+		for _, task := range failed {
+			if _, ok := data[task.Key()]; !ok { // 处理期间已被移除，忽略
+				continue
+			}
+			b, err := json.Marshal(task)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			data[task.Key()] = string(b)
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// 读取 ConfigMap，修改数据后写回，ConfigMap 不存在时自动创建，冲突时重试
+func (q *Queue) mutate(ctx context.Context, fn func(data map[string]string) error) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return retry.OnError(retry.DefaultBackoff, func(err error) bool {
+		return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+	}, func() error {
+		cm := &corev1.ConfigMap{}
+		if err := q.reader.Get(ctx, q.key, cm); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return errors.WithStack(err)
+			}
+			cm = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: q.key.Namespace,
+					Name:      q.key.Name,
+				},
+				Data: make(map[string]string),
+			}
+			if err := fn(cm.Data); err != nil {
+				return err
+			}
+			return q.client.Create(ctx, cm)
+		}
+		if cm.Data == nil {
+			cm.Data = make(map[string]string)
+		}
+		if err := fn(cm.Data); err != nil {
+			return err
+		}
+		return q.client.Update(ctx, cm)
+	})
+}
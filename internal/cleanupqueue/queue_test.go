@@ -0,0 +1,104 @@
+package cleanupqueue
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestQueue(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	q := newQueue(c, c, "kube-system")
+
+	tasks, err := q.List(ctx)
+	if err != nil || len(tasks) != 0 {
+		t.Fatalf("expect empty queue, got %v, err: %v", tasks, err)
+	}
+
+	t1 := &Task{Region: "ap-guangzhou", LbId: "lb-1", ListenerId: "lbl-1", Port: 30000, Protocol: "TCP", OwnerUID: "uid-1"}
+	t2 := &Task{Region: "ap-guangzhou", LbId: "lb-1", Port: 30001, Protocol: "TCP_SSL", OwnerUID: "uid-1"}
+	if err := q.Add(ctx, t1, t2); err != nil {
+		t.Fatal(err)
+	}
+	// 重复添加同一端口的任务会覆盖
+	if err := q.Add(ctx, t1); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-q.notify:
+	default:
+		t.Fatal("expect notify after add")
+	}
+	tasks, err = q.List(ctx)
+	if err != nil || len(tasks) != 2 {
+		t.Fatalf("expect 2 tasks, got %v, err: %v", tasks, err)
+	}
+
+	t2.Retries = 1
+	t2.LastError = "api error"
+	if err := q.Update(ctx, []*Task{t1}, []*Task{t2}); err != nil {
+		t.Fatal(err)
+	}
+	tasks, err = q.List(ctx)
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("expect 1 task, got %v, err: %v", tasks, err)
+	}
+	if tasks[0].Key() != t2.Key() || tasks[0].Retries != 1 || tasks[0].LastError != "api error" {
+		t.Fatalf("unexpected task: %+v", tasks[0])
+	}
+}
+
+func TestQueueKeyByOwner(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	q := newQueue(c, c, "kube-system")
+
+	// 端口被重新分配后，不同 CLBBinding 强制清理同一个端口，两个任务都要保留
+	t1 := &Task{Region: "ap-guangzhou", LbId: "lb-1", Port: 30000, Protocol: "TCP", OwnerUID: "uid-1"}
+	t2 := &Task{Region: "ap-guangzhou", LbId: "lb-1", Port: 30000, Protocol: "TCP", OwnerUID: "uid-2"}
+	if t1.Key() == t2.Key() {
+		t.Fatalf("expect different keys for different owners, got %s", t1.Key())
+	}
+	if err := q.Add(ctx, t1); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Add(ctx, t2); err != nil {
+		t.Fatal(err)
+	}
+	tasks, err := q.List(ctx)
+	if err != nil || len(tasks) != 2 {
+		t.Fatalf("expect 2 tasks, got %v, err: %v", tasks, err)
+	}
+}
+
+func TestQueueFull(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	q := newQueue(c, c, "kube-system")
+
+	tasks := []*Task{}
+	for i := 0; i < MaxTasks; i++ {
+		tasks = append(tasks, &Task{Region: "ap-guangzhou", LbId: "lb-1", Port: uint16(30000 + i), Protocol: "TCP", OwnerUID: "uid-1"})
+	}
+	if err := q.Add(ctx, tasks...); err != nil {
+		t.Fatal(err)
+	}
+	extra := &Task{Region: "ap-guangzhou", LbId: "lb-2", Port: 30000, Protocol: "TCP", OwnerUID: "uid-2"}
+	if err := q.Add(ctx, extra); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("expect ErrQueueFull, got %v", err)
+	}
+	// 已在队列中的任务可以重复添加
+	if err := q.Add(ctx, tasks[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Update(ctx, tasks[:1], nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Add(ctx, extra); err != nil {
+		t.Fatalf("expect added after queue drained, got %v", err)
+	}
+}
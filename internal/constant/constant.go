@@ -9,12 +9,42 @@ const (
 	CLBHostPortMappingResultKey  = "networking.cloud.tencent.com/clb-hostport-mapping-result"
 	EnableCLBHostPortMapping     = "networking.cloud.tencent.com/enable-clb-hostport-mapping"
 	Finalizer                    = "networking.cloud.tencent.com/finalizer"
+	DrainFinalizer               = "networking.cloud.tencent.com/drain"
 	Ratain                       = "networking.cloud.tencent.com/retain"
 	LastUpdateTime               = "networking.cloud.tencent.com/last-update-time"
 	FinalizedKey                 = "networking.cloud.tencent.com/finalized"
+	ForceCleanupKey              = "networking.cloud.tencent.com/force-cleanup"
+	AllowDeleteInUseKey          = "networking.cloud.tencent.com/allow-delete-in-use"
+	CLBDrainSecondsKey           = "networking.cloud.tencent.com/clb-drain-seconds"
+	DrainStartTimeKey            = "networking.cloud.tencent.com/drain-start-time"
+	CLBWaitBackendReadyKey       = "networking.cloud.tencent.com/clb-wait-backend-ready"
+	InjectCLBReadinessGateKey    = "networking.cloud.tencent.com/inject-clb-readiness-gate"
+	CLBBoundConditionType        = "networking.cloud.tencent.com/clb-bound"
+	CLBPortSchedulingGate        = "networking.cloud.tencent.com/clb-port-allocated"
+	ImportedKey                  = "networking.cloud.tencent.com/imported"
+	ClaimCLBBindingKey           = "networking.cloud.tencent.com/claim-clb-binding"
+	ClaimedByKey                 = "networking.cloud.tencent.com/claimed-by"
+	PreviousUIDsKey              = "networking.cloud.tencent.com/previous-uids"
+	CLBPortPoolLabelKey          = "networking.cloud.tencent.com/clb-port-pool"
+	CLBBindingUIDLabelKey        = "networking.cloud.tencent.com/clb-binding-uid"
+	CLBBindingTypeLabelKey       = "networking.cloud.tencent.com/clb-binding-type"
+	CLBBindingKey                = "networking.cloud.tencent.com/clb-binding"
+	DedicatedCLBServiceLabelKey  = "networking.cloud.tencent.com/dedicated-clb-service"
+	CLBWeightKey                 = "networking.cloud.tencent.com/clb-weight"
+	GatewayRouteUIDLabelKey      = "networking.cloud.tencent.com/gateway-route-uid"
+	CertIdKey                    = "networking.cloud.tencent.com/cert-id"
+	CertFingerprintKey           = "networking.cloud.tencent.com/cert-fingerprint"
+	StaleCertIdsKey              = "networking.cloud.tencent.com/stale-cert-ids"
+	CACertIdKey                  = "networking.cloud.tencent.com/ca-cert-id"
+	CACertFingerprintKey         = "networking.cloud.tencent.com/ca-cert-fingerprint"
+	ManagedCertLabelKey          = "networking.cloud.tencent.com/managed-cert"
+	GatewayControllerName        = "networking.cloud.tencent.com/tke-extend-network-controller"
+	CLBPortPoolAddressType       = "networking.cloud.tencent.com/CLBPortPool"
 	ProtocolTCP                  = "TCP"
 	ProtocolUDP                  = "UDP"
 	ProtocolTCPUDP               = "TCPUDP"
+	ProtocolHTTP                 = "HTTP"
+	ProtocolHTTPS                = "HTTPS"
 	OKGNetworkType               = "TencentCloud-CLB"
 	AgonesGameServerLabelKey     = "agones.dev/gameserver"
 
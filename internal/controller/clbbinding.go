@@ -1,14 +1,11 @@
 package controller
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"reflect"
 	"slices"
-	"strconv"
 	"strings"
 	"time"
 
@@ -18,17 +15,21 @@ import (
 	"github.com/tkestack/tke-extend-network-controller/internal/constant"
 	"go.uber.org/multierr"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"github.com/pkg/errors"
 	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
 	"github.com/tkestack/tke-extend-network-controller/internal/clbbinding"
+	"github.com/tkestack/tke-extend-network-controller/internal/cleanupqueue"
 	"github.com/tkestack/tke-extend-network-controller/internal/portpool"
 	"github.com/tkestack/tke-extend-network-controller/pkg/clb"
 	"github.com/tkestack/tke-extend-network-controller/pkg/clusterinfo"
@@ -50,6 +51,13 @@ type portKey struct {
 }
 
 func (r *CLBBindingReconciler[T]) sync(ctx context.Context, bd T) (result ctrl.Result, err error) {
+	// 后端正在删除，摘流期间不再绑定后端
+	if draining, remain, err := r.ensureBackendDrained(ctx, bd); err != nil {
+		return result, errors.WithStack(err)
+	} else if draining {
+		result.RequeueAfter = remain
+		return result, nil
+	}
 	spec := bd.GetSpec()
 	if spec.Disabled != nil && *spec.Disabled {
 		if err := r.ensureState(ctx, bd, networkingv1alpha1.CLBBindingStateDisabled); err != nil {
@@ -58,6 +66,10 @@ func (r *CLBBindingReconciler[T]) sync(ctx context.Context, bd T) (result ctrl.R
 		if err := r.ensureUnbound(ctx, bd); err != nil {
 			return result, errors.WithStack(err)
 		}
+		// 网络隔离不需要分配端口，放行调度
+		if err := r.ensureSchedulingGateRemoved(ctx, bd); err != nil {
+			return result, errors.WithStack(err)
+		}
 		return
 	}
 	status := bd.GetStatus()
@@ -76,6 +88,10 @@ func (r *CLBBindingReconciler[T]) sync(ctx context.Context, bd T) (result ctrl.R
 			log.FromContext(ctx).Info("lb info not found in pool yet, will retry", "err", err)
 			result.RequeueAfter = 20 * time.Microsecond
 			return result, nil
+		case ErrCLBListenerDeleting: // 端口上一个使用者的 CLBListener 还在删除中，等待删除完成
+			log.FromContext(ctx).Info("wait clblistener to be deleted, will retry", "err", err)
+			result.RequeueAfter = time.Second
+			return result, nil
 		case portpool.ErrPortPoolNotAllocatable: // 端口池不可用
 			if err := r.ensureState(ctx, bd, networkingv1alpha1.CLBBindingStatePortPoolNotAllocatable); err != nil {
 				return result, errors.WithStack(err)
@@ -137,7 +153,23 @@ func (r *CLBBindingReconciler[T]) sync(ctx context.Context, bd T) (result ctrl.R
 }
 
 func (r *CLBBindingReconciler[T]) ensureUnbound(ctx context.Context, bd clbbinding.CLBBinding) error {
+	if UseCLBListener {
+		if err := r.updateCLBListenerBackends(ctx, bd, func([]networkingv1alpha1.CLBListenerBackend) []networkingv1alpha1.CLBListenerBackend {
+			return nil
+		}); err != nil {
+			return errors.WithStack(err)
+		}
+	}
 	for _, binding := range bd.GetStatus().PortBindings {
+		if util.IsLayer7Protocol(binding.Protocol) { // 七层端口绑定解绑转发规则上的后端
+			if err := deregisterLayer7PortBinding(ctx, &binding); err != nil {
+				return errors.WithStack(err)
+			}
+			continue
+		}
+		if UseCLBListener { // 四层端口绑定的后端由 CLBListener 控制器解绑
+			continue
+		}
 		lisId := binding.ListenerId
 		if lisId == "" {
 			continue
@@ -155,6 +187,14 @@ func (r *CLBBindingReconciler[T]) ensureCLBBinding(ctx context.Context, bd clbbi
 	if err := r.ensurePortAllocated(ctx, bd); err != nil {
 		return errors.WithStack(err)
 	}
+	// 证书 Secret 更新后，更新已分配端口的监听器证书
+	if err := r.ensureCertsRotated(ctx, bd); err != nil {
+		return errors.WithStack(err)
+	}
+	// 端口已分配，放行调度
+	if err := r.ensureSchedulingGateRemoved(ctx, bd); err != nil {
+		return errors.WithStack(err)
+	}
 	// 确保所有监听器都已创建并绑定到 backend
 	if len(bd.GetStatus().PortBindings) > 0 { // 确保要分配到了端口
 		if err := r.ensureBackendBindings(ctx, bd); err != nil {
@@ -164,9 +204,79 @@ func (r *CLBBindingReconciler[T]) ensureCLBBinding(ctx context.Context, bd clbbi
 	return nil
 }
 
-var ErrListenerNotExpected = errors.New("listener not expected")
+var (
+	ErrListenerNotExpected  = errors.New("listener not expected")
+	ErrListenerOwnedByOther = errors.New("listener owned by other clbbinding")
+)
+
+// 检查已存在的监听器是否属于当前 CLBBinding（监听器名称中记录了所属 CLBBinding 的 UID），
+// 属于自己或无法识别归属（预创建监听器、旧版本创建的监听器）时可以复用，属于其它 CLBBinding 时视为端口冲突。
+// 属于重建前的 CLBBinding 时（灾难恢复或集群迁移），将监听器名称改为当前 UID 后复用。
+func (r *CLBBindingReconciler[T]) checkListenerOwner(ctx context.Context, bd clbbinding.CLBBinding, binding *networkingv1alpha1.PortBindingStatus, lis *clb.Listener) error {
+	owner := clb.ParseListenerName(lis.ListenerName)
+	if owner == nil || owner.UID == string(bd.GetUID()) {
+		return nil
+	}
+	if clbbinding.IsPreviousUID(bd.GetObject(), owner.UID) {
+		return r.renameListener(ctx, bd, binding, lis)
+	}
+	r.Recorder.Eventf(
+		bd.GetObject(), corev1.EventTypeWarning, "ListenerConflict",
+		"listener %s (%s/%d/%s) is owned by other clbbinding (uid:%s pool:%s port:%d)",
+		lis.ListenerId, binding.LoadbalancerId, binding.LoadbalancerPort, binding.Protocol,
+		owner.UID, owner.Pool, owner.Port,
+	)
+	return errors.Wrapf(
+		ErrListenerOwnedByOther, "listener %s (%s/%d/%s) is owned by clbbinding %s",
+		lis.ListenerId, binding.LoadbalancerId, binding.LoadbalancerPort, binding.Protocol, owner.UID,
+	)
+}
+
+// 将重建前的 CLBBinding 创建的监听器名称改为当前 UID，之后按名称识别归属（端口冲突检查、强制清理）不再依赖注解中记录的原 UID
+func (r *CLBBindingReconciler[T]) renameListener(ctx context.Context, bd clbbinding.CLBBinding, binding *networkingv1alpha1.PortBindingStatus, lis *clb.Listener) error {
+	name := clb.BuildListenerName(string(bd.GetUID()), binding.Pool, binding.Port)
+	if err := clb.RenameListener(ctx, binding.Region, binding.LoadbalancerId, lis, name); err != nil {
+		return errors.WithStack(err)
+	}
+	r.Recorder.Eventf(bd.GetObject(), corev1.EventTypeNormal, "ListenerRenamed", "rename listener %s of previous clbbinding from %s to %s", lis.ListenerId, lis.ListenerName, name)
+	return nil
+}
+
+// 获取端口绑定的监听器预期的配置：端口池中的监听器模板，其中的健康检查配置会被端口配置中的健康检查配置覆盖
+func (r *CLBBindingReconciler[T]) getExpectedListenerTemplate(ctx context.Context, bd clbbinding.CLBBinding, binding *networkingv1alpha1.PortBindingStatus) (*networkingv1alpha1.ListenerTemplate, error) {
+	var tpl *networkingv1alpha1.ListenerTemplate
+	pp := &networkingv1alpha1.CLBPortPool{}
+	if err := r.Get(ctx, client.ObjectKey{Name: binding.Pool}, pp); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, errors.WithStack(err)
+		}
+	} else if pp.Spec.ListenerTemplate != nil {
+		tpl = pp.Spec.ListenerTemplate.DeepCopy()
+	}
+	if entry := findPortEntry(bd.GetSpec(), binding); entry != nil && entry.HealthCheck != nil {
+		if tpl == nil {
+			tpl = &networkingv1alpha1.ListenerTemplate{}
+		}
+		tpl.HealthCheck = clb.MergeHealthCheck(tpl.HealthCheck, entry.HealthCheck)
+	}
+	return tpl, nil
+}
+
+// 查找端口绑定对应的端口配置
+func findPortEntry(spec *networkingv1alpha1.CLBBindingSpec, binding *networkingv1alpha1.PortBindingStatus) *networkingv1alpha1.PortEntry {
+	for i := range spec.Ports {
+		entry := &spec.Ports[i]
+		if entry.Port != binding.Port || !slices.Contains(entry.Pools, binding.Pool) {
+			continue
+		}
+		if entry.Protocol == binding.Protocol || (entry.Protocol == constant.ProtocolTCPUDP && (binding.Protocol == constant.ProtocolTCP || binding.Protocol == constant.ProtocolUDP)) {
+			return entry
+		}
+	}
+	return nil
+}
 
-func (r *CLBBindingReconciler[T]) ensureListenerExpected(ctx context.Context, binding *networkingv1alpha1.PortBindingStatus, lis *clb.Listener) (*networkingv1alpha1.PortBindingStatus, error) {
+func (r *CLBBindingReconciler[T]) ensureListenerExpected(ctx context.Context, bd clbbinding.CLBBinding, binding *networkingv1alpha1.PortBindingStatus, lis *clb.Listener) (*networkingv1alpha1.PortBindingStatus, error) {
 	if lis.Port != int64(binding.LoadbalancerPort) || lis.EndPort != int64(util.GetValue(binding.LoadbalancerEndPort)) || lis.Protocol != binding.Protocol { // 不符预期，删除监听器
 		if err := clb.DeleteListenerById(ctx, binding.Region, binding.LoadbalancerId, lis.ListenerId); err != nil {
 			if clb.IsLoadBalancerNotExistsError(err) { // lb 不存在，删除 binding，清理缓存
@@ -184,26 +294,53 @@ func (r *CLBBindingReconciler[T]) ensureListenerExpected(ctx context.Context, bi
 		}
 		return binding, ErrListenerNotExpected
 	}
-	// 符合预期
+	// 端口和协议符合预期
 	if binding.ListenerId != lis.ListenerId { // 但监听器 ID 不一样，更新一下
-		updateListener(binding, lis.ListenerId)
+		updateListener(binding, lis.ListenerId, lis.ListenerName)
+	}
+	// 对账监听器的配置（只有从 CLB API 查询到的监听器才有详情）
+	if lis.Detail != nil {
+		tpl, err := r.getExpectedListenerTemplate(ctx, bd, binding)
+		if err != nil {
+			return binding, errors.WithStack(err)
+		}
+		if drift := clb.GetListenerDrift(lis.Detail, tpl, binding.Protocol); len(drift) > 0 {
+			if err := clb.ModifyListener(ctx, binding.Region, binding.LoadbalancerId, lis.ListenerId, binding.Protocol, tpl); err != nil {
+				return binding, errors.WithStack(err)
+			}
+			r.Recorder.Eventf(
+				bd.GetObject(), corev1.EventTypeNormal, "ListenerUpdated",
+				"update %s of listener %s (%s/%d/%s)",
+				strings.Join(drift, ","), lis.ListenerId, binding.LoadbalancerId, binding.LoadbalancerPort, binding.Protocol,
+			)
+		}
 	}
 	return binding, nil
 }
 
-func updateListener(binding *networkingv1alpha1.PortBindingStatus, lisId string) {
+func updateListener(binding *networkingv1alpha1.PortBindingStatus, lisId, lisName string) {
 	binding.ListenerId = lisId
 	lis := &clb.Listener{
 		Port:         int64(binding.LoadbalancerPort),
 		EndPort:      int64(util.GetValue(binding.LoadbalancerEndPort)),
 		Protocol:     binding.Protocol,
 		ListenerId:   lisId,
-		ListenerName: clb.TkeListenerName,
+		ListenerName: lisName,
 	}
 	clb.GetListenerCache(clb.LBKey{LbId: binding.LoadbalancerId, Region: binding.Region}).Set(lis)
 }
 
 func (r *CLBBindingReconciler[T]) createListener(ctx context.Context, bd clbbinding.CLBBinding, binding *networkingv1alpha1.PortBindingStatus, log logr.Logger) (*networkingv1alpha1.PortBindingStatus, error) {
+	// 监听器名称中记录所属 CLBBinding 的 UID、端口池和端口，重试时可识别并复用自己之前创建的监听器
+	lisName := clb.BuildListenerName(string(bd.GetUID()), binding.Pool, binding.Port)
+	tpl, err := r.getExpectedListenerTemplate(ctx, bd, binding)
+	if err != nil {
+		return binding, errors.WithStack(err)
+	}
+	extensiveParameters, err := clb.BuildExtensiveParameters(tpl, binding.Protocol)
+	if err != nil {
+		return binding, errors.WithStack(err)
+	}
 	createListener := func() (lisId string, err error) {
 		lisId, err = clb.CreateListenerTryBatch(
 			ctx,
@@ -212,8 +349,9 @@ func (r *CLBBindingReconciler[T]) createListener(ctx context.Context, bd clbbind
 			int64(binding.LoadbalancerPort),
 			int64(util.GetValue(binding.LoadbalancerEndPort)),
 			binding.Protocol,
-			binding.CertId,
-			"",
+			clb.NewCertificate(binding.CertId, binding.ExtraCertIds, binding.CACertId),
+			extensiveParameters,
+			lisName,
 		)
 		if err != nil {
 			log.Error(err, "create listener failed", "listenerId", lisId)
@@ -225,7 +363,7 @@ func (r *CLBBindingReconciler[T]) createListener(ctx context.Context, bd clbbind
 	}
 	lisId, err := createListener()
 	if err == nil { // 创建成功，记录最新的监听器 ID 并更新监听器缓存
-		updateListener(binding, lisId)
+		updateListener(binding, lisId, lisName)
 		return binding, nil
 	}
 	// 创建失败，判断错误
@@ -249,13 +387,17 @@ func (r *CLBBindingReconciler[T]) createListener(ctx context.Context, bd clbbind
 			if lisId, err := createListener(); err != nil {
 				return binding, errors.WithStack(err)
 			} else { // 重试创建成功，记录最新的监听器 ID
-				updateListener(binding, lisId)
+				updateListener(binding, lisId, lisName)
 				log.Info("retry create listener successfully", "listenerId", lisId)
 				return binding, nil
 			}
 		} else { // 存在
+			// 检查是否被其它 CLBBinding 占用
+			if err := r.checkListenerOwner(ctx, bd, binding, lis); err != nil {
+				return binding, errors.WithStack(err)
+			}
 			// 检查是否符合预期
-			binding, err = r.ensureListenerExpected(ctx, binding, lis)
+			binding, err = r.ensureListenerExpected(ctx, bd, binding, lis)
 			if err != nil {
 				return binding, errors.WithStack(err)
 			}
@@ -273,7 +415,7 @@ func (r *CLBBindingReconciler[T]) ensureListener(ctx context.Context, bd clbbind
 	// 如果 lb 已被移除，且当前还未绑定成功，则移除该端口绑定，等待重新分配端口（配错 lb导致一直无法绑定成功，更正后，可以触发重新分配以便能够成功绑定）
 	if bd.GetStatus().State != networkingv1alpha1.CLBBindingStateBound && !portpool.Allocator.IsLbExists(binding.Pool, portpool.NewLBKeyFromBinding(binding)) {
 		log.Info("remove allocated clbbinding due to lb not exists")
-		r.Recorder.Eventf(bd.GetObject(), corev1.EventTypeNormal, "PortBindingRemoved", "lb %q not exists, remove port binding (lbPort:%s protocol:%s)", binding.LoadbalancerId, binding.LoadbalancerPort, binding.Protocol)
+		r.Recorder.Eventf(bd.GetObject(), corev1.EventTypeNormal, "PortBindingRemoved", "lb %q not exists, remove port binding (lbPort:%d protocol:%s)", binding.LoadbalancerId, binding.LoadbalancerPort, binding.Protocol)
 		// 确保监听器被清理（pool 可能已被删除，无法获取预创建状态，传 false 直接删除监听器）
 		if err := r.cleanupPortBinding(ctx, binding, log, false); err != nil {
 			return binding, errors.WithStack(err)
@@ -321,6 +463,9 @@ func (r *CLBBindingReconciler[T]) ensureListener(ctx context.Context, bd clbbind
 			return nil, errors.WithStack(err)
 		}
 		if lis != nil { // 可能是之前创建了监听器，但记录到 status 失败了
+			if err := r.checkListenerOwner(ctx, bd, binding, lis); err != nil {
+				return binding, errors.WithStack(err)
+			}
 			binding.ListenerId = lis.ListenerId
 		}
 	}
@@ -376,7 +521,10 @@ func (r *CLBBindingReconciler[T]) ensureListener(ctx context.Context, bd clbbind
 		}
 		if lis != nil { // 如果存在，检测已有监听器是否符合预期
 			log.V(3).Info("listener found use port and protocol")
-			binding, err = r.ensureListenerExpected(ctx, binding, lis)
+			if err := r.checkListenerOwner(ctx, bd, binding, lis); err != nil {
+				return binding, errors.WithStack(err)
+			}
+			binding, err = r.ensureListenerExpected(ctx, bd, binding, lis)
 			if err != nil {
 				return binding, errors.WithStack(err)
 			}
@@ -402,8 +550,13 @@ func (r *CLBBindingReconciler[T]) ensureListener(ctx context.Context, bd clbbind
 			return binding, nil
 		}
 	} else { // 通过 ID 查到了监听器，对比是否符合预期
+		if owner := clb.ParseListenerName(lis.ListenerName); owner != nil && clbbinding.IsPreviousUID(bd.GetObject(), owner.UID) {
+			if err := r.renameListener(ctx, bd, binding, lis); err != nil {
+				return binding, errors.WithStack(err)
+			}
+		}
 		log.V(3).Info("found listener id, ensureListenerExpected", "lis", lis)
-		binding, err = r.ensureListenerExpected(ctx, binding, lis)
+		binding, err = r.ensureListenerExpected(ctx, bd, binding, lis)
 		if err != nil {
 			return binding, errors.WithStack(err)
 		}
@@ -470,6 +623,31 @@ func (r *CLBBindingReconciler[T]) ensureBackendBindings(ctx context.Context, bd
 		needBind = false
 	}
 
+	// 启用了等待后端就绪，后端未就绪时不绑定（端口保持分配），已绑定的后端将权重设为 0
+	waitReady := false
+	if needBind {
+		wait, portName, err := r.getWaitBackendReady(ctx, bd)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if wait && !backend.IsReady(portName) {
+			if status.State != networkingv1alpha1.CLBBindingStateWaitBackendReady {
+				r.Recorder.Event(bd.GetObject(), corev1.EventTypeNormal, "WaitBackendReady", "wait backend to be ready")
+			}
+			if err = r.ensureState(ctx, bd, networkingv1alpha1.CLBBindingStateWaitBackendReady); err != nil {
+				return errors.WithStack(err)
+			}
+			log.FromContext(ctx).V(1).Info("not bind backend due to backend not ready")
+			needBind = false
+			waitReady = true
+		}
+	}
+
+	// 由 CLBListener 控制器管理监听器和后端
+	if UseCLBListener {
+		return r.ensureCLBListeners(ctx, bd, backend, needBind, waitReady)
+	}
+
 	// rs 准备就绪，确保 CLB 监听器创建并绑定到 rs
 	type Result struct {
 		Binding *networkingv1alpha1.PortBindingStatus
@@ -478,17 +656,24 @@ func (r *CLBBindingReconciler[T]) ensureBackendBindings(ctx context.Context, bd
 	result := make(chan Result)
 	for i := range status.PortBindings { // 遍历所有 binding
 		go func(binding *networkingv1alpha1.PortBindingStatus) {
+			if util.IsLayer7Protocol(binding.Protocol) { // 七层端口绑定使用共享监听器上的转发规则
+				binding, err := r.ensureLayer7Binding(ctx, bd, backend, binding, needBind, waitReady)
+				result <- Result{Binding: binding, Err: err}
+				return
+			}
 			// 确保 listener 创建并符合预期
 			binding, err := r.ensureListener(ctx, bd, binding)
 			// 如果 listener 无误、当前 binding 不需要被清理、且 rs 有 IP，那么确保 listener 要绑定到 rs
 			if needBind && err == nil && binding != nil {
 				err = r.ensurePortBound(ctx, bd, backend, binding)
+			} else if waitReady && err == nil && binding != nil {
+				err = drainPortBinding(ctx, binding)
 			}
 			result <- Result{Binding: binding, Err: err}
 		}(status.PortBindings[i].DeepCopy())
 	}
 
-	// 构造对账后的 bindings，如有变化，更新到 status
+	// 构造对账后的 bindings
 	bindings := []networkingv1alpha1.PortBindingStatus{}
 	for range status.PortBindings {
 		r := <-result
@@ -499,6 +684,12 @@ func (r *CLBBindingReconciler[T]) ensureBackendBindings(ctx context.Context, bd
 			bindings = append(bindings, *r.Binding)
 		}
 	}
+	return r.ensureBindingsResult(ctx, bd, backend, bindings, needBind, err)
+}
+
+// 将对账后的 bindings 更新到 status，如果所有端口都已绑定（bound 为 true 且没有错误），更新状态为 Bound 并将绑定信息写入 backend 注解
+func (r *CLBBindingReconciler[T]) ensureBindingsResult(ctx context.Context, bd clbbinding.CLBBinding, backend clbbinding.Backend, bindings []networkingv1alpha1.PortBindingStatus, bound bool, err error) error {
+	status := bd.GetStatus()
 	clbbinding.SortPortBindings(bindings)
 	if !reflect.DeepEqual(bindings, status.PortBindings) { // 有变化，更新到 status
 		err := util.RetryIfPossible(func() error { // 确保更新成功，避免丢失已创建的 listenerId，导致需要更多的查询判断，拖慢速度
@@ -517,8 +708,8 @@ func (r *CLBBindingReconciler[T]) ensureBackendBindings(ctx context.Context, bd
 	if err != nil {
 		return errors.WithStack(err)
 	}
-	// 还不需要绑定，不可能更新 Bound 状态和写入注解，直接返回
-	if !needBind {
+	// 还不需要绑定或还未绑定完成，不可能更新 Bound 状态和写入注解，直接返回
+	if !bound {
 		return nil
 	}
 
@@ -614,7 +805,9 @@ func (r *CLBBindingReconciler[T]) ensureBackendStatusAnnotation(ctx context.Cont
 		if address == "" && len(status.Ips) > 0 {
 			address = status.Ips[0]
 		}
-		if address != "" {
+		if util.IsLayer7Protocol(binding.Protocol) { // 七层端口绑定的访问地址为转发规则的 URL
+			address = layer7Address(&binding)
+		} else if address != "" {
 			address = fmt.Sprintf("%s:%d", address, binding.LoadbalancerPort)
 		}
 		statuses = append(statuses, PortBindingStatus{
@@ -710,25 +903,24 @@ func (r *CLBBindingReconciler[T]) ensurePortBound(ctx context.Context, bd clbbin
 	if err != nil {
 		return errors.WithStack(err)
 	}
-	// 根据 CLB 的 IP 版本选择后端 IP 地址
-	backendIP := backend.GetIP() // 默认使用 IPv4
-	// 如果 binding 的 AddressIPVersion 为空（兼容旧数据），查询 CLB 的 IP 版本
-	addressIPVersion := binding.AddressIPVersion
-	if addressIPVersion == nil {
-		addressIPVersion = clb.GetLBAddressIPVersion(ctx, binding.LoadbalancerId, binding.Region)
-	}
-	if util.IsIPv6LB(addressIPVersion) && backend.GetIPv6() != "" {
-		backendIP = backend.GetIPv6()
-	}
 	backendTarget := clb.Target{
-		TargetIP:   backendIP,
+		TargetIP:   getBackendIP(ctx, backend, binding),
 		TargetPort: int64(binding.Port),
 	}
 	targetToDelete := []*clb.Target{}
 	alreadyAdded := false
 	for _, target := range targets {
-		if *target == backendTarget {
+		if target.IsSameBackend(backendTarget) {
 			alreadyAdded = true
+			// 权重为 0 通常是上次解绑前摘流后后端被重新绑定，恢复摘流前的权重
+			if target.Weight != nil && *target.Weight == 0 {
+				weight := originalWeight(binding)
+				r.Recorder.Eventf(bd.GetObject(), corev1.EventTypeNormal, "RestoreTargetWeight", "restore weight of target %s to %d", target, weight)
+				if err := clb.ModifyTargetWeight(ctx, binding.Region, binding.LoadbalancerId, binding.ListenerId, weight, target); err != nil {
+					return errors.WithStack(err)
+				}
+			}
+			binding.OriginalWeight = nil
 		} else {
 			targetToDelete = append(targetToDelete, target)
 		}
@@ -774,6 +966,20 @@ func (r *CLBBindingReconciler[T]) ensurePortBound(ctx context.Context, bd clbbin
 	return nil
 }
 
+// 根据 CLB 的 IP 版本选择后端 IP 地址
+func getBackendIP(ctx context.Context, backend clbbinding.Backend, binding *networkingv1alpha1.PortBindingStatus) string {
+	backendIP := backend.GetIP() // 默认使用 IPv4
+	// 如果 binding 的 AddressIPVersion 为空（兼容旧数据），查询 CLB 的 IP 版本
+	addressIPVersion := binding.AddressIPVersion
+	if addressIPVersion == nil {
+		addressIPVersion = clb.GetLBAddressIPVersion(ctx, binding.LoadbalancerId, binding.Region)
+	}
+	if util.IsIPv6LB(addressIPVersion) && backend.GetIPv6() != "" {
+		backendIP = backend.GetIPv6()
+	}
+	return backendIP
+}
+
 var (
 	ErrCertIdNotFound = errors.New("no cert id found from secret")
 	ErrPoolNotReady   = errors.New("pool not ready")
@@ -873,22 +1079,41 @@ LOOP_PORT:
 				continue LOOP_PORT
 			}
 		}
-		// 未分配端口，先检查证书配置
-		var certId *string
-		if secretName := port.CertSecretName; secretName != nil && *secretName != "" {
-			id, err := kube.GetCertIdFromSecret(ctx, r.Client, client.ObjectKey{
-				Namespace: bd.GetNamespace(),
-				Name:      *secretName,
-			})
+		// 七层协议不分配端口，而是分配共享监听器，在共享监听器上创建转发规则
+		if util.IsLayer7Protocol(port.Protocol) {
+			allocated, err := portpool.Allocator.AllocateLayer7(ctx, port.Pools, port.Protocol)
+			log.FromContext(ctx).V(3).Info("allocate layer7 listener", "allocated", allocated.String(), "protocol", port.Protocol, "pools", port.Pools, "err", err)
 			if err != nil {
 				releasePorts()
-				if apierrors.IsNotFound(errors.Cause(err)) {
-					r.Recorder.Eventf(bd.GetObject(), corev1.EventTypeWarning, "CertNotFound", "cert secret %q not found", *secretName)
-					return errors.Wrapf(ErrCertIdNotFound, "cert secret %q not found", *secretName)
-				}
 				return errors.WithStack(err)
 			}
-			certId = &id
+			if len(allocated) == 0 {
+				releasePorts()
+				for _, poolName := range port.Pools {
+					r.tryRequestScaleUp(ctx, poolName)
+				}
+				return portpool.ErrNoPortAvailable
+			}
+			for _, allocatedPort := range allocated {
+				binding, err := r.newLayer7PortBinding(ctx, bd, port, allocatedPort)
+				if err != nil {
+					releasePorts()
+					return errors.WithStack(err)
+				}
+				poolsShouldReconcile[allocatedPort.Name] = struct{}{}
+				newBindings = append(newBindings, *binding)
+			}
+			allocatedPorts = append(allocatedPorts, allocated...)
+			continue
+		}
+		// 未分配端口，先检查证书配置
+		certs, err := getPortCerts(ctx, r.Client, bd.GetNamespace(), &port)
+		if err != nil {
+			releasePorts()
+			if errors.Is(err, ErrCertIdNotFound) {
+				r.Recorder.Event(bd.GetObject(), corev1.EventTypeWarning, "CertNotFound", err.Error())
+			}
+			return errors.WithStack(err)
 		}
 		// 配置无误，执行分配
 		// 预创建模式下，绑定只能复用预创建的监听器。若该协议未预创建监听器，
@@ -924,7 +1149,9 @@ LOOP_PORT:
 				binding := networkingv1alpha1.PortBindingStatus{
 					Port:             port.Port,
 					Protocol:         allocatedPort.Protocol,
-					CertId:           certId,
+					CertId:           certs.CertId,
+					ExtraCertIds:     certs.ExtraCertIds,
+					CACertId:         certs.CACertId,
 					Pool:             allocatedPort.Name,
 					LoadbalancerId:   allocatedPort.LbId,
 					LoadbalancerPort: allocatedPort.Port,
@@ -969,13 +1196,17 @@ LOOP_PORT:
 // tryRequestScaleUp 尝试请求端口池扩容，检查 CLBPortPool 是否启用了自动创建且未达到上限，
 // 使用 CAS 保证并发安全，只有第一个请求成功的才通知 CLBPortPool reconcile。
 func (r *CLBBindingReconciler[T]) tryRequestScaleUp(ctx context.Context, poolName string) {
+	tryRequestScaleUp(ctx, r.Client, poolName)
+}
+
+func tryRequestScaleUp(ctx context.Context, c client.Client, poolName string) {
 	// CAS 设标记，如果已有请求在先则跳过
 	if !portpool.Allocator.RequestScaleUp(poolName) {
 		return
 	}
 	// 检查 CLBPortPool 是否可以扩容
 	cpp := &networkingv1alpha1.CLBPortPool{}
-	if err := r.Client.Get(ctx, client.ObjectKey{Name: poolName}, cpp); err != nil {
+	if err := c.Get(ctx, client.ObjectKey{Name: poolName}, cpp); err != nil {
 		log.FromContext(ctx).Error(err, "failed to get CLBPortPool for scale-up check", "pool", poolName)
 		portpool.Allocator.ResetScaleUpRequest(poolName) // 获取失败，重置标记
 		return
@@ -1000,6 +1231,69 @@ func (r *CLBBindingReconciler[T]) tryRequestScaleUp(ctx context.Context, poolNam
 	notifyPortPoolReconcile(poolName)
 }
 
+// 移除 Pod 上等待 CLB 端口分配的 scheduling gate，让 Pod 可以被调度
+func (r *CLBBindingReconciler[T]) ensureSchedulingGateRemoved(ctx context.Context, bd clbbinding.CLBBinding) error {
+	backend, err := bd.GetAssociatedObject(ctx, r.Client)
+	if err != nil {
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			return nil
+		}
+		return errors.WithStack(err)
+	}
+	pod, ok := backend.GetObject().(*corev1.Pod)
+	if !ok || !util.RemoveSchedulingGate(pod, constant.CLBPortSchedulingGate) {
+		return nil
+	}
+	if err := r.Update(ctx, pod); err != nil {
+		return errors.WithStack(err)
+	}
+	r.Recorder.Event(pod, corev1.EventTypeNormal, "SchedulingGateRemoved", "clb ports allocated, remove scheduling gate")
+	return nil
+}
+
+// 确保 Pod 的 clb-bound readiness gate 与 CLBBinding 的状态一致：Bound 时为 True，Disabled 或 Failed 时为 False，
+// 其它中间状态保持不变（readiness gate 对应的 condition 不存在时视为 False）
+func (r *CLBBindingReconciler[T]) ensureReadinessGate(ctx context.Context, bd clbbinding.CLBBinding) error {
+	status := bd.GetStatus()
+	var conditionStatus corev1.ConditionStatus
+	switch status.State {
+	case networkingv1alpha1.CLBBindingStateBound:
+		conditionStatus = corev1.ConditionTrue
+	case networkingv1alpha1.CLBBindingStateDisabled, networkingv1alpha1.CLBBindingStateFailed:
+		conditionStatus = corev1.ConditionFalse
+	default:
+		return nil
+	}
+	backend, err := bd.GetAssociatedObject(ctx, r.Client)
+	if err != nil {
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			return nil
+		}
+		return errors.WithStack(err)
+	}
+	pod, ok := backend.GetObject().(*corev1.Pod)
+	if !ok || !pod.DeletionTimestamp.IsZero() || !util.HasReadinessGate(pod, constant.CLBBoundConditionType) {
+		return nil
+	}
+	condition := util.GetPodCondition(pod, constant.CLBBoundConditionType)
+	if condition != nil && condition.Status == conditionStatus {
+		return nil
+	}
+	if condition == nil {
+		pod.Status.Conditions = append(pod.Status.Conditions, corev1.PodCondition{Type: constant.CLBBoundConditionType})
+		condition = &pod.Status.Conditions[len(pod.Status.Conditions)-1]
+	}
+	condition.Status = conditionStatus
+	condition.Reason = string(status.State)
+	condition.Message = status.Message
+	condition.LastTransitionTime = metav1.Now()
+	log.FromContext(ctx).V(3).Info("update pod readiness gate", "status", conditionStatus)
+	if err := r.Status().Update(ctx, pod); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
 func (r *CLBBindingReconciler[T]) ensureState(ctx context.Context, bd clbbinding.CLBBinding, state networkingv1alpha1.CLBBindingState) error {
 	status := bd.GetStatus()
 	if status.State == state {
@@ -1029,29 +1323,78 @@ func (r *CLBBindingReconciler[T]) cleanup(ctx context.Context, bd T) (result ctr
 	if err = r.ensureState(ctx, bd, networkingv1alpha1.CLBBindingStateDeleting); err != nil {
 		return result, errors.WithStack(err)
 	}
-	ch := make(chan error)
-	controllerutil.ContainsFinalizer(bd.GetObject(), constant.Finalizer)
-	for _, binding := range status.PortBindings {
-		go func(binding *networkingv1alpha1.PortBindingStatus) {
-			isListenerPrecreated := false
-			if pool := portpool.Allocator.GetPool(binding.Pool); pool != nil && pool.IsPrecreateListenerEnabled() {
-				isListenerPrecreated = true
+	if anno[constant.ForceCleanupKey] == "true" && UseCLBListener {
+		// 强制清理：删除 CLBListener 后不等待监听器清理完成，由 CLBListener 控制器继续重试；
+		// 没有 CLBListener 的端口绑定（七层端口绑定和启用 CLBListener 前创建的监听器）交给 GC 异步清理
+		bindings, err := r.getBindingsWithoutCLBListener(ctx, bd)
+		if err != nil {
+			return result, errors.WithStack(err)
+		}
+		if _, err := r.deleteCLBListeners(ctx, bd); err != nil {
+			return result, errors.WithStack(err)
+		}
+		if err := r.enqueueCleanup(ctx, bd, bindings); err != nil {
+			return result, errors.WithStack(err)
+		}
+		r.Recorder.Event(bd.GetObject(), corev1.EventTypeWarning, "ForceCleanup", "force cleanup, cloud resources cleanup is deferred to clblistener controller and gc")
+	} else if anno[constant.ForceCleanupKey] == "true" {
+		// 强制清理：云上资源的清理交给 GC 异步重试，不阻塞 CLBBinding 的删除（CLB API 异常或 lb 被手动删除导致清理一直失败的场景）
+		if err := r.enqueueCleanup(ctx, bd, status.PortBindings); err != nil {
+			return result, errors.WithStack(err)
+		}
+		log.Info("force cleanup, cloud resources cleanup is deferred to gc", "bindings", len(status.PortBindings))
+		r.Recorder.Event(bd.GetObject(), corev1.EventTypeWarning, "ForceCleanup", "force cleanup, cloud resources cleanup is deferred to gc")
+	} else {
+		// 解绑前先摘流，摘流完成前保留 finalizer
+		remain, err := r.ensureDrained(ctx, bd)
+		if err != nil {
+			return result, errors.WithStack(err)
+		}
+		if remain > 0 {
+			log.V(3).Info("wait targets to be drained", "remain", remain)
+			result.RequeueAfter = remain
+			return result, nil
+		}
+		if UseCLBListener {
+			// 删除 CLBListener，等待 CLBListener 控制器清理完监听器后再释放端口
+			remaining, err := r.deleteCLBListeners(ctx, bd)
+			if err != nil {
+				return result, errors.WithStack(err)
 			}
-			if err := r.cleanupPortBinding(ctx, binding, log, isListenerPrecreated); err != nil {
-				ch <- err
-			} else {
-				ch <- nil
+			if remaining > 0 {
+				log.V(3).Info("wait clblisteners to be deleted", "remaining", remaining)
+				result.RequeueAfter = 3 * time.Second
+				return result, nil
 			}
-		}(&binding)
-	}
-	for range status.PortBindings {
-		e := <-ch
-		if e != nil {
-			err = multierr.Append(err, e)
 		}
-	}
-	if err != nil {
-		return result, errors.WithStack(err)
+		// 使用 CLBListener 时，有 CLBListener 的监听器此时已被 CLBListener 控制器清理，再次清理时查不到监听器会被忽略；
+		// 但启用 CLBListener 前创建、还未迁移到 CLBListener 的监听器（CLBBinding 在迁移前就被删除）没有 CLBListener，
+		// 仍需直接清理，所以这里总是清理所有端口绑定
+		toCleanup := status.PortBindings
+		ch := make(chan error)
+		controllerutil.ContainsFinalizer(bd.GetObject(), constant.Finalizer)
+		for _, binding := range toCleanup {
+			go func(binding *networkingv1alpha1.PortBindingStatus) {
+				isListenerPrecreated := false
+				if pool := portpool.Allocator.GetPool(binding.Pool); pool != nil && pool.IsPrecreateListenerEnabled() {
+					isListenerPrecreated = true
+				}
+				if err := r.cleanupPortBinding(ctx, binding, log, isListenerPrecreated); err != nil {
+					ch <- err
+				} else {
+					ch <- nil
+				}
+			}(&binding)
+		}
+		for range toCleanup {
+			e := <-ch
+			if e != nil {
+				err = multierr.Append(err, e)
+			}
+		}
+		if err != nil {
+			return result, errors.WithStack(err)
+		}
 	}
 	// 全部解绑完成，打上标记，避免重复释放端口导致冲突（比如刚释放完端口又被其它 pod 分配，然后再次进入cleanup时又被清理，有可能再次分配给其它 pod，导致相同ip:port被重复分配）
 	anno[constant.FinalizedKey] = "true"
@@ -1078,6 +1421,10 @@ func (r *CLBBindingReconciler[T]) cleanup(ctx context.Context, bd T) (result ctr
 		}
 		return result, errors.WithStack(err)
 	}
+	// CLBBinding 已清理完成，不再需要阻止后端删除
+	if err := updateDrainFinalizer(ctx, backend, false); err != nil {
+		return result, errors.WithStack(err)
+	}
 	if !backend.GetDeletionTimestamp().IsZero() { // 忽略正在删除的后端
 		return result, nil
 	}
@@ -1086,11 +1433,302 @@ func (r *CLBBindingReconciler[T]) cleanup(ctx context.Context, bd T) (result ctr
 	return result, nil
 }
 
+// 获取 CLBBinding 已分配端口所在的端口池，忽略不存在的端口池
+func (r *CLBBindingReconciler[T]) getBoundPools(ctx context.Context, bd clbbinding.CLBBinding) ([]*networkingv1alpha1.CLBPortPool, error) {
+	pools := []*networkingv1alpha1.CLBPortPool{}
+	visited := make(map[string]bool)
+	for _, binding := range bd.GetStatus().PortBindings {
+		if visited[binding.Pool] {
+			continue
+		}
+		visited[binding.Pool] = true
+		pp := &networkingv1alpha1.CLBPortPool{}
+		if err := r.Get(ctx, client.ObjectKey{Name: binding.Pool}, pp); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, errors.WithStack(err)
+		}
+		pools = append(pools, pp)
+	}
+	return pools, nil
+}
+
+// 获取解绑前的摘流时间：优先使用 CLBBinding 中的配置（来自 Pod/Node 注解），否则取所用端口池中配置的最大值
+func (r *CLBBindingReconciler[T]) getDrainSeconds(ctx context.Context, bd clbbinding.CLBBinding) (int64, error) {
+	if drainSeconds := bd.GetSpec().DrainSeconds; drainSeconds != nil {
+		return *drainSeconds, nil
+	}
+	pools, err := r.getBoundPools(ctx, bd)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	var drainSeconds int64
+	for _, pp := range pools {
+		if pp.Spec.DrainSeconds != nil && *pp.Spec.DrainSeconds > drainSeconds {
+			drainSeconds = *pp.Spec.DrainSeconds
+		}
+	}
+	return drainSeconds, nil
+}
+
+// 获取是否需要等待后端就绪后再绑定，以及就绪检查的容器端口名称：优先使用 CLBBinding 中的配置（来自 Pod/Node 注解），
+// 否则只要有一个所用端口池启用了 waitBackendReady 就需要等待
+func (r *CLBBindingReconciler[T]) getWaitBackendReady(ctx context.Context, bd clbbinding.CLBBinding) (bool, string, error) {
+	spec := bd.GetSpec()
+	if spec.WaitBackendReady != nil {
+		return *spec.WaitBackendReady, util.GetValue(spec.ReadinessPortName), nil
+	}
+	pools, err := r.getBoundPools(ctx, bd)
+	if err != nil {
+		return false, "", errors.WithStack(err)
+	}
+	for _, pp := range pools {
+		if pp.Spec.WaitBackendReady != nil && *pp.Spec.WaitBackendReady {
+			return true, "", nil
+		}
+	}
+	return false, "", nil
+}
+
+// 解绑前摘流：将已绑定的后端权重设为 0，让 CLB 不再转发新连接，并记录开始摘流的时间，返回还需等待的时间
+func (r *CLBBindingReconciler[T]) ensureDrained(ctx context.Context, bd clbbinding.CLBBinding) (time.Duration, error) {
+	drainSeconds, err := r.getDrainSeconds(ctx, bd)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	if drainSeconds <= 0 {
+		return 0, nil
+	}
+	drainPeriod := time.Duration(drainSeconds) * time.Second
+	anno := bd.GetAnnotations()
+	if anno == nil {
+		anno = make(map[string]string)
+	}
+	if v := anno[constant.DrainStartTimeKey]; v != "" {
+		if startTime, err := time.Parse(time.RFC3339, v); err == nil { // 已经在摘流，等待摘流时间结束
+			return max(time.Until(startTime.Add(drainPeriod)), 0), nil
+		}
+	}
+	if UseCLBListener {
+		if err := r.updateCLBListenerBackends(ctx, bd, drainCLBListenerBackends); err != nil {
+			return 0, errors.WithStack(err)
+		}
+	}
+	status := bd.GetStatus()
+	bindings := make([]networkingv1alpha1.PortBindingStatus, len(status.PortBindings))
+	for i := range status.PortBindings {
+		binding := status.PortBindings[i].DeepCopy()
+		if !UseCLBListener || util.IsLayer7Protocol(binding.Protocol) { // 四层端口在 CLBListener 模式下已由 CLBListener 控制器摘流
+			if err := drainPortBinding(ctx, binding); err != nil {
+				return 0, errors.WithStack(err)
+			}
+		}
+		bindings[i] = *binding
+	}
+	// 保存摘流前的权重，用于后端重新绑定时恢复
+	if !reflect.DeepEqual(bindings, status.PortBindings) {
+		status.PortBindings = bindings
+		if err := r.Status().Update(ctx, bd.GetObject()); err != nil {
+			return 0, errors.WithStack(err)
+		}
+	}
+	anno = bd.GetAnnotations()
+	if anno == nil {
+		anno = make(map[string]string)
+	}
+	anno[constant.DrainStartTimeKey] = time.Now().Format(time.RFC3339)
+	bd.SetAnnotations(anno)
+	if err := r.Update(ctx, bd.GetObject()); err != nil {
+		return 0, errors.WithStack(err)
+	}
+	r.Recorder.Eventf(bd.GetObject(), corev1.EventTypeNormal, "Draining", "set weight of targets to 0, deregister after %ds", drainSeconds)
+	return drainPeriod, nil
+}
+
+// 摘流失败时，超过摘流时间后最多再重试的时长
+const drainTimeout = 5 * time.Minute
+
+// 后端（Pod/Node）开始删除时立即摘流，而不是等到 CLBBinding 被 GC 删除（此时 Pod 已删除，摘流已无意义）。
+// 配置了摘流时间时给 Pod 加上 finalizer，摘流时间结束后才移除，让 Pod 在摘流期间保持存在。
+// 返回后端是否正在摘流，以及还需等待的时间。
+func (r *CLBBindingReconciler[T]) ensureBackendDrained(ctx context.Context, bd clbbinding.CLBBinding) (bool, time.Duration, error) {
+	backend, err := bd.GetAssociatedObject(ctx, r.Client)
+	if err != nil {
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			return false, 0, nil
+		}
+		return false, 0, errors.WithStack(err)
+	}
+	var drainSeconds int64
+	if !util.GetValue(bd.GetSpec().Disabled) && len(bd.GetStatus().PortBindings) > 0 {
+		if drainSeconds, err = r.getDrainSeconds(ctx, bd); err != nil {
+			return false, 0, errors.WithStack(err)
+		}
+	}
+	if backend.GetDeletionTimestamp().IsZero() {
+		if err := updateDrainFinalizer(ctx, backend, drainSeconds > 0); err != nil {
+			return false, 0, errors.WithStack(err)
+		}
+		return false, 0, nil
+	}
+	if drainSeconds <= 0 {
+		return false, 0, errors.WithStack(updateDrainFinalizer(ctx, backend, false))
+	}
+	remain, err := r.ensureDrained(ctx, bd)
+	if err != nil {
+		// 摘流持续失败（如云 API 异常）时不能无限期阻止 Pod 删除，超过摘流时间一段时间后放弃摘流，放行后端删除
+		if deadline := backend.GetDeletionTimestamp().Add(time.Duration(drainSeconds)*time.Second + drainTimeout); time.Now().After(deadline) {
+			r.Recorder.Eventf(bd.GetObject(), corev1.EventTypeWarning, "DrainTimeout", "give up draining targets: %s", err.Error())
+			return true, 0, errors.WithStack(updateDrainFinalizer(ctx, backend, false))
+		}
+		return true, 0, errors.WithStack(err)
+	}
+	if remain > 0 {
+		log.FromContext(ctx).V(3).Info("backend is deleting, wait targets to be drained", "remain", remain)
+		return true, remain, nil
+	}
+	// 摘流结束，放行后端删除，CLBBinding 随后被 GC 删除并解绑
+	if err := updateDrainFinalizer(ctx, backend, false); err != nil {
+		return true, 0, errors.WithStack(err)
+	}
+	return true, 0, nil
+}
+
+// 添加或移除 Pod 上用于摘流的 finalizer（Node 不加 finalizer，删除时只摘流）
+func updateDrainFinalizer(ctx context.Context, backend clbbinding.Backend, add bool) error {
+	pod, ok := backend.GetObject().(*corev1.Pod)
+	if !ok || controllerutil.ContainsFinalizer(pod, constant.DrainFinalizer) == add {
+		return nil
+	}
+	if add {
+		return errors.WithStack(kube.AddPodFinalizer(ctx, pod, constant.DrainFinalizer))
+	}
+	return errors.WithStack(kube.RemovePodFinalizer(ctx, pod, constant.DrainFinalizer))
+}
+
+// RemoveDrainFinalizers 移除所有 Pod 上用于摘流的 finalizer，用于卸载控制器后清理（否则删除中的 Pod 会一直处于 Terminating 状态），
+// 返回被移除 finalizer 的 Pod。
+func RemoveDrainFinalizers(ctx context.Context, c client.Client) ([]string, error) {
+	podList := &corev1.PodList{}
+	if err := c.List(ctx, podList); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	removed := []string{}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if !controllerutil.ContainsFinalizer(pod, constant.DrainFinalizer) {
+			continue
+		}
+		patch := client.MergeFromWithOptions(pod.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		controllerutil.RemoveFinalizer(pod, constant.DrainFinalizer)
+		if err := c.Patch(ctx, pod, patch); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return removed, errors.WithStack(err)
+		}
+		removed = append(removed, pod.Namespace+"/"+pod.Name)
+	}
+	return removed, nil
+}
+
+// 将监听器上已绑定后端的权重设为 0，让 CLB 不再向其转发新连接
+func drainPortBinding(ctx context.Context, binding *networkingv1alpha1.PortBindingStatus) error {
+	if util.IsLayer7Protocol(binding.Protocol) {
+		return drainLayer7PortBinding(ctx, binding)
+	}
+	if binding.ListenerId == "" { // 还没有监听器，无需摘流
+		return nil
+	}
+	targets, err := clb.DescribeTargetsTryBatch(ctx, binding.Region, binding.LoadbalancerId, binding.ListenerId)
+	if err != nil {
+		if clb.IsLoadBalancerNotExistsError(errors.Cause(err)) { // lb 不存在，忽略
+			return nil
+		}
+		return errors.WithStack(err)
+	}
+	targetsToDrain := []*clb.Target{}
+	for _, target := range targets {
+		if target.Weight == nil || *target.Weight != 0 {
+			targetsToDrain = append(targetsToDrain, target)
+		}
+	}
+	recordOriginalWeight(binding, targetsToDrain)
+	if err := clb.ModifyTargetWeight(ctx, binding.Region, binding.LoadbalancerId, binding.ListenerId, 0, targetsToDrain...); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// 摘流前记录后端原来的权重，用于后端重新就绪时恢复
+func recordOriginalWeight(binding *networkingv1alpha1.PortBindingStatus, targets []*clb.Target) {
+	if binding.OriginalWeight != nil {
+		return
+	}
+	for _, target := range targets {
+		if target.Weight != nil {
+			binding.OriginalWeight = util.GetPtr(*target.Weight)
+			return
+		}
+	}
+}
+
+// 获取摘流后恢复绑定时的权重：优先使用摘流前记录的权重，没有记录时使用默认权重
+func originalWeight(binding *networkingv1alpha1.PortBindingStatus) int64 {
+	if binding.OriginalWeight != nil {
+		return *binding.OriginalWeight
+	}
+	return clb.DefaultTargetWeight
+}
+
+// 将 CLBBinding 待清理的云上资源加入持久化清理队列，由 GC 异步重试清理
+func (r *CLBBindingReconciler[T]) enqueueCleanup(ctx context.Context, bd clbbinding.CLBBinding, bindings []networkingv1alpha1.PortBindingStatus) error {
+	source := bd.GetType() + "/" + bd.GetName()
+	if ns := bd.GetNamespace(); ns != "" {
+		source = bd.GetType() + "/" + ns + "/" + bd.GetName()
+	}
+	var previousUIDs []string
+	if uids := bd.GetAnnotations()[constant.PreviousUIDsKey]; uids != "" {
+		previousUIDs = strings.Split(uids, ",")
+	}
+	tasks := []*cleanupqueue.Task{}
+	for _, binding := range bindings {
+		pool := portpool.Allocator.GetPool(binding.Pool)
+		tasks = append(tasks, &cleanupqueue.Task{
+			Region:            binding.Region,
+			LbId:              binding.LoadbalancerId,
+			ListenerId:        binding.ListenerId,
+			Port:              binding.LoadbalancerPort,
+			EndPort:           util.GetValue(binding.LoadbalancerEndPort),
+			Protocol:          binding.Protocol,
+			Pool:              binding.Pool,
+			LocationId:        util.GetValue(binding.LocationId),
+			Domain:            util.GetValue(binding.Domain),
+			Url:               util.GetValue(binding.Url),
+			DeregisterOnly:    pool != nil && pool.IsPrecreateListenerEnabled(),
+			OwnerUID:          string(bd.GetUID()),
+			PreviousOwnerUIDs: previousUIDs,
+			Source:            source,
+		})
+	}
+	if err := cleanupqueue.Add(ctx, tasks...); err != nil {
+		if errors.Is(err, cleanupqueue.ErrQueueFull) { // 保留 finalizer，等待 GC 处理完队列中的任务后重试
+			r.Recorder.Event(bd.GetObject(), corev1.EventTypeWarning, "CleanupQueueFull", err.Error())
+		}
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
 // 解绑：
 // 1）预创建监听器场景：解绑 rs
 // 2）其它：删除监听器
 func (r *CLBBindingReconciler[T]) cleanupPortBinding(ctx context.Context, binding *networkingv1alpha1.PortBindingStatus, log logr.Logger, isListenerPrecreated bool) error {
 	log.V(2).Info("cleanupPortBinding")
+	if util.IsLayer7Protocol(binding.Protocol) { // 七层端口绑定只删除转发规则，共享监听器不删除
+		return cleanupLayer7PortBinding(ctx, binding, log)
+	}
 	if isListenerPrecreated { // 预创建监听器，仅解绑 rs
 		if binding.ListenerId != "" {
 			clb.DeregisterAllTargetsTryBatch(ctx, binding.Region, binding.LoadbalancerId, binding.ListenerId)
@@ -1130,83 +1768,12 @@ func (r *CLBBindingReconciler[T]) cleanupPortBinding(ctx context.Context, bindin
 	}
 }
 
-func generatePortsFromAnnotation(anno string) (ports []networkingv1alpha1.PortEntry, err error) {
-	rd := bufio.NewReader(strings.NewReader(anno))
-	for {
-		line, _, err := rd.ReadLine()
-		if err != nil {
-			if err == io.EOF {
-				break
-			}
-			return nil, err
-		}
-		fields := strings.Fields(string(line))
-		if len(fields) < 3 {
-			return nil, fmt.Errorf("invalid port mapping: %s", string(line))
-		}
-		portStr := fields[0]
-		portUint64, err := strconv.ParseUint(portStr, 10, 16)
-		if err != nil {
-			return nil, fmt.Errorf("bad port number in port mapping: %s", string(line))
-		}
-		port := uint16(portUint64)
-		protocol := fields[1]
-		pools := strings.Split(fields[2], ",")
-		var useSamePortAcrossPools *bool
-		var certSecretName *string
-		if len(fields) >= 4 {
-			options := fields[3]
-			optionList := strings.Split(options, ",")
-			for _, option := range optionList {
-				kv := strings.Split(option, "=")
-				if len(kv) == 1 {
-					switch kv[0] {
-					case "useSamePortAcrossPools":
-						b := true
-						useSamePortAcrossPools = &b
-					}
-				} else if len(kv) == 2 {
-					key := kv[0]
-					value := kv[1]
-					switch key {
-					case "certSecret":
-						certSecretName = &value
-					}
-				}
-			}
-		}
-		ports = append(ports, networkingv1alpha1.PortEntry{
-			Port:                   port,
-			Protocol:               protocol,
-			Pools:                  pools,
-			UseSamePortAcrossPools: useSamePortAcrossPools,
-			CertSecretName:         certSecretName,
-		})
-	}
-	return
-}
-
-func generateCLBBindingSpec(anno, enablePortMappings string) (*networkingv1alpha1.CLBBindingSpec, error) {
-	ports, err := generatePortsFromAnnotation(anno)
-	if err != nil {
-		return nil, errors.WithStack(err)
-	}
-	spec := &networkingv1alpha1.CLBBindingSpec{}
-	spec.Ports = ports
-	if enablePortMappings == "false" {
-		spec.Disabled = util.GetPtr(true)
-	}
-	return spec, nil
-}
-
 func (r *CLBBindingReconciler[T]) syncCLBBinding(ctx context.Context, obj client.Object, binding T) (result ctrl.Result, err error) {
 	if !obj.GetDeletionTimestamp().IsZero() { // 忽略正在删除的 Object
 		return
 	}
 	anno := obj.GetAnnotations()
 
-	portMappings := anno[constant.CLBPortMappingsKey]
-
 	bd := binding.GetObject()
 	err = r.Get(ctx, client.ObjectKeyFromObject(obj), bd)
 	// 获取 obj 的注解
@@ -1220,7 +1787,7 @@ func (r *CLBBindingReconciler[T]) syncCLBBinding(ctx context.Context, obj client
 				bd.SetName(obj.GetName())
 				bd.SetNamespace(obj.GetNamespace())
 				// 生成期望的 CLBBindingSpec
-				spec, err := generateCLBBindingSpec(portMappings, enablePortMappings)
+				spec, err := clbbinding.GenerateSpec(anno)
 				if err != nil {
 					return result, errors.Wrapf(err, "failed to generate %s spec", binding.GetType())
 				}
@@ -1241,6 +1808,10 @@ func (r *CLBBindingReconciler[T]) syncCLBBinding(ctx context.Context, obj client
 					}
 				}
 				r.Recorder.Eventf(obj, corev1.EventTypeNormal, "CreateCLBBinding", "create %s %s successfully", binding.GetType(), obj.GetName())
+				// 认领从其它集群导入的 CLBBinding，继承其端口分配
+				if err := r.ensureClaimed(ctx, obj, binding); err != nil {
+					return result, errors.WithStack(err)
+				}
 			} else { // 其它错误，直接返回错误
 				return result, errors.WithStack(err)
 			}
@@ -1266,8 +1837,16 @@ func (r *CLBBindingReconciler[T]) syncCLBBinding(ctx context.Context, obj client
 				r.Recorder.Event(obj, corev1.EventTypeNormal, "WaitCLBBindingGC", "wait old clbbinding to be deleted")
 				return result, nil
 			}
+			// 从其它集群导入的同名 CLBBinding，直接接管
+			if err := r.ensureImportedAdopted(ctx, obj, binding); err != nil {
+				return result, errors.WithStack(err)
+			}
+			// 之前认领导入的 CLBBinding 时没有完成，继续完成认领
+			if err := r.ensureClaimed(ctx, obj, binding); err != nil {
+				return result, errors.WithStack(err)
+			}
 			// CLBBinding 存在且没有被删除，对账 spec 是否符合预期
-			spec, err := generateCLBBindingSpec(portMappings, enablePortMappings)
+			spec, err := clbbinding.GenerateSpec(anno)
 			if err != nil {
 				return result, errors.Wrap(err, "failed to generate CLBBinding spec")
 			}
@@ -1297,17 +1876,118 @@ func (r *CLBBindingReconciler[T]) syncCLBBinding(ctx context.Context, obj client
 	return
 }
 
+// 接管从其它集群导入的同名 CLBBinding：添加 OwnerReference 并移除导入标记
+func (r *CLBBindingReconciler[T]) ensureImportedAdopted(ctx context.Context, obj client.Object, binding T) error {
+	anno := binding.GetAnnotations()
+	if anno[constant.ImportedKey] != "true" {
+		return nil
+	}
+	if obj.GetAnnotations()[constant.Ratain] != "true" && len(binding.GetOwnerReferences()) == 0 {
+		if err := controllerutil.SetOwnerReference(obj, binding.GetObject(), r.Scheme); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	delete(anno, constant.ImportedKey)
+	binding.SetAnnotations(anno)
+	if err := r.Update(ctx, binding.GetObject()); err != nil {
+		return errors.WithStack(err)
+	}
+	r.Recorder.Eventf(obj, corev1.EventTypeNormal, "AdoptCLBBinding", "adopt imported %s %s", binding.GetType(), binding.GetName())
+	return nil
+}
+
+// 认领通过注解指定的、从其它集群导入的 CLBBinding（Pod/Node 名称在新集群中发生了变化的场景）：
+// 新的 CLBBinding 还没有分配端口时继承被认领 CLBBinding 的端口分配，然后删除被认领的 CLBBinding
+// （删除时不清理监听器，也不释放端口）。
+func (r *CLBBindingReconciler[T]) ensureClaimed(ctx context.Context, obj client.Object, binding T) error {
+	claimName := obj.GetAnnotations()[constant.ClaimCLBBindingKey]
+	if claimName == "" || claimName == obj.GetName() {
+		return nil
+	}
+	claimed := clbbinding.Wrap(binding.GetObject().DeepCopyObject().(client.Object))
+	if err := r.Get(ctx, client.ObjectKey{Namespace: obj.GetNamespace(), Name: claimName}, claimed.GetObject()); err != nil {
+		if apierrors.IsNotFound(err) { // 不存在或已认领完成
+			return nil
+		}
+		return errors.WithStack(err)
+	}
+	anno := claimed.GetAnnotations()
+	if anno[constant.ImportedKey] != "true" || len(claimed.GetOwnerReferences()) > 0 || !claimed.GetDeletionTimestamp().IsZero() {
+		return nil
+	}
+	claimedBy := anno[constant.ClaimedByKey]
+	if claimedBy != "" && claimedBy != string(obj.GetUID()) {
+		r.Recorder.Eventf(obj, corev1.EventTypeWarning, "ClaimCLBBinding", "%s %s has been claimed by other object", claimed.GetType(), claimName)
+		return nil
+	}
+	status := binding.GetStatus()
+	if claimedBy == "" {
+		if len(status.PortBindings) > 0 { // 已经自行分配了端口，不再认领
+			return nil
+		}
+		// 标记为已被认领，避免被重复认领，删除时也不清理监听器和释放端口
+		anno[constant.ClaimedByKey] = string(obj.GetUID())
+		anno[constant.FinalizedKey] = "true"
+		claimed.SetAnnotations(anno)
+		if err := r.Update(ctx, claimed.GetObject()); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if len(status.PortBindings) == 0 { // 继承端口分配
+		status.PortBindings = claimed.GetStatus().PortBindings
+		status.State = networkingv1alpha1.CLBBindingStateAllocated
+		if err := r.Status().Update(ctx, binding.GetObject()); err != nil {
+			return errors.WithStack(err)
+		}
+	} else if !reflect.DeepEqual(status.PortBindings, claimed.GetStatus().PortBindings) {
+		// 认领过程中已自行分配了端口，放弃继承，被认领的 CLBBinding 删除时正常清理监听器和释放端口
+		delete(anno, constant.FinalizedKey)
+		claimed.SetAnnotations(anno)
+		if err := r.Update(ctx, claimed.GetObject()); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if err := r.Delete(ctx, claimed.GetObject()); err != nil && !apierrors.IsNotFound(err) {
+		return errors.WithStack(err)
+	}
+	r.Recorder.Eventf(obj, corev1.EventTypeNormal, "ClaimCLBBinding", "claim imported %s %s", claimed.GetType(), claimName)
+	return nil
+}
+
+// 端口池的监听器模板发生变化时，需要通知所有使用该端口池的 CLBBinding 对账，将变化同步到已有的监听器
+var listenerTemplateChangedPredicate = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldPool, ok := e.ObjectOld.(*networkingv1alpha1.CLBPortPool)
+		if !ok {
+			return false
+		}
+		newPool, ok := e.ObjectNew.(*networkingv1alpha1.CLBPortPool)
+		if !ok {
+			return false
+		}
+		return !reflect.DeepEqual(oldPool.Spec.ListenerTemplate, newPool.Spec.ListenerTemplate)
+	},
+}
+
+func usePortPool(portpool client.Object, spec networkingv1alpha1.CLBBindingSpec) bool {
+	for _, port := range spec.Ports {
+		if slices.Contains(port.Pools, portpool.GetName()) {
+			return true
+		}
+	}
+	return false
+}
+
 func shouldNotify(portpool client.Object, spec networkingv1alpha1.CLBBindingSpec, status networkingv1alpha1.CLBBindingStatus) bool {
 	switch status.State {
 	case "", networkingv1alpha1.CLBBindingStatePending, // 还未分配端口的状态，触发对账分配端口
 		networkingv1alpha1.CLBBindingStateNoPortAvailable,        // 分配过端口但当时端口不足，触发一次对账重新分配
 		networkingv1alpha1.CLBBindingStatePortPoolNotFound,       // 之前端口池不存在，但现在有了，触发一次对账以便分配端口。通常是 apply yaml 场景，端口池和工作负载同时创建，先后顺序不固定导致
 		networkingv1alpha1.CLBBindingStatePortPoolNotAllocatable: // 之前端口池不可分配，但现在可以分配了，触发一次对账以便分配端口。通常是端口池还未就绪，等待就绪后自动触发对账重新分配端口
-		for _, port := range spec.Ports {
-			if slices.Contains(port.Pools, portpool.GetName()) {
-				return true
-			}
-		}
+		return usePortPool(portpool, spec)
 	}
 	return false
 }
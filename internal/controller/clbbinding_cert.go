@@ -0,0 +1,175 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/pkg/errors"
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/clbbinding"
+	"github.com/tkestack/tke-extend-network-controller/pkg/clb"
+	"github.com/tkestack/tke-extend-network-controller/pkg/kube"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// CLBPodBinding 按引用的证书 Secret 名称建立的索引，用于 Secret 变化时找到需要更新证书的 CLBPodBinding。
+// CLBNodeBinding 是集群级别的资源，不支持证书 Secret（webhook 会拒绝），不需要建立索引。
+const CertSecretNameIndex = "spec.ports.certSecretName"
+
+// IndexCertSecretNames 获取 CLBPodBinding 端口配置中引用的所有证书 Secret 名称，用于建立 CertSecretNameIndex 索引
+func IndexCertSecretNames(o client.Object) []string {
+	pb := o.(*networkingv1alpha1.CLBPodBinding)
+	ret := []string{}
+	for _, port := range pb.Spec.Ports {
+		if name := util.GetValue(port.CertSecretName); name != "" {
+			ret = append(ret, name)
+		}
+		ret = append(ret, port.ExtraCertSecretNames...)
+		if name := util.GetValue(port.CACertSecretName); name != "" {
+			ret = append(ret, name)
+		}
+	}
+	return ret
+}
+
+// 端口配置引用的证书 Secret 中解析出的证书 ID
+type portCerts struct {
+	CertId       *string
+	ExtraCertIds []string
+	CACertId     *string
+}
+
+// 解析端口配置引用的服务端证书、额外的服务端证书和客户端 CA 证书，Secret 不存在时返回 ErrCertIdNotFound
+func getPortCerts(ctx context.Context, apiClient client.Client, namespace string, port *networkingv1alpha1.PortEntry) (*portCerts, error) {
+	certs := &portCerts{}
+	if util.GetValue(port.CertSecretName) == "" {
+		return certs, nil
+	}
+	get := func(secretName string, getCertId func(context.Context, client.Client, client.ObjectKey) (string, error)) (string, error) {
+		id, err := getCertId(ctx, apiClient, client.ObjectKey{Namespace: namespace, Name: secretName})
+		if err != nil {
+			if apierrors.IsNotFound(errors.Cause(err)) {
+				return "", errors.Wrapf(ErrCertIdNotFound, "cert secret %q not found", secretName)
+			}
+			return "", errors.WithStack(err)
+		}
+		return id, nil
+	}
+	certId, err := get(*port.CertSecretName, kube.GetCertIdFromSecret)
+	if err != nil {
+		return nil, err
+	}
+	certs.CertId = &certId
+	for _, secretName := range port.ExtraCertSecretNames {
+		id, err := get(secretName, kube.GetCertIdFromSecret)
+		if err != nil {
+			return nil, err
+		}
+		if id != "" {
+			certs.ExtraCertIds = append(certs.ExtraCertIds, id)
+		}
+	}
+	if secretName := util.GetValue(port.CACertSecretName); secretName != "" {
+		id, err := get(secretName, kube.GetCACertIdFromSecret)
+		if err != nil {
+			return nil, err
+		}
+		if id != "" {
+			certs.CACertId = &id
+		}
+	}
+	return certs, nil
+}
+
+// 判断端口绑定使用的证书是否与 Secret 中的证书一致
+func (c *portCerts) matches(binding *networkingv1alpha1.PortBindingStatus) bool {
+	return util.GetValue(c.CertId) == util.GetValue(binding.CertId) &&
+		util.GetValue(c.CACertId) == util.GetValue(binding.CACertId) &&
+		slices.Equal(c.ExtraCertIds, binding.ExtraCertIds)
+}
+
+func (c *portCerts) apply(binding *networkingv1alpha1.PortBindingStatus) {
+	binding.CertId = c.CertId
+	binding.ExtraCertIds = c.ExtraCertIds
+	binding.CACertId = c.CACertId
+}
+
+func (c *portCerts) String() string {
+	return clb.NewCertificate(c.CertId, c.ExtraCertIds, c.CACertId).Key()
+}
+
+// 证书 Secret 中的证书变化（证书 ID 变化，或 kubernetes.io/tls 证书续期后重新上传）时，原地更新已分配端口的监听器证书，
+// 包括额外的服务端证书和双向认证的 CA 证书，同一个 lb 上的监听器批量更新。使用 CLBListener 时只更新 status 中的证书 ID，
+// 由 CLBListener 控制器更新监听器。
+func (r *CLBBindingReconciler[T]) ensureCertsRotated(ctx context.Context, bd clbbinding.CLBBinding) error {
+	status := bd.GetStatus()
+	spec := bd.GetSpec()
+	entryCerts := make(map[*networkingv1alpha1.PortEntry]*portCerts)
+	rotated := make(map[clb.LBKey][]int)
+	newCerts := make(map[int]*portCerts)
+	for i := range status.PortBindings {
+		binding := &status.PortBindings[i]
+		if binding.CertId == nil {
+			continue
+		}
+		entry := findPortEntry(spec, binding)
+		if entry == nil || util.GetValue(entry.CertSecretName) == "" {
+			continue
+		}
+		certs, ok := entryCerts[entry]
+		if !ok {
+			c, err := getPortCerts(ctx, r.Client, bd.GetNamespace(), entry)
+			if err != nil {
+				if errors.Is(err, ErrCertIdNotFound) { // Secret 被删除，保持已使用的证书
+					log.FromContext(ctx).Info("cert secret not found, skip cert rotation", "err", err.Error())
+					entryCerts[entry] = nil
+					continue
+				}
+				return errors.WithStack(err)
+			}
+			entryCerts[entry] = c
+			certs = c
+		}
+		if certs == nil || util.GetValue(certs.CertId) == "" || certs.matches(binding) {
+			continue
+		}
+		key := clb.LBKey{LbId: binding.LoadbalancerId, Region: binding.Region}
+		rotated[key] = append(rotated[key], i)
+		newCerts[i] = certs
+	}
+	if len(rotated) == 0 {
+		return nil
+	}
+	for lbKey, indexes := range rotated {
+		certs := make(map[string]*clb.Certificate)
+		for _, i := range indexes {
+			binding := &status.PortBindings[i]
+			if binding.ListenerId != "" && !UseCLBListener {
+				c := newCerts[i]
+				certs[binding.ListenerId] = clb.NewCertificate(c.CertId, c.ExtraCertIds, c.CACertId)
+			}
+		}
+		if err := clb.ModifyListenersCert(ctx, lbKey.Region, lbKey.LbId, certs); err != nil {
+			r.Recorder.Eventf(bd.GetObject(), corev1.EventTypeWarning, "CertRotateFailed", "update cert of listeners in lb %s failed: %s", lbKey.LbId, errors.Cause(err).Error())
+			return errors.WithStack(err)
+		}
+		for _, i := range indexes {
+			binding := &status.PortBindings[i]
+			old := &portCerts{CertId: binding.CertId, ExtraCertIds: binding.ExtraCertIds, CACertId: binding.CACertId}
+			r.Recorder.Event(bd.GetObject(), corev1.EventTypeNormal, "CertRotated", fmt.Sprintf(
+				"update cert of listener %s (%s/%d/%s) from %s to %s",
+				binding.ListenerId, binding.LoadbalancerId, binding.LoadbalancerPort, binding.Protocol, old, newCerts[i],
+			))
+			newCerts[i].apply(binding)
+		}
+	}
+	if err := r.Status().Update(ctx, bd.GetObject()); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
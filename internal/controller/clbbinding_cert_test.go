@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/clbbinding"
+	"github.com/tkestack/tke-extend-network-controller/pkg/clb"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+)
+
+// 统计测试地域中各 CLB 上记录的 ModifyListener 调用次数
+func countModifyListener() map[string]int {
+	counts := map[string]int{}
+	for _, action := range clb.GetDryRunReport().Actions {
+		if action.Api == "ModifyListener" && action.Region == dryRunTestRegion {
+			counts[action.LbId] += action.Count
+		}
+	}
+	return counts
+}
+
+func newCertSecret(name, certId string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Data:       map[string][]byte{"qcloud_cert_id": []byte(certId)},
+	}
+}
+
+// 两个端口引用同一个证书 Secret，分配到 lb-1 的两个监听器和 lb-2 的一个监听器
+func newCertPodBinding(certId string) *networkingv1alpha1.CLBPodBinding {
+	entry := func(port uint16) networkingv1alpha1.PortEntry {
+		return networkingv1alpha1.PortEntry{Port: port, Protocol: "TCP_SSL", Pools: []string{"pool"}, CertSecretName: util.GetPtr("cert")}
+	}
+	binding := func(port uint16, lbId string, lbPort uint16, listenerId string) networkingv1alpha1.PortBindingStatus {
+		return networkingv1alpha1.PortBindingStatus{
+			Port: port, Protocol: "TCP_SSL", Pool: "pool", Region: dryRunTestRegion,
+			LoadbalancerId: lbId, LoadbalancerPort: lbPort, ListenerId: listenerId, CertId: util.GetPtr(certId),
+		}
+	}
+	return &networkingv1alpha1.CLBPodBinding{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-0"},
+		Spec:       networkingv1alpha1.CLBBindingSpec{Ports: []networkingv1alpha1.PortEntry{entry(443), entry(8443), entry(9443)}},
+		Status: networkingv1alpha1.CLBBindingStatus{
+			PortBindings: []networkingv1alpha1.PortBindingStatus{
+				binding(443, "lb-cert-1", 30000, "lbl-1"),
+				binding(8443, "lb-cert-1", 30001, "lbl-2"),
+				binding(9443, "lb-cert-2", 30000, "lbl-3"),
+			},
+		},
+	}
+}
+
+func TestEnsureCertsRotated(t *testing.T) {
+	enableTestDryRun(t)
+	setUseCLBListener(t, false)
+	ctx := context.Background()
+
+	t.Run("secret deleted", func(t *testing.T) {
+		pb := newCertPodBinding("cert-old")
+		c := newFakeClient(t, pb)
+		r := &CLBBindingReconciler[*clbbinding.CLBPodBinding]{Client: c, Scheme: c.Scheme(), Recorder: record.NewFakeRecorder(10)}
+		before := countModifyListener()
+		// Secret 不存在时保持监听器已使用的证书
+		if err := r.ensureCertsRotated(ctx, clbbinding.WrapCLBPodBinding(pb)); err != nil {
+			t.Fatal(err)
+		}
+		for _, binding := range pb.Status.PortBindings {
+			if util.GetValue(binding.CertId) != "cert-old" {
+				t.Errorf("expect cert of %s unchanged, got %s", binding.ListenerId, util.GetValue(binding.CertId))
+			}
+		}
+		if after := countModifyListener(); len(after) != len(before) || after["lb-cert-1"] != before["lb-cert-1"] {
+			t.Errorf("expect no listener modified, got %v", after)
+		}
+	})
+
+	t.Run("rotate by lb", func(t *testing.T) {
+		pb := newCertPodBinding("cert-old")
+		c := newFakeClient(t, pb, newCertSecret("cert", "cert-new"))
+		recorder := record.NewFakeRecorder(10)
+		r := &CLBBindingReconciler[*clbbinding.CLBPodBinding]{Client: c, Scheme: c.Scheme(), Recorder: recorder}
+		before := countModifyListener()
+		if err := r.ensureCertsRotated(ctx, clbbinding.WrapCLBPodBinding(pb)); err != nil {
+			t.Fatal(err)
+		}
+		after := countModifyListener()
+		// 同一个 lb 上的监听器一起更新，每个监听器调用一次 ModifyListener
+		if after["lb-cert-1"]-before["lb-cert-1"] != 2 || after["lb-cert-2"]-before["lb-cert-2"] != 1 {
+			t.Errorf("unexpected ModifyListener calls, before %v, after %v", before, after)
+		}
+		if len(recorder.Events) != 3 {
+			t.Errorf("expect 3 CertRotated events, got %d", len(recorder.Events))
+		}
+		saved := &networkingv1alpha1.CLBPodBinding{}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(pb), saved); err != nil {
+			t.Fatal(err)
+		}
+		for _, binding := range saved.Status.PortBindings {
+			if util.GetValue(binding.CertId) != "cert-new" {
+				t.Errorf("expect cert of %s rotated in status, got %s", binding.ListenerId, util.GetValue(binding.CertId))
+			}
+		}
+		// 证书已一致时不再更新
+		if err := r.ensureCertsRotated(ctx, clbbinding.WrapCLBPodBinding(saved)); err != nil {
+			t.Fatal(err)
+		}
+		if again := countModifyListener(); again["lb-cert-1"] != after["lb-cert-1"] || again["lb-cert-2"] != after["lb-cert-2"] {
+			t.Errorf("expect no more ModifyListener calls, got %v", again)
+		}
+	})
+
+	t.Run("clblistener mode", func(t *testing.T) {
+		setUseCLBListener(t, true)
+		pb := newCertPodBinding("cert-old")
+		c := newFakeClient(t, pb, newCertSecret("cert", "cert-new"))
+		r := &CLBBindingReconciler[*clbbinding.CLBPodBinding]{Client: c, Scheme: c.Scheme(), Recorder: record.NewFakeRecorder(10)}
+		before := countModifyListener()
+		// 只更新 status 中的证书 ID，由 CLBListener 控制器更新监听器
+		if err := r.ensureCertsRotated(ctx, clbbinding.WrapCLBPodBinding(pb)); err != nil {
+			t.Fatal(err)
+		}
+		if after := countModifyListener(); after["lb-cert-1"] != before["lb-cert-1"] || after["lb-cert-2"] != before["lb-cert-2"] {
+			t.Errorf("expect no ModifyListener calls, before %v, after %v", before, after)
+		}
+		for _, binding := range pb.Status.PortBindings {
+			if util.GetValue(binding.CertId) != "cert-new" {
+				t.Errorf("expect cert of %s updated in status, got %s", binding.ListenerId, util.GetValue(binding.CertId))
+			}
+		}
+	})
+}
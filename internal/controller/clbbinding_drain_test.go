@@ -0,0 +1,298 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/clbbinding"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/pkg/clb"
+	"github.com/tkestack/tke-extend-network-controller/pkg/kube"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+)
+
+// 配置了 60 秒摘流时间的 Pod 及其已绑定的 CLBPodBinding
+func newDrainPodBinding(name, region string) (*corev1.Pod, *networkingv1alpha1.CLBPodBinding) {
+	pod := newTestPod(name, nil, "10.0.0.1")
+	pod.Annotations = map[string]string{constant.EnableCLBPortMappingsKey: "true"}
+	pb := &networkingv1alpha1.CLBPodBinding{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec: networkingv1alpha1.CLBBindingSpec{
+			Ports:        []networkingv1alpha1.PortEntry{{Port: 80, Protocol: "TCP", Pools: []string{"pool"}}},
+			DrainSeconds: util.GetPtr(int64(60)),
+		},
+		Status: networkingv1alpha1.CLBBindingStatus{
+			State: networkingv1alpha1.CLBBindingStateBound,
+			PortBindings: []networkingv1alpha1.PortBindingStatus{{
+				Port: 80, Protocol: "TCP", Pool: "pool", Region: region, LoadbalancerId: "lb-drain",
+				LoadbalancerPort: 30000, ListenerId: "lbl-drain", AddressIPVersion: util.GetPtr("IPV4"),
+			}},
+		},
+	}
+	return pod, pb
+}
+
+// 设置监听器上已绑定的后端 10.0.0.1:80 的权重
+func setTargetWeight(cloud *fakeCloudTransport, weight int64) {
+	cloud.SetResult("DescribeTargets", map[string]any{
+		"Listeners": []any{map[string]any{
+			"ListenerId": "lbl-drain",
+			"Targets":    []any{map[string]any{"Type": "ENI", "Port": 80, "PrivateIpAddresses": []string{"10.0.0.1"}, "Weight": weight}},
+		}},
+	})
+}
+
+// 检查第 i 次 ModifyTargetWeight 调用设置的权重
+func checkModifiedWeight(t *testing.T, cloud *fakeCloudTransport, i int, weight int64) {
+	t.Helper()
+	calls := cloud.Calls("ModifyTargetWeight")
+	if len(calls) != i+1 {
+		t.Fatalf("expect %d ModifyTargetWeight calls, got %d", i+1, len(calls))
+	}
+	if got, _ := calls[i]["Weight"].(float64); int64(got) != weight {
+		t.Errorf("expect weight modified to %d, got %v", weight, calls[i]["Weight"])
+	}
+}
+
+func getPodBinding(t *testing.T, c client.Client, name string) *networkingv1alpha1.CLBPodBinding {
+	t.Helper()
+	pb := &networkingv1alpha1.CLBPodBinding{}
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: name}, pb); err != nil {
+		t.Fatal(err)
+	}
+	return pb
+}
+
+func TestDrainAndRestoreWeight(t *testing.T) {
+	ctx := context.Background()
+	setUseCLBListener(t, false)
+	cloud := useFakeCloud(t)
+	setTargetWeight(cloud, 20)
+	pod, pb := newDrainPodBinding("pod-0", "ap-drain-test")
+	c := newFakeClient(t, pod, pb)
+	r := &CLBBindingReconciler[*clbbinding.CLBPodBinding]{Client: c, Scheme: c.Scheme(), Recorder: record.NewFakeRecorder(10)}
+
+	// 摘流时将权重设为 0，并记录摘流前的权重和开始摘流的时间
+	remain, err := r.ensureDrained(ctx, clbbinding.WrapCLBPodBinding(pb))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remain != 60*time.Second {
+		t.Errorf("expect wait 60s, got %s", remain)
+	}
+	checkModifiedWeight(t, cloud, 0, 0)
+	saved := getPodBinding(t, c, "pod-0")
+	if w := saved.Status.PortBindings[0].OriginalWeight; w == nil || *w != 20 {
+		t.Errorf("expect original weight 20 saved, got %v", w)
+	}
+	if saved.Annotations[constant.DrainStartTimeKey] == "" {
+		t.Error("expect drain start time recorded")
+	}
+
+	// 摘流期间再次对账只等待，不再修改权重
+	remain, err = r.ensureDrained(ctx, clbbinding.WrapCLBPodBinding(saved))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remain <= 0 || remain > 60*time.Second {
+		t.Errorf("expect wait in drain period, got %s", remain)
+	}
+	checkModifiedWeight(t, cloud, 0, 0)
+
+	// 后端重新绑定时恢复摘流前的权重
+	setTargetWeight(cloud, 0)
+	bd := clbbinding.WrapCLBPodBinding(saved)
+	backend, err := bd.GetAssociatedObject(ctx, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	binding := saved.Status.PortBindings[0]
+	if err := r.ensurePortBound(ctx, bd, backend, &binding); err != nil {
+		t.Fatal(err)
+	}
+	checkModifiedWeight(t, cloud, 1, 20)
+	if binding.OriginalWeight != nil {
+		t.Errorf("expect original weight cleared after restored, got %d", *binding.OriginalWeight)
+	}
+}
+
+func TestRecordOriginalWeight(t *testing.T) {
+	weight := func(w int64) *int64 { return &w }
+	tests := []struct {
+		name     string
+		recorded *int64
+		targets  []*clb.Target
+		expect   int64
+	}{
+		{name: "record first weight", targets: []*clb.Target{{TargetIP: "10.0.0.1", Weight: weight(20)}, {TargetIP: "10.0.0.2", Weight: weight(30)}}, expect: 20},
+		{name: "keep recorded weight", recorded: weight(50), targets: []*clb.Target{{TargetIP: "10.0.0.1", Weight: weight(20)}}, expect: 50},
+		{name: "default weight without record", targets: []*clb.Target{{TargetIP: "10.0.0.1"}}, expect: clb.DefaultTargetWeight},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			binding := &networkingv1alpha1.PortBindingStatus{OriginalWeight: tt.recorded}
+			recordOriginalWeight(binding, tt.targets)
+			if got := originalWeight(binding); got != tt.expect {
+				t.Errorf("expect original weight %d, got %d", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestEnsureBackendDrained(t *testing.T) {
+	ctx := context.Background()
+	setUseCLBListener(t, false)
+	cloud := useFakeCloud(t)
+	setTargetWeight(cloud, 20)
+	pod, pb := newDrainPodBinding("pod-0", "ap-drain-finalizer-test")
+	c := newFakeClient(t, pod, pb)
+	kube.SetClient(c, c)
+	t.Cleanup(func() { kube.SetClient(nil, nil) })
+	r := &CLBBindingReconciler[*clbbinding.CLBPodBinding]{Client: c, Scheme: c.Scheme(), Recorder: record.NewFakeRecorder(10)}
+	ensure := func() (bool, time.Duration) {
+		t.Helper()
+		draining, remain, err := r.ensureBackendDrained(ctx, clbbinding.WrapCLBPodBinding(getPodBinding(t, c, "pod-0")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return draining, remain
+	}
+
+	// 配置了摘流时间时给 Pod 加上 finalizer
+	if draining, _ := ensure(); draining {
+		t.Error("expect not draining before pod deleted")
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pod), pod); err != nil {
+		t.Fatal(err)
+	}
+	if !controllerutil.ContainsFinalizer(pod, constant.DrainFinalizer) {
+		t.Fatal("expect drain finalizer added")
+	}
+
+	// Pod 删除时先摘流，摘流期间保留 finalizer
+	if err := c.Delete(ctx, pod); err != nil {
+		t.Fatal(err)
+	}
+	if draining, remain := ensure(); !draining || remain != 60*time.Second {
+		t.Errorf("expect draining 60s, got draining=%v remain=%s", draining, remain)
+	}
+	checkModifiedWeight(t, cloud, 0, 0)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pod), pod); err != nil {
+		t.Fatalf("expect pod kept while draining, got %v", err)
+	}
+
+	// 摘流时间结束后移除 finalizer，放行 Pod 删除
+	saved := getPodBinding(t, c, "pod-0")
+	saved.Annotations[constant.DrainStartTimeKey] = time.Now().Add(-61 * time.Second).Format(time.RFC3339)
+	if err := c.Update(ctx, saved); err != nil {
+		t.Fatal(err)
+	}
+	if draining, remain := ensure(); !draining || remain != 0 {
+		t.Errorf("expect drain finished, got draining=%v remain=%s", draining, remain)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pod), pod); !apierrors.IsNotFound(err) {
+		t.Errorf("expect pod deleted after drained, got %v", err)
+	}
+}
+
+func TestEnsureBackendDrainedTimeout(t *testing.T) {
+	ctx := context.Background()
+	setUseCLBListener(t, false)
+	cloud := useFakeCloud(t)
+	cloud.SetError("DescribeTargets", "InvalidParameter")
+	newDeletingPod := func(name string, deletedAt time.Time) (*corev1.Pod, *networkingv1alpha1.CLBPodBinding) {
+		pod, pb := newDrainPodBinding(name, "ap-drain-timeout-test")
+		pod.Finalizers = []string{constant.DrainFinalizer}
+		pod.DeletionTimestamp = &metav1.Time{Time: deletedAt}
+		return pod, pb
+	}
+	pod0, pb0 := newDeletingPod("pod-0", time.Now())
+	pod1, pb1 := newDeletingPod("pod-1", time.Now().Add(-10*time.Minute))
+	c := newFakeClient(t, pod0, pb0, pod1, pb1)
+	kube.SetClient(c, c)
+	t.Cleanup(func() { kube.SetClient(nil, nil) })
+	recorder := record.NewFakeRecorder(10)
+	r := &CLBBindingReconciler[*clbbinding.CLBPodBinding]{Client: c, Scheme: c.Scheme(), Recorder: recorder}
+
+	// 摘流失败时重试，保留 finalizer
+	if _, _, err := r.ensureBackendDrained(ctx, clbbinding.WrapCLBPodBinding(getPodBinding(t, c, "pod-0"))); err == nil {
+		t.Error("expect error when drain failed")
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pod0), pod0); err != nil {
+		t.Errorf("expect pod kept when drain failed, got %v", err)
+	}
+
+	// 超过摘流时间太久后放弃摘流，移除 finalizer
+	draining, _, err := r.ensureBackendDrained(ctx, clbbinding.WrapCLBPodBinding(getPodBinding(t, c, "pod-1")))
+	if err != nil || !draining {
+		t.Fatalf("expect drain given up, got draining=%v err=%v", draining, err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pod1), pod1); !apierrors.IsNotFound(err) {
+		t.Errorf("expect pod deleted after drain timeout, got %v", err)
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("expect DrainTimeout event, got %d events", len(recorder.Events))
+	}
+}
+
+func TestReleaseDrainFinalizer(t *testing.T) {
+	ctx := context.Background()
+	newDeletingPod := func(name string) *corev1.Pod {
+		pod := newTestPod(name, nil, "10.0.0.1")
+		pod.Finalizers = []string{constant.DrainFinalizer}
+		pod.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		return pod
+	}
+	orphan := newDeletingPod("pod-0")
+	bound := newDeletingPod("pod-1")
+	_, pb := newDrainPodBinding("pod-1", "ap-drain-release-test")
+	c := newFakeClient(t, orphan, bound, pb)
+	kube.SetClient(c, c)
+	t.Cleanup(func() { kube.SetClient(nil, nil) })
+	r := &PodReconciler{Client: c}
+
+	// 没有 CLBPodBinding 的 Pod 直接移除 finalizer
+	if err := r.ensureDrainFinalizerReleased(ctx, orphan); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(orphan), orphan); !apierrors.IsNotFound(err) {
+		t.Errorf("expect orphan pod deleted, got %v", err)
+	}
+	// 有 CLBPodBinding 的 Pod 由 CLBPodBinding 摘流后移除
+	if err := r.ensureDrainFinalizerReleased(ctx, bound); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(bound), bound); err != nil || !controllerutil.ContainsFinalizer(bound, constant.DrainFinalizer) {
+		t.Errorf("expect finalizer kept for pod with clbpodbinding, got %v", err)
+	}
+}
+
+func TestRemoveDrainFinalizers(t *testing.T) {
+	ctx := context.Background()
+	draining := newTestPod("pod-0", nil, "10.0.0.1")
+	draining.Finalizers = []string{constant.DrainFinalizer, "other"}
+	normal := newTestPod("pod-1", nil, "10.0.0.2")
+	c := newFakeClient(t, draining, normal)
+	removed, err := RemoveDrainFinalizers(ctx, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 1 || removed[0] != "default/pod-0" {
+		t.Errorf("expect finalizer of default/pod-0 removed, got %v", removed)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(draining), draining); err != nil {
+		t.Fatal(err)
+	}
+	if len(draining.Finalizers) != 1 || draining.Finalizers[0] != "other" {
+		t.Errorf("expect other finalizers kept, got %v", draining.Finalizers)
+	}
+}
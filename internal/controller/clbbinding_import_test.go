@@ -0,0 +1,170 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/clbbinding"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/pkg/clb"
+)
+
+// 从其它集群导入的 CLBPodBinding，已分配了端口
+func newImportedPodBinding(name string, annotations map[string]string) *networkingv1alpha1.CLBPodBinding {
+	anno := map[string]string{constant.ImportedKey: "true"}
+	for k, v := range annotations {
+		anno[k] = v
+	}
+	return &networkingv1alpha1.CLBPodBinding{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, UID: "new-uid", Annotations: anno},
+		Spec:       networkingv1alpha1.CLBBindingSpec{Ports: []networkingv1alpha1.PortEntry{{Port: 80, Protocol: "TCP", Pools: []string{"pool"}}}},
+		Status: networkingv1alpha1.CLBBindingStatus{
+			State: networkingv1alpha1.CLBBindingStateAllocated,
+			PortBindings: []networkingv1alpha1.PortBindingStatus{
+				{Port: 80, Protocol: "TCP", Pool: "pool", Region: dryRunTestRegion, LoadbalancerId: "lb-import", LoadbalancerPort: 30080},
+			},
+		},
+	}
+}
+
+func TestEnsureImportedAdopted(t *testing.T) {
+	ctx := context.Background()
+	for _, retain := range []bool{false, true} {
+		pod := newTestPod("pod-0", nil, "10.0.0.1")
+		pod.UID = "pod-uid"
+		if retain {
+			pod.Annotations = map[string]string{constant.Ratain: "true"}
+		}
+		pb := newImportedPodBinding("pod-0", nil)
+		c := newFakeClient(t, pod, pb)
+		r := &CLBBindingReconciler[*clbbinding.CLBPodBinding]{Client: c, Scheme: c.Scheme(), Recorder: record.NewFakeRecorder(10)}
+		if err := r.ensureImportedAdopted(ctx, pod, clbbinding.WrapCLBPodBinding(pb)); err != nil {
+			t.Fatal(err)
+		}
+		saved := &networkingv1alpha1.CLBPodBinding{}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(pb), saved); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := saved.Annotations[constant.ImportedKey]; ok {
+			t.Errorf("expect imported annotation removed, retain=%v", retain)
+		}
+		// 设置了 retain 注解的 Pod 不关联 CLBPodBinding，Pod 删除后 CLBPodBinding 保留
+		if retain && len(saved.OwnerReferences) != 0 {
+			t.Errorf("expect no owner reference for retained pod, got %+v", saved.OwnerReferences)
+		}
+		if !retain && (len(saved.OwnerReferences) != 1 || saved.OwnerReferences[0].UID != pod.UID) {
+			t.Errorf("expect owned by pod, got %+v", saved.OwnerReferences)
+		}
+	}
+}
+
+func TestEnsureClaimed(t *testing.T) {
+	ctx := context.Background()
+	newPod := func(claim string) *corev1.Pod {
+		pod := newTestPod("pod-1", nil, "10.0.0.1")
+		pod.UID = "pod-uid"
+		pod.Annotations = map[string]string{constant.ClaimCLBBindingKey: claim}
+		return pod
+	}
+
+	t.Run("claim", func(t *testing.T) {
+		pod := newPod("pod-0")
+		// 被认领的 CLBPodBinding 带 finalizer，删除后仍可检查认领标记
+		claimed := newImportedPodBinding("pod-0", nil)
+		claimed.Finalizers = []string{constant.Finalizer}
+		pb := &networkingv1alpha1.CLBPodBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-1"}, Spec: claimed.Spec}
+		c := newFakeClient(t, pod, claimed, pb)
+		r := &CLBBindingReconciler[*clbbinding.CLBPodBinding]{Client: c, Scheme: c.Scheme(), Recorder: record.NewFakeRecorder(10)}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(pb), pb); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.ensureClaimed(ctx, pod, clbbinding.WrapCLBPodBinding(pb)); err != nil {
+			t.Fatal(err)
+		}
+		saved := &networkingv1alpha1.CLBPodBinding{}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(pb), saved); err != nil {
+			t.Fatal(err)
+		}
+		if saved.Status.State != networkingv1alpha1.CLBBindingStateAllocated || len(saved.Status.PortBindings) != 1 || saved.Status.PortBindings[0].LoadbalancerPort != 30080 {
+			t.Errorf("expect port bindings inherited, got %+v", saved.Status)
+		}
+		old := &networkingv1alpha1.CLBPodBinding{}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(claimed), old); err != nil {
+			t.Fatal(err)
+		}
+		if old.DeletionTimestamp.IsZero() {
+			t.Error("expect claimed clbpodbinding deleted")
+		}
+		// 删除被认领的 CLBPodBinding 时不清理监听器和释放端口
+		if old.Annotations[constant.ClaimedByKey] != string(pod.UID) || old.Annotations[constant.FinalizedKey] != "true" {
+			t.Errorf("expect claimed clbpodbinding marked, got %v", old.Annotations)
+		}
+	})
+
+	t.Run("claimed by other", func(t *testing.T) {
+		pod := newPod("pod-0")
+		claimed := newImportedPodBinding("pod-0", map[string]string{constant.ClaimedByKey: "other-uid"})
+		pb := &networkingv1alpha1.CLBPodBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-1"}, Spec: claimed.Spec}
+		c := newFakeClient(t, pod, claimed, pb)
+		recorder := record.NewFakeRecorder(10)
+		r := &CLBBindingReconciler[*clbbinding.CLBPodBinding]{Client: c, Scheme: c.Scheme(), Recorder: recorder}
+		if err := r.ensureClaimed(ctx, pod, clbbinding.WrapCLBPodBinding(pb)); err != nil {
+			t.Fatal(err)
+		}
+		if len(pb.Status.PortBindings) != 0 {
+			t.Errorf("expect port bindings not inherited, got %+v", pb.Status.PortBindings)
+		}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(claimed), &networkingv1alpha1.CLBPodBinding{}); err != nil {
+			t.Errorf("expect claimed clbpodbinding kept, got %v", err)
+		}
+		if len(recorder.Events) != 1 {
+			t.Errorf("expect 1 warning event, got %d", len(recorder.Events))
+		}
+	})
+}
+
+func TestCheckListenerOwner(t *testing.T) {
+	enableTestDryRun(t)
+	ctx := context.Background()
+	pb := newImportedPodBinding("pod-0", map[string]string{constant.PreviousUIDsKey: "old-uid-1,old-uid-2"})
+	binding := &pb.Status.PortBindings[0]
+	r := &CLBBindingReconciler[*clbbinding.CLBPodBinding]{Recorder: record.NewFakeRecorder(10)}
+	bd := clbbinding.WrapCLBPodBinding(pb)
+
+	// 重建前的 CLBBinding 创建的监听器改名为当前 UID 后复用
+	lis := &clb.Listener{ListenerId: "lbl-renamed", ListenerName: clb.BuildListenerName("old-uid-2", "pool", 80)}
+	if err := r.checkListenerOwner(ctx, bd, binding, lis); err != nil {
+		t.Fatal(err)
+	}
+	newName := clb.BuildListenerName("new-uid", "pool", 80)
+	renamed := false
+	for _, action := range clb.GetDryRunReport().Actions {
+		if action.Api == "ModifyListener" && action.LbId == "lb-import" && strings.Contains(action.Request, newName) {
+			renamed = true
+		}
+	}
+	if !renamed {
+		t.Errorf("expect listener renamed to %s", newName)
+	}
+
+	// 属于自己或无法识别归属的监听器直接复用
+	for _, name := range []string{newName, clb.TkeListenerName} {
+		if err := r.checkListenerOwner(ctx, bd, binding, &clb.Listener{ListenerId: "lbl-own", ListenerName: name}); err != nil {
+			t.Errorf("expect listener %s reused, got %v", name, err)
+		}
+	}
+
+	// 属于其它 CLBBinding 的监听器视为端口冲突
+	lis = &clb.Listener{ListenerId: "lbl-other", ListenerName: clb.BuildListenerName("other-uid", "pool", 80)}
+	if err := r.checkListenerOwner(ctx, bd, binding, lis); !errors.Is(err, ErrListenerOwnedByOther) {
+		t.Errorf("expect ErrListenerOwnedByOther, got %v", err)
+	}
+}
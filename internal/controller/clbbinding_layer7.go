@@ -0,0 +1,260 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/clbbinding"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/internal/portpool"
+	"github.com/tkestack/tke-extend-network-controller/pkg/clb"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// 七层（HTTP/HTTPS）端口绑定：不独占监听器，而是在端口池的共享七层监听器上为每个 Pod/Node 创建一条转发规则（域名 + URL），
+// 后端绑定到转发规则上。
+
+// 渲染七层转发规则的域名或 URL 模板
+func renderLayer7Template(tpl string, bd clbbinding.CLBBinding, port uint16) string {
+	return strings.NewReplacer(
+		"{{pod}}", bd.GetName(),
+		"{{node}}", bd.GetName(),
+		"{{namespace}}", bd.GetNamespace(),
+		"{{port}}", strconv.Itoa(int(port)),
+	).Replace(tpl)
+}
+
+// 根据端口池的七层配置，为分配到的共享监听器构造端口绑定
+func (r *CLBBindingReconciler[T]) newLayer7PortBinding(ctx context.Context, bd clbbinding.CLBBinding, port networkingv1alpha1.PortEntry, allocated portpool.PortAllocation) (*networkingv1alpha1.PortBindingStatus, error) {
+	pp := &networkingv1alpha1.CLBPortPool{}
+	if err := r.Get(ctx, client.ObjectKey{Name: allocated.Name}, pp); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if pp.Spec.Layer7 == nil {
+		return nil, errors.Wrapf(portpool.ErrLayer7NotConfigured, "port pool %s", allocated.Name)
+	}
+	url := "/"
+	if pp.Spec.Layer7.Url != nil && *pp.Spec.Layer7.Url != "" {
+		url = *pp.Spec.Layer7.Url
+	}
+	domain := renderLayer7Template(pp.Spec.Layer7.Domain, bd, port.Port)
+	url = renderLayer7Template(url, bd, port.Port)
+	return &networkingv1alpha1.PortBindingStatus{
+		Port:             port.Port,
+		Protocol:         allocated.Protocol,
+		Pool:             allocated.Name,
+		LoadbalancerId:   allocated.LbId,
+		LoadbalancerPort: allocated.Port,
+		Region:           allocated.Region,
+		AddressIPVersion: r.getLBAddressIPVersion(ctx, allocated.Name, allocated.LbId),
+		Domain:           &domain,
+		Url:              &url,
+	}, nil
+}
+
+// 确保七层端口绑定的共享监听器和转发规则存在，并根据需要绑定或摘流后端，返回 nil 表示该端口绑定需要被移除
+func (r *CLBBindingReconciler[T]) ensureLayer7Binding(ctx context.Context, bd clbbinding.CLBBinding, backend clbbinding.Backend, binding *networkingv1alpha1.PortBindingStatus, needBind, waitReady bool) (*networkingv1alpha1.PortBindingStatus, error) {
+	log := log.FromContext(ctx, "binding", binding)
+	log.V(5).Info("ensureLayer7Binding")
+	// 端口池被删或 lb 已被移除，且当前还未绑定成功，则移除该端口绑定，等待重新分配
+	if bd.GetStatus().State != networkingv1alpha1.CLBBindingStateBound {
+		if pool := portpool.Allocator.GetPool(binding.Pool); pool == nil || !pool.IsLbExists(portpool.NewLBKeyFromBinding(binding)) {
+			log.Info("remove allocated layer7 binding due to port pool or lb not exists")
+			r.Recorder.Eventf(bd.GetObject(), corev1.EventTypeNormal, "PortBindingRemoved", "port pool %q or lb %q not exists, remove layer7 binding (domain:%s url:%s)", binding.Pool, binding.LoadbalancerId, util.GetValue(binding.Domain), util.GetValue(binding.Url))
+			if err := cleanupLayer7PortBinding(ctx, binding, log); err != nil {
+				return binding, errors.WithStack(err)
+			}
+			return nil, nil
+		}
+	}
+	pp := &networkingv1alpha1.CLBPortPool{}
+	if err := r.Get(ctx, client.ObjectKey{Name: binding.Pool}, pp); err != nil {
+		return binding, errors.WithStack(err)
+	}
+	if pp.Spec.Layer7 == nil {
+		return binding, errors.Wrapf(portpool.ErrLayer7NotConfigured, "port pool %s", binding.Pool)
+	}
+	// 确保共享监听器存在
+	lisId, err := clb.EnsureLayer7Listener(ctx, binding.Region, binding.LoadbalancerId, binding.LoadbalancerPort, binding.Protocol, util.GetValue(pp.Spec.Layer7.CertId), clb.BuildLayer7ListenerName(binding.Pool, binding.LoadbalancerPort))
+	if err != nil {
+		return binding, errors.WithStack(err)
+	}
+	if binding.ListenerId != lisId { // 共享监听器被重建，转发规则也需要重建
+		binding.ListenerId = lisId
+		binding.LocationId = nil
+	}
+	// 确保转发规则存在
+	if binding.LocationId == nil {
+		domain, url := util.GetValue(binding.Domain), util.GetValue(binding.Url)
+		rule, err := clb.GetRule(ctx, binding.Region, binding.LoadbalancerId, lisId, domain, url)
+		if err != nil {
+			return binding, errors.WithStack(err)
+		}
+		locationId := ""
+		if rule != nil {
+			locationId = rule.LocationId
+		} else {
+			locationId, err = clb.CreateRule(ctx, binding.Region, binding.LoadbalancerId, lisId, domain, url)
+			if err != nil {
+				if clb.IsListenerNotFound(err) { // 共享监听器被手动删除，清理缓存后重试
+					clb.GetListenerCache(clb.LBKey{Region: binding.Region, LbId: binding.LoadbalancerId}).EnsureRemoved(ctx, binding.LoadbalancerPort, binding.Protocol)
+				}
+				return binding, errors.WithStack(err)
+			}
+			r.Recorder.Eventf(bd.GetObject(), corev1.EventTypeNormal, "RuleCreated", "rule created (domain:%s url:%s locationId:%s)", domain, url, locationId)
+		}
+		binding.LocationId = &locationId
+	}
+	if needBind {
+		err = r.ensureRuleBound(ctx, bd, backend, binding)
+	} else if waitReady {
+		err = drainLayer7PortBinding(ctx, binding)
+	}
+	return binding, err
+}
+
+// 确保后端绑定到转发规则，并解绑其它后端（转发规则只属于当前 CLBBinding）
+func (r *CLBBindingReconciler[T]) ensureRuleBound(ctx context.Context, bd clbbinding.CLBBinding, backend clbbinding.Backend, binding *networkingv1alpha1.PortBindingStatus) error {
+	locationId := util.GetValue(binding.LocationId)
+	targets, err := clb.DescribeRuleTargets(ctx, binding.Region, binding.LoadbalancerId, binding.ListenerId, locationId)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	backendTarget := clb.Target{
+		TargetIP:   getBackendIP(ctx, backend, binding),
+		TargetPort: int64(binding.Port),
+	}
+	targetToDelete := []*clb.Target{}
+	alreadyAdded := false
+	for _, target := range targets {
+		if target.IsSameBackend(backendTarget) {
+			alreadyAdded = true
+			// 权重为 0 通常是上次解绑前摘流后后端被重新绑定，恢复摘流前的权重
+			if target.Weight != nil && *target.Weight == 0 {
+				weight := originalWeight(binding)
+				r.Recorder.Eventf(bd.GetObject(), corev1.EventTypeNormal, "RestoreTargetWeight", "restore weight of target %s to %d", target, weight)
+				if err := clb.ModifyRuleTargetWeight(ctx, binding.Region, binding.LoadbalancerId, binding.ListenerId, locationId, weight, target); err != nil {
+					return errors.WithStack(err)
+				}
+			}
+			binding.OriginalWeight = nil
+		} else {
+			targetToDelete = append(targetToDelete, target)
+		}
+	}
+	if len(targetToDelete) > 0 {
+		r.Recorder.Eventf(bd.GetObject(), corev1.EventTypeNormal, "DeregisterTarget", "remove unexpected target: %v", targetToDelete)
+		if err := clb.DeregisterRuleTargets(ctx, binding.Region, binding.LoadbalancerId, binding.ListenerId, locationId, targetToDelete...); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if !alreadyAdded {
+		if err := clb.RegisterRuleTargets(ctx, binding.Region, binding.LoadbalancerId, binding.ListenerId, locationId, backendTarget); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// 将转发规则上已绑定后端的权重设为 0，让 CLB 不再向其转发新请求
+func drainLayer7PortBinding(ctx context.Context, binding *networkingv1alpha1.PortBindingStatus) error {
+	if binding.LocationId == nil { // 还没有转发规则，无需摘流
+		return nil
+	}
+	targets, err := clb.DescribeRuleTargets(ctx, binding.Region, binding.LoadbalancerId, binding.ListenerId, *binding.LocationId)
+	if err != nil {
+		if clb.IsLoadBalancerNotExistsError(errors.Cause(err)) { // lb 不存在，忽略
+			return nil
+		}
+		return errors.WithStack(err)
+	}
+	targetsToDrain := []*clb.Target{}
+	for _, target := range targets {
+		if target.Weight == nil || *target.Weight != 0 {
+			targetsToDrain = append(targetsToDrain, target)
+		}
+	}
+	recordOriginalWeight(binding, targetsToDrain)
+	if err := clb.ModifyRuleTargetWeight(ctx, binding.Region, binding.LoadbalancerId, binding.ListenerId, *binding.LocationId, 0, targetsToDrain...); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// 解绑转发规则上的所有后端
+func deregisterLayer7PortBinding(ctx context.Context, binding *networkingv1alpha1.PortBindingStatus) error {
+	if binding.LocationId == nil {
+		return nil
+	}
+	targets, err := clb.DescribeRuleTargets(ctx, binding.Region, binding.LoadbalancerId, binding.ListenerId, *binding.LocationId)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := clb.DeregisterRuleTargets(ctx, binding.Region, binding.LoadbalancerId, binding.ListenerId, *binding.LocationId, targets...); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// 删除七层端口绑定的转发规则，共享监听器保留给其它转发规则使用
+func cleanupLayer7PortBinding(ctx context.Context, binding *networkingv1alpha1.PortBindingStatus, log logr.Logger) error {
+	log.V(2).Info("cleanupLayer7PortBinding")
+	if binding.ListenerId == "" { // 还没有创建转发规则
+		return nil
+	}
+	locationId := util.GetValue(binding.LocationId)
+	if locationId == "" { // 转发规则可能已创建但 ID 还未记录到 status，按域名和 URL 查询
+		rule, err := clb.GetRule(ctx, binding.Region, binding.LoadbalancerId, binding.ListenerId, util.GetValue(binding.Domain), util.GetValue(binding.Url))
+		if err != nil {
+			if clb.IsLoadBalancerNotExistsError(errors.Cause(err)) {
+				return nil
+			}
+			return errors.WithStack(err)
+		}
+		if rule == nil {
+			return nil
+		}
+		locationId = rule.LocationId
+	}
+	if err := deleteLayer7Rule(ctx, binding.Region, binding.LoadbalancerId, binding.ListenerId, locationId, util.GetValue(binding.Domain), util.GetValue(binding.Url)); err != nil {
+		return errors.WithStack(err)
+	}
+	log.Info("rule deleted", "locationId", locationId)
+	return nil
+}
+
+// 删除转发规则，lb、共享监听器或转发规则已不存在时忽略
+func deleteLayer7Rule(ctx context.Context, region, lbId, listenerId, locationId, domain, url string) error {
+	err := clb.DeleteRule(ctx, region, lbId, listenerId, locationId)
+	if err == nil {
+		return nil
+	}
+	if clb.IsLoadBalancerNotExistsError(errors.Cause(err)) || clb.IsListenerNotFound(err) {
+		return nil
+	}
+	// 删除失败，检查转发规则是否已不存在
+	rule, e := clb.GetRule(ctx, region, lbId, listenerId, domain, url)
+	if e == nil && (rule == nil || rule.LocationId != locationId) {
+		return nil
+	}
+	return errors.WithStack(err)
+}
+
+// 七层端口绑定对外的访问地址，如 https://pod-0.default.games.example.com/
+func layer7Address(binding *networkingv1alpha1.PortBindingStatus) string {
+	scheme := strings.ToLower(binding.Protocol)
+	host := util.GetValue(binding.Domain)
+	if (binding.Protocol == constant.ProtocolHTTP && binding.LoadbalancerPort != networkingv1alpha1.DefaultLayer7HTTPPort) ||
+		(binding.Protocol == constant.ProtocolHTTPS && binding.LoadbalancerPort != networkingv1alpha1.DefaultLayer7HTTPSPort) {
+		host = fmt.Sprintf("%s:%d", host, binding.LoadbalancerPort)
+	}
+	return fmt.Sprintf("%s://%s%s", scheme, host, util.GetValue(binding.Url))
+}
@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/clbbinding"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+)
+
+func TestGetWaitBackendReady(t *testing.T) {
+	newPool := func(name string, waitBackendReady *bool) *networkingv1alpha1.CLBPortPool {
+		return &networkingv1alpha1.CLBPortPool{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       networkingv1alpha1.CLBPortPoolSpec{StartPort: 30000, WaitBackendReady: waitBackendReady},
+		}
+	}
+	tests := []struct {
+		name         string
+		spec         networkingv1alpha1.CLBBindingSpec
+		pools        []string
+		wantWait     bool
+		wantPortName string
+	}{
+		{name: "no config", pools: []string{"pool-default"}},
+		{name: "enabled by one of the pools", pools: []string{"pool-default", "pool-wait"}, wantWait: true},
+		{name: "pool not found", pools: []string{"pool-not-found"}},
+		{
+			name:     "disabled by annotation",
+			spec:     networkingv1alpha1.CLBBindingSpec{WaitBackendReady: util.GetPtr(false)},
+			pools:    []string{"pool-wait"},
+			wantWait: false,
+		},
+		{
+			name:         "enabled by annotation with port name",
+			spec:         networkingv1alpha1.CLBBindingSpec{WaitBackendReady: util.GetPtr(true), ReadinessPortName: util.GetPtr("game")},
+			pools:        []string{"pool-default"},
+			wantWait:     true,
+			wantPortName: "game",
+		},
+	}
+	c := newFakeClient(t, newPool("pool-default", nil), newPool("pool-wait", util.GetPtr(true)))
+	r := &CLBBindingReconciler[*clbbinding.CLBPodBinding]{Client: c, Scheme: c.Scheme()}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pb := &networkingv1alpha1.CLBPodBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-0"}, Spec: tt.spec}
+			for i, pool := range tt.pools {
+				pb.Status.PortBindings = append(pb.Status.PortBindings, networkingv1alpha1.PortBindingStatus{Port: uint16(80 + i), Protocol: "TCP", Pool: pool})
+			}
+			wait, portName, err := r.getWaitBackendReady(context.Background(), clbbinding.WrapCLBPodBinding(pb))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if wait != tt.wantWait || portName != tt.wantPortName {
+				t.Errorf("expect wait=%v portName=%q, got wait=%v portName=%q", tt.wantWait, tt.wantPortName, wait, portName)
+			}
+		})
+	}
+}
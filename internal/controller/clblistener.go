@@ -0,0 +1,353 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/clbbinding"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/internal/portpool"
+	"github.com/tkestack/tke-extend-network-controller/pkg/clb"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+)
+
+// UseCLBListener 为 true（默认）时，CLBBinding 不再直接调用云 API 管理监听器，而是为每个端口绑定创建 CLBListener，
+// 由 CLBListener 控制器负责监听器的创建、配置对账和后端绑定。
+// 启用前已绑定的端口在 CLBBinding 下次对账时创建 CLBListener，CLBListener 控制器按端口接管已有的监听器（监听器名称中
+// 记录的 CLBBinding UID 一致）；迁移前就被删除的 CLBBinding 没有 CLBListener，清理时直接删除监听器。
+// 为 false 时使用 CLBBinding 直接调用云 API 的旧模式，仅用于升级过渡，后续版本移除。
+var UseCLBListener = true
+
+var ErrCLBListenerDeleting = errors.New("clblistener is deleting")
+
+// CLBListener 的名称由 lb、协议和端口确定，同一个监听器只会对应一个 CLBListener，
+// 端口被重新分配时，可以据此发现上一个使用者的 CLBListener 还未清理完。
+func clbListenerName(binding *networkingv1alpha1.PortBindingStatus) string {
+	protocol := strings.ReplaceAll(strings.ToLower(binding.Protocol), "_", "-")
+	name := fmt.Sprintf("%s-%s-%d", binding.LoadbalancerId, protocol, binding.LoadbalancerPort)
+	if binding.LoadbalancerEndPort != nil {
+		name = fmt.Sprintf("%s-%d", name, *binding.LoadbalancerEndPort)
+	}
+	return name
+}
+
+// 对账所有端口绑定对应的 CLBListener，并将监听器 ID 记录到 status 中，所有 CLBListener 都同步完成后才认为绑定成功
+func (r *CLBBindingReconciler[T]) ensureCLBListeners(ctx context.Context, bd clbbinding.CLBBinding, backend clbbinding.Backend, needBind, waitReady bool) (err error) {
+	status := bd.GetStatus()
+	bindings := []networkingv1alpha1.PortBindingStatus{}
+	allSynced := true
+	for i := range status.PortBindings {
+		binding := status.PortBindings[i].DeepCopy()
+		if util.IsLayer7Protocol(binding.Protocol) { // 七层端口绑定使用共享监听器上的转发规则，不使用 CLBListener
+			b, e := r.ensureLayer7Binding(ctx, bd, backend, binding, needBind, waitReady)
+			if e != nil {
+				err = multierr.Append(err, e)
+				allSynced = false
+			}
+			if b != nil {
+				bindings = append(bindings, *b)
+			}
+			continue
+		}
+		if r.shouldRemovePortBinding(ctx, bd, binding) {
+			if e := r.deleteCLBListener(ctx, bd, binding); e != nil {
+				err = multierr.Append(err, e)
+				bindings = append(bindings, *binding)
+				continue
+			}
+			if portpool.Allocator.ReleaseBinding(binding) {
+				notifyPortPoolReconcile(binding.Pool)
+			}
+			continue
+		}
+		lis, e := r.ensureCLBListener(ctx, bd, backend, binding, needBind, waitReady)
+		if e != nil {
+			err = multierr.Append(err, e)
+			allSynced = false
+		} else {
+			if lis.Status.ListenerID != "" {
+				binding.ListenerId = lis.Status.ListenerID
+			}
+			if lis.Status.State == networkingv1alpha1.CLBListenerStateFailed {
+				err = multierr.Append(err, errors.Errorf("clblistener %s failed: %s", lis.Name, lis.Status.Message))
+			}
+			if lis.Status.State != networkingv1alpha1.CLBListenerStateSynced || lis.Status.ObservedGeneration != lis.Generation {
+				allSynced = false
+			}
+		}
+		bindings = append(bindings, *binding)
+	}
+	return r.ensureBindingsResult(ctx, bd, backend, bindings, needBind && allSynced, err)
+}
+
+// 如果端口池被删或 lb 已被移除，且当前还未绑定成功，则移除该端口绑定，等待重新分配端口
+func (r *CLBBindingReconciler[T]) shouldRemovePortBinding(ctx context.Context, bd clbbinding.CLBBinding, binding *networkingv1alpha1.PortBindingStatus) bool {
+	if bd.GetStatus().State == networkingv1alpha1.CLBBindingStateBound {
+		return false
+	}
+	removeReason := ""
+	removeMsg := ""
+	if pool := portpool.Allocator.GetPool(binding.Pool); pool == nil {
+		removeReason = "PortPoolDeleted"
+		removeMsg = "port pool has been deleted"
+	} else if !pool.IsLbExists(portpool.NewLBKeyFromBinding(binding)) {
+		removeReason = "CLBDeleted"
+		removeMsg = "clb has been removed"
+	} else {
+		return false
+	}
+	log.FromContext(ctx).Info("remove allocated clbbinding due to " + removeMsg)
+	r.Recorder.Eventf(bd.GetObject(), corev1.EventTypeWarning, removeReason, "%s (%s/%s/%d/%s)", removeMsg, binding.Pool, binding.LoadbalancerId, binding.LoadbalancerPort, binding.Protocol)
+	return true
+}
+
+// 构造端口绑定预期的 CLBListener spec，后端列表由调用方决定
+func (r *CLBBindingReconciler[T]) getExpectedCLBListenerSpec(ctx context.Context, bd clbbinding.CLBBinding, binding *networkingv1alpha1.PortBindingStatus) (*networkingv1alpha1.CLBListenerSpec, error) {
+	tpl, err := r.getExpectedListenerTemplate(ctx, bd, binding)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	precreated := false
+	if pool := portpool.Allocator.GetPool(binding.Pool); pool != nil && pool.IsPrecreateListenerEnabled() {
+		precreated = true
+	}
+	return &networkingv1alpha1.CLBListenerSpec{
+		LoadbalancerID:   binding.LoadbalancerId,
+		Region:           binding.Region,
+		Port:             binding.LoadbalancerPort,
+		EndPort:          binding.LoadbalancerEndPort,
+		Protocol:         binding.Protocol,
+		CertId:           binding.CertId,
+		ExtraCertIds:     binding.ExtraCertIds,
+		CACertId:         binding.CACertId,
+		ListenerName:     util.GetPtr(clb.BuildListenerName(string(bd.GetUID()), binding.Pool, binding.Port)),
+		ListenerTemplate: tpl,
+		Precreated:       precreated,
+	}, nil
+}
+
+// 确保端口绑定对应的 CLBListener 存在且符合预期：
+// 需要绑定时后端为当前 backend；等待后端就绪时已绑定的后端权重设为 0；其余情况保持已有的后端不变。
+func (r *CLBBindingReconciler[T]) ensureCLBListener(ctx context.Context, bd clbbinding.CLBBinding, backend clbbinding.Backend, binding *networkingv1alpha1.PortBindingStatus, needBind, waitReady bool) (*networkingv1alpha1.CLBListener, error) {
+	spec, err := r.getExpectedCLBListenerSpec(ctx, bd, binding)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	lis := &networkingv1alpha1.CLBListener{}
+	if err := r.Get(ctx, client.ObjectKey{Name: clbListenerName(binding)}, lis); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, errors.WithStack(err)
+		}
+		return r.createCLBListener(ctx, bd, backend, binding, spec, needBind)
+	}
+	if lis.DeletionTimestamp != nil {
+		return nil, errors.Wrapf(ErrCLBListenerDeleting, "clblistener %s is deleting", lis.Name)
+	}
+	if uid := lis.Labels[constant.CLBBindingUIDLabelKey]; uid != string(bd.GetUID()) && clbbinding.IsPreviousUID(bd.GetObject(), uid) {
+		// 重建前的 CLBBinding 创建的 CLBListener（灾难恢复），改为属于当前 CLBBinding
+		lis.Labels[constant.CLBBindingUIDLabelKey] = string(bd.GetUID())
+		if lis.Annotations == nil {
+			lis.Annotations = make(map[string]string)
+		}
+		lis.Annotations[constant.CLBBindingKey] = client.ObjectKeyFromObject(bd.GetObject()).String()
+		lis.Annotations[constant.PreviousUIDsKey] = bd.GetAnnotations()[constant.PreviousUIDsKey]
+		if err := r.Update(ctx, lis); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	if lis.Labels[constant.CLBBindingUIDLabelKey] != string(bd.GetUID()) {
+		r.Recorder.Eventf(
+			bd.GetObject(), corev1.EventTypeWarning, "ListenerConflict",
+			"clblistener %s (%s/%d/%s) is owned by other clbbinding (%s)",
+			lis.Name, binding.LoadbalancerId, binding.LoadbalancerPort, binding.Protocol, lis.Annotations[constant.CLBBindingKey],
+		)
+		return nil, errors.Wrapf(ErrListenerOwnedByOther, "clblistener %s is owned by clbbinding %s", lis.Name, lis.Annotations[constant.CLBBindingKey])
+	}
+	switch {
+	case needBind:
+		spec.Backends = []networkingv1alpha1.CLBListenerBackend{{IP: getBackendIP(ctx, backend, binding), Port: binding.Port}}
+	case waitReady:
+		spec.Backends = drainCLBListenerBackends(lis.Spec.Backends)
+	default:
+		spec.Backends = lis.Spec.Backends
+	}
+	if !reflect.DeepEqual(*spec, lis.Spec) {
+		lis.Spec = *spec
+		if err := r.Update(ctx, lis); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	return lis, nil
+}
+
+func (r *CLBBindingReconciler[T]) createCLBListener(ctx context.Context, bd clbbinding.CLBBinding, backend clbbinding.Backend, binding *networkingv1alpha1.PortBindingStatus, spec *networkingv1alpha1.CLBListenerSpec, needBind bool) (*networkingv1alpha1.CLBListener, error) {
+	lis := &networkingv1alpha1.CLBListener{}
+	lis.Name = clbListenerName(binding)
+	lis.Labels = map[string]string{
+		constant.CLBPortPoolLabelKey:    binding.Pool,
+		constant.CLBBindingUIDLabelKey:  string(bd.GetUID()),
+		constant.CLBBindingTypeLabelKey: bd.GetType(),
+	}
+	lis.Annotations = map[string]string{
+		constant.CLBBindingKey: client.ObjectKeyFromObject(bd.GetObject()).String(),
+	}
+	// 监听器名称中可能还是重建前的 CLBBinding 的 UID，由 CLBListener 控制器改名
+	if uids := bd.GetAnnotations()[constant.PreviousUIDsKey]; uids != "" {
+		lis.Annotations[constant.PreviousUIDsKey] = uids
+	}
+	// CLBListener 归属于端口池，端口池删除时一并清理
+	pp := &networkingv1alpha1.CLBPortPool{}
+	if err := r.Get(ctx, client.ObjectKey{Name: binding.Pool}, pp); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, errors.WithStack(err)
+		}
+	} else if err := controllerutil.SetControllerReference(pp, lis, r.Scheme); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	lis.Spec = *spec
+	if needBind {
+		lis.Spec.Backends = []networkingv1alpha1.CLBListenerBackend{{IP: getBackendIP(ctx, backend, binding), Port: binding.Port}}
+	}
+	if err := r.Create(ctx, lis); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	log.FromContext(ctx).V(1).Info("clblistener created", "name", lis.Name)
+	return lis, nil
+}
+
+// 删除端口绑定对应的 CLBListener（只删除属于当前 CLBBinding 的）
+func (r *CLBBindingReconciler[T]) deleteCLBListener(ctx context.Context, bd clbbinding.CLBBinding, binding *networkingv1alpha1.PortBindingStatus) error {
+	lis := &networkingv1alpha1.CLBListener{}
+	if err := r.Get(ctx, client.ObjectKey{Name: clbListenerName(binding)}, lis); err != nil {
+		return errors.WithStack(client.IgnoreNotFound(err))
+	}
+	if lis.Labels[constant.CLBBindingUIDLabelKey] != string(bd.GetUID()) || lis.DeletionTimestamp != nil {
+		return nil
+	}
+	if err := r.Delete(ctx, lis); err != nil {
+		return errors.WithStack(client.IgnoreNotFound(err))
+	}
+	return nil
+}
+
+func (r *CLBBindingReconciler[T]) listCLBListeners(ctx context.Context, bd clbbinding.CLBBinding) ([]networkingv1alpha1.CLBListener, error) {
+	list := &networkingv1alpha1.CLBListenerList{}
+	if err := r.List(ctx, list, client.MatchingLabels{constant.CLBBindingUIDLabelKey: string(bd.GetUID())}); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return list.Items, nil
+}
+
+// 获取没有对应 CLBListener 的端口绑定：七层端口绑定，以及启用 CLBListener 前创建、还未迁移的四层端口绑定
+func (r *CLBBindingReconciler[T]) getBindingsWithoutCLBListener(ctx context.Context, bd clbbinding.CLBBinding) ([]networkingv1alpha1.PortBindingStatus, error) {
+	items, err := r.listCLBListeners(ctx, bd)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	names := make(map[string]bool)
+	for _, lis := range items {
+		names[lis.Name] = true
+	}
+	bindings := []networkingv1alpha1.PortBindingStatus{}
+	for _, binding := range bd.GetStatus().PortBindings {
+		if util.IsLayer7Protocol(binding.Protocol) || !names[clbListenerName(&binding)] {
+			bindings = append(bindings, binding)
+		}
+	}
+	return bindings, nil
+}
+
+// 删除 CLBBinding 所有的 CLBListener，返回还未删除完成的数量
+func (r *CLBBindingReconciler[T]) deleteCLBListeners(ctx context.Context, bd clbbinding.CLBBinding) (int, error) {
+	items, err := r.listCLBListeners(ctx, bd)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	for i := range items {
+		lis := &items[i]
+		if lis.DeletionTimestamp != nil {
+			continue
+		}
+		if err := r.Delete(ctx, lis); err != nil && !apierrors.IsNotFound(err) {
+			return 0, errors.WithStack(err)
+		}
+	}
+	return len(items), nil
+}
+
+// 修改 CLBBinding 所有 CLBListener 的后端
+func (r *CLBBindingReconciler[T]) updateCLBListenerBackends(ctx context.Context, bd clbbinding.CLBBinding, update func([]networkingv1alpha1.CLBListenerBackend) []networkingv1alpha1.CLBListenerBackend) error {
+	items, err := r.listCLBListeners(ctx, bd)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for i := range items {
+		lis := &items[i]
+		if lis.DeletionTimestamp != nil {
+			continue
+		}
+		backends := update(lis.Spec.Backends)
+		if reflect.DeepEqual(backends, lis.Spec.Backends) {
+			continue
+		}
+		lis.Spec.Backends = backends
+		if err := r.Update(ctx, lis); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// 将后端权重设为 0，CLB 不再向其转发新连接
+func drainCLBListenerBackends(backends []networkingv1alpha1.CLBListenerBackend) []networkingv1alpha1.CLBListenerBackend {
+	if len(backends) == 0 {
+		return backends
+	}
+	drained := make([]networkingv1alpha1.CLBListenerBackend, len(backends))
+	for i, backend := range backends {
+		backend.Weight = util.GetPtr(int64(0))
+		drained[i] = backend
+	}
+	return drained
+}
+
+// CLBListener 状态变化时通知所属的 CLBBinding 重新对账
+func mapCLBListenerToCLBBinding(bindingType string) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		if obj.GetLabels()[constant.CLBBindingTypeLabelKey] != bindingType {
+			return []reconcile.Request{}
+		}
+		key := obj.GetAnnotations()[constant.CLBBindingKey]
+		if key == "" {
+			return []reconcile.Request{}
+		}
+		namespace, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "invalid clbbinding key in clblistener", "clblistener", obj.GetName(), "key", key)
+			return []reconcile.Request{}
+		}
+		return []reconcile.Request{
+			{
+				NamespacedName: types.NamespacedName{
+					Namespace: namespace,
+					Name:      name,
+				},
+			},
+		}
+	}
+}
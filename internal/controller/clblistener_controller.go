@@ -0,0 +1,353 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/clbbinding"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/pkg/clb"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+)
+
+// CLBListenerReconciler reconciles a CLBListener object
+type CLBListenerReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=networking.cloud.tencent.com,resources=clblisteners,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.cloud.tencent.com,resources=clblisteners/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=networking.cloud.tencent.com,resources=clblisteners/finalizers,verbs=update
+
+// Reconcile 对账 CLBListener：确保监听器存在、配置符合预期，且绑定的后端与 spec 一致。
+// 删除 CLBListener 时删除监听器（预创建的监听器仅解绑后端）。
+func (r *CLBListenerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return ReconcileWithFinalizer(ctx, req, r.Client, &networkingv1alpha1.CLBListener{}, r.sync, r.cleanup)
+}
+
+var ErrListenerNotPrecreated = errors.New("precreated listener not found")
+
+func (r *CLBListenerReconciler) sync(ctx context.Context, lis *networkingv1alpha1.CLBListener) (result ctrl.Result, err error) {
+	if err := r.ensureListener(ctx, lis); err != nil {
+		return r.handleError(ctx, lis, err)
+	}
+	if err := r.ensureTargets(ctx, lis); err != nil {
+		return r.handleError(ctx, lis, err)
+	}
+	status := &lis.Status
+	if status.State != networkingv1alpha1.CLBListenerStateSynced || status.Message != "" || status.ObservedGeneration != lis.Generation {
+		status.State = networkingv1alpha1.CLBListenerStateSynced
+		status.Message = ""
+		status.ObservedGeneration = lis.Generation
+		if err := r.Status().Update(ctx, lis); err != nil {
+			return result, errors.WithStack(err)
+		}
+	}
+	return result, nil
+}
+
+// 云 API 的错误记录到 event 和状态中方便排障，所有对监听器的重试都在这里统一处理
+func (r *CLBListenerReconciler) handleError(ctx context.Context, lis *networkingv1alpha1.CLBListener, err error) (result ctrl.Result, _ error) {
+	errCause := errors.Cause(err)
+	// 被云 API 限流，1s 后重新入队
+	if clb.IsRequestLimitExceededError(errCause) {
+		log.FromContext(ctx).Info("requeue due to clb api request limit exceeded when reconciling", "err", err)
+		result.RequeueAfter = time.Second
+		return result, nil
+	}
+	if apierrors.IsConflict(errCause) {
+		return result, errors.WithStack(err)
+	}
+	r.Recorder.Event(lis, corev1.EventTypeWarning, "SyncFailed", errCause.Error())
+	status := &lis.Status
+	if status.State != networkingv1alpha1.CLBListenerStateFailed || status.Message != errCause.Error() {
+		status.State = networkingv1alpha1.CLBListenerStateFailed
+		status.Message = errCause.Error()
+		if err := r.Status().Update(ctx, lis); err != nil {
+			return result, errors.WithStack(err)
+		}
+	}
+	// lb 已不存在，没必要重新入队对账，保持 Failed 状态，由 CLBBinding 重新分配端口
+	if clb.IsLoadBalancerNotExistsError(errCause) {
+		return result, nil
+	}
+	return result, errors.WithStack(err)
+}
+
+func (r *CLBListenerReconciler) updateListenerID(ctx context.Context, lis *networkingv1alpha1.CLBListener, current *clb.Listener) error {
+	clb.GetListenerCache(clb.LBKey{LbId: lis.Spec.LoadbalancerID, Region: lis.Spec.Region}).Set(current)
+	if lis.Status.ListenerID == current.ListenerId {
+		return nil
+	}
+	lis.Status.ListenerID = current.ListenerId
+	// 立即记录监听器 ID，避免重试时重复查询
+	if err := r.Status().Update(ctx, lis); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// 确保监听器存在且配置符合预期
+func (r *CLBListenerReconciler) ensureListener(ctx context.Context, lis *networkingv1alpha1.CLBListener) error {
+	spec := &lis.Spec
+	port := int64(spec.Port)
+	endPort := int64(util.GetValue(spec.EndPort))
+	var current *clb.Listener
+	var err error
+	if lis.Status.ListenerID != "" {
+		current, err = clb.GetListenerById(ctx, spec.Region, spec.LoadbalancerID, lis.Status.ListenerID)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if current == nil { // 没有记录监听器 ID 或监听器已被删除，通过端口和协议查找
+		current, err = clb.GetListenerByPort(ctx, spec.Region, spec.LoadbalancerID, port, spec.Protocol)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if current != nil && (current.Port != port || current.EndPort != endPort || current.Protocol != spec.Protocol) {
+		if spec.Precreated {
+			return errors.Errorf("precreated listener %s (%d-%d/%s) does not match the expected port %d-%d/%s", current.ListenerId, current.Port, current.EndPort, current.Protocol, port, endPort, spec.Protocol)
+		}
+		// 端口段不符合预期，删除后重建
+		if err := clb.DeleteListenerById(ctx, spec.Region, spec.LoadbalancerID, current.ListenerId); err != nil && !clb.IsListenerNotFound(err) {
+			return errors.WithStack(err)
+		}
+		clb.GetListenerCache(clb.LBKey{LbId: spec.LoadbalancerID, Region: spec.Region}).EnsureRemoved(ctx, spec.Port, spec.Protocol)
+		r.Recorder.Eventf(lis, corev1.EventTypeNormal, "ListenerDeleted", "delete unexpected listener %s (%d-%d/%s)", current.ListenerId, current.Port, current.EndPort, current.Protocol)
+		current = nil
+	}
+	if current == nil {
+		if spec.Precreated {
+			return errors.Wrapf(ErrListenerNotPrecreated, "listener %d/%s not found in lb %s", spec.Port, spec.Protocol, spec.LoadbalancerID)
+		}
+		return r.createListener(ctx, lis)
+	}
+	// 检查监听器是否被其它 CLBBinding 占用（监听器名称中记录了所属 CLBBinding 的 UID）
+	if expected := clb.ParseListenerName(util.GetValue(spec.ListenerName)); expected != nil {
+		if owner := clb.ParseListenerName(current.ListenerName); owner != nil {
+			if owner.UID != expected.UID && !clbbinding.IsPreviousUID(lis, owner.UID) {
+				return errors.Wrapf(
+					ErrListenerOwnedByOther, "listener %s (%s/%d/%s) is owned by clbbinding %s",
+					current.ListenerId, spec.LoadbalancerID, spec.Port, spec.Protocol, owner.UID,
+				)
+			}
+			// 重建前的 CLBBinding 创建的监听器，或者 CLBSharedBinding 修改了应用端口，改为当前名称
+			if current.ListenerName != *spec.ListenerName {
+				if err := clb.RenameListener(ctx, spec.Region, spec.LoadbalancerID, current, *spec.ListenerName); err != nil {
+					return errors.WithStack(err)
+				}
+				r.Recorder.Eventf(lis, corev1.EventTypeNormal, "ListenerRenamed", "rename listener %s from %s to %s", current.ListenerId, current.ListenerName, *spec.ListenerName)
+			}
+		}
+	}
+	if err := r.updateListenerID(ctx, lis, current); err != nil {
+		return errors.WithStack(err)
+	}
+	// 对账监听器的配置
+	if current.Detail != nil {
+		if drift := clb.GetListenerDrift(current.Detail, spec.ListenerTemplate, spec.Protocol); len(drift) > 0 {
+			if err := clb.ModifyListener(ctx, spec.Region, spec.LoadbalancerID, current.ListenerId, spec.Protocol, spec.ListenerTemplate); err != nil {
+				return errors.WithStack(err)
+			}
+			r.Recorder.Eventf(lis, corev1.EventTypeNormal, "ListenerUpdated", "update %s of listener %s", strings.Join(drift, ","), current.ListenerId)
+		}
+		// 证书 Secret 更新后 spec 中的证书 ID 随之变化，原地更新监听器证书（包括预创建的监听器）
+		if cert := clb.NewCertificate(spec.CertId, spec.ExtraCertIds, spec.CACertId); cert != nil && !cert.Equal(current.Detail.Certificate) {
+			if err := clb.ModifyListenersCert(ctx, spec.Region, spec.LoadbalancerID, map[string]*clb.Certificate{current.ListenerId: cert}); err != nil {
+				return errors.WithStack(err)
+			}
+			r.Recorder.Eventf(lis, corev1.EventTypeNormal, "CertRotated", "update cert of listener %s to %s", current.ListenerId, cert.Key())
+		}
+	}
+	return nil
+}
+
+func (r *CLBListenerReconciler) createListener(ctx context.Context, lis *networkingv1alpha1.CLBListener) error {
+	spec := &lis.Spec
+	extensiveParameters, err := clb.BuildExtensiveParameters(spec.ListenerTemplate, spec.Protocol)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	listenerName := util.GetValue(spec.ListenerName)
+	lisId, err := clb.CreateListenerTryBatch(
+		ctx,
+		spec.Region,
+		spec.LoadbalancerID,
+		int64(spec.Port),
+		int64(util.GetValue(spec.EndPort)),
+		spec.Protocol,
+		clb.NewCertificate(spec.CertId, spec.ExtraCertIds, spec.CACertId),
+		extensiveParameters,
+		listenerName,
+	)
+	if err != nil {
+		// lb 不存在，通知关联的端口池重新对账 lb 状态
+		if pool := lis.Labels[constant.CLBPortPoolLabelKey]; pool != "" && clb.IsLoadBalancerNotExistsError(errors.Cause(err)) {
+			notifyPortPoolReconcile(pool)
+		}
+		return errors.WithStack(err)
+	}
+	r.Recorder.Eventf(lis, corev1.EventTypeNormal, "ListenerCreated", "create listener %s", lisId)
+	return r.updateListenerID(ctx, lis, &clb.Listener{
+		Port:         int64(spec.Port),
+		EndPort:      int64(util.GetValue(spec.EndPort)),
+		Protocol:     spec.Protocol,
+		ListenerId:   lisId,
+		ListenerName: listenerName,
+	})
+}
+
+// 确保监听器绑定的后端与 spec 一致：绑定缺少的后端、解绑多余的后端、修正权重
+func (r *CLBListenerReconciler) ensureTargets(ctx context.Context, lis *networkingv1alpha1.CLBListener) error {
+	spec := &lis.Spec
+	lisId := lis.Status.ListenerID
+	targets, err := clb.DescribeTargetsTryBatch(ctx, spec.Region, spec.LoadbalancerID, lisId)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	expected := make(map[clb.Target]*networkingv1alpha1.CLBListenerBackend)
+	for i := range spec.Backends {
+		backend := &spec.Backends[i]
+		expected[clb.Target{TargetIP: backend.IP, TargetPort: int64(backend.Port)}] = backend
+	}
+	targetsToDelete := []*clb.Target{}
+	for _, target := range targets {
+		key := clb.Target{TargetIP: target.TargetIP, TargetPort: target.TargetPort}
+		backend, ok := expected[key]
+		if !ok {
+			targetsToDelete = append(targetsToDelete, target)
+			continue
+		}
+		delete(expected, key)
+		weight := clb.DefaultTargetWeight
+		if backend.Weight != nil {
+			weight = *backend.Weight
+		}
+		if target.Weight == nil || *target.Weight != weight {
+			if err := clb.ModifyTargetWeight(ctx, spec.Region, spec.LoadbalancerID, lisId, weight, target); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+	}
+	if len(targetsToDelete) > 0 {
+		r.Recorder.Eventf(lis, corev1.EventTypeNormal, "DeregisterTarget", "remove unexpected target: %v", targetsToDelete)
+		if err := clb.DeregisterTargetsForListener(ctx, spec.Region, spec.LoadbalancerID, lisId, targetsToDelete...); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	for target, backend := range expected {
+		target.Weight = backend.Weight
+		if err := clb.RegisterTarget(ctx, spec.Region, spec.LoadbalancerID, lisId, target); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// 删除监听器，预创建的监听器仅解绑后端
+func (r *CLBListenerReconciler) cleanup(ctx context.Context, lis *networkingv1alpha1.CLBListener) (result ctrl.Result, err error) {
+	spec := &lis.Spec
+	if lis.Status.State != networkingv1alpha1.CLBListenerStateDeleting {
+		lis.Status.State = networkingv1alpha1.CLBListenerStateDeleting
+		lis.Status.Message = ""
+		if err := r.Status().Update(ctx, lis); err != nil {
+			return result, errors.WithStack(err)
+		}
+	}
+	if spec.Precreated {
+		if lis.Status.ListenerID == "" {
+			return result, nil
+		}
+		if err := clb.DeregisterAllTargetsTryBatch(ctx, spec.Region, spec.LoadbalancerID, lis.Status.ListenerID); err != nil {
+			if clb.IsLoadBalancerNotExistsError(errors.Cause(err)) {
+				return result, nil
+			}
+			return result, errors.WithStack(err)
+		}
+		return result, nil
+	}
+	clb.GetListenerCache(clb.LBKey{LbId: spec.LoadbalancerID, Region: spec.Region}).EnsureRemoved(ctx, spec.Port, spec.Protocol)
+	if err := r.deleteListener(ctx, lis); err != nil {
+		errCause := errors.Cause(err)
+		switch {
+		case errCause == clb.ErrListenerNotFound, clb.IsListenerNotFound(errCause), clb.IsLoadBalancerNotExistsError(errCause):
+			return result, nil
+		}
+		if clb.IsRequestLimitExceededError(errCause) {
+			result.RequeueAfter = time.Second
+			return result, nil
+		}
+		return result, errors.WithStack(err)
+	}
+	return result, nil
+}
+
+// 删除 CLBListener 的监听器：记录了监听器 ID 时直接按 ID 删除（ID 只会记录自己创建或接管的监听器）；
+// 没有记录 ID 或批量删除失败需要按端口查找时，只删除属于同一个 CLBBinding 的监听器，不能误删端口冲突时其它对象的监听器
+func (r *CLBListenerReconciler) deleteListener(ctx context.Context, lis *networkingv1alpha1.CLBListener) error {
+	spec := &lis.Spec
+	if lisId := lis.Status.ListenerID; lisId != "" {
+		err := clb.DeleteListenerById(ctx, spec.Region, spec.LoadbalancerID, lisId)
+		if errors.Cause(err) != clb.ErrOtherListenerNotFound {
+			return errors.WithStack(err)
+		}
+		// 批量删除时同批其它监听器已被删除导致本批次未删除，按端口重试
+	}
+	current, err := clb.GetListenerByPort(ctx, spec.Region, spec.LoadbalancerID, int64(spec.Port), spec.Protocol)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if current == nil {
+		return nil
+	}
+	if current.ListenerId != lis.Status.ListenerID && !isCLBListenerOwned(lis, current) {
+		r.Recorder.Eventf(
+			lis, corev1.EventTypeWarning, "ListenerConflict",
+			"listener %s (%s/%d/%s) is not owned by this clblistener, skip deleting it",
+			current.ListenerId, spec.LoadbalancerID, spec.Port, spec.Protocol,
+		)
+		return nil
+	}
+	return errors.WithStack(clb.DeleteListener(ctx, spec.Region, spec.LoadbalancerID, current.ListenerId))
+}
+
+// 判断监听器是否属于 CLBListener 对应的 CLBBinding：监听器名称中记录的 CLBBinding UID 需要与 CLBListener 一致，
+// 或者是注解中记录的重建前的 CLBBinding 的 UID
+func isCLBListenerOwned(lis *networkingv1alpha1.CLBListener, current *clb.Listener) bool {
+	owner := clb.ParseListenerName(current.ListenerName)
+	if owner == nil {
+		return false
+	}
+	uid := lis.Labels[constant.CLBBindingUIDLabelKey]
+	if expected := clb.ParseListenerName(util.GetValue(lis.Spec.ListenerName)); expected != nil {
+		uid = expected.UID
+	}
+	return uid != "" && (owner.UID == uid || clbbinding.IsPreviousUID(lis, owner.UID))
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CLBListenerReconciler) SetupWithManager(mgr ctrl.Manager, workers int) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&networkingv1alpha1.CLBListener{}).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: workers,
+		}).
+		Named("clblistener").
+		Complete(r)
+}
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/pkg/clb"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+)
+
+func newTestCLBListener() *networkingv1alpha1.CLBListener {
+	return &networkingv1alpha1.CLBListener{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "lb-1-tcp-30000",
+			Labels: map[string]string{constant.CLBBindingUIDLabelKey: "pb-uid"},
+		},
+		Spec: networkingv1alpha1.CLBListenerSpec{
+			LoadbalancerID: "lb-1",
+			Region:         "ap-guangzhou",
+			Port:           30000,
+			Protocol:       "TCP",
+			ListenerName:   util.GetPtr(clb.BuildListenerName("pb-uid", "pool-test", 7777)),
+		},
+	}
+}
+
+func TestIsCLBListenerOwned(t *testing.T) {
+	lis := newTestCLBListener()
+	// 没有记录监听器名称的 CLBListener 通过标签中的 UID 判断
+	noName := lis.DeepCopy()
+	noName.Spec.ListenerName = nil
+	noOwner := noName.DeepCopy()
+	noOwner.Labels = nil
+	tests := []struct {
+		name         string
+		lis          *networkingv1alpha1.CLBListener
+		listenerName string
+		owned        bool
+	}{
+		{"created by same clbbinding", lis, clb.BuildListenerName("pb-uid", "pool-test", 7777), true},
+		{"created by same clbbinding before migration", lis, clb.BuildListenerName("pb-uid", "pool-test", 8888), true},
+		{"created by other clbbinding", lis, clb.BuildListenerName("other-uid", "pool-test", 7777), false},
+		{"precreated or legacy listener", lis, clb.TkeListenerName, false},
+		{"created by user", lis, "my-listener", false},
+		{"owner from label", noName, clb.BuildListenerName("pb-uid", "pool-test", 7777), true},
+		{"no owner", noOwner, clb.BuildListenerName("pb-uid", "pool-test", 7777), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := &clb.Listener{ListenerId: "lbl-1", ListenerName: tt.listenerName}
+			if owned := isCLBListenerOwned(tt.lis, current); owned != tt.owned {
+				t.Errorf("isCLBListenerOwned(%q) = %v, want %v", tt.listenerName, owned, tt.owned)
+			}
+		})
+	}
+}
+
+func TestCLBListenerHandleError(t *testing.T) {
+	ctx := context.Background()
+	lis := newTestCLBListener()
+	c := newFakeClient(t, lis)
+	recorder := record.NewFakeRecorder(100)
+	r := &CLBListenerReconciler{Client: c, Scheme: c.Scheme(), Recorder: recorder}
+	if _, err := r.handleError(ctx, lis, errors.New("create listener failed")); err == nil {
+		t.Error("expect error returned to requeue")
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(lis), lis); err != nil {
+		t.Fatal(err)
+	}
+	if lis.Status.State != networkingv1alpha1.CLBListenerStateFailed || lis.Status.Message != "create listener failed" {
+		t.Errorf("unexpected status %+v", lis.Status)
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("expect SyncFailed event recorded, got %d events", len(recorder.Events))
+	}
+}
+
+func TestCLBListenerCleanupPrecreatedWithoutListenerID(t *testing.T) {
+	ctx := context.Background()
+	lis := newTestCLBListener()
+	lis.Spec.Precreated = true
+	c := newFakeClient(t, lis)
+	r := &CLBListenerReconciler{Client: c, Scheme: c.Scheme(), Recorder: record.NewFakeRecorder(100)}
+	// 预创建的监听器不删除，没有记录监听器 ID 时也没有需要解绑的后端
+	if _, err := r.cleanup(ctx, lis); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(lis), lis); err != nil {
+		t.Fatal(err)
+	}
+	if lis.Status.State != networkingv1alpha1.CLBListenerStateDeleting {
+		t.Errorf("expect state Deleting, got %s", lis.Status.State)
+	}
+}
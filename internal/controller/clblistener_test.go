@@ -0,0 +1,201 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/clbbinding"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/pkg/clb"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+)
+
+func newTestPodBinding(bindings ...networkingv1alpha1.PortBindingStatus) *clbbinding.CLBPodBinding {
+	return clbbinding.WrapCLBPodBinding(&networkingv1alpha1.CLBPodBinding{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gs-0", UID: "pb-uid"},
+		Status:     networkingv1alpha1.CLBBindingStatus{PortBindings: bindings},
+	})
+}
+
+func newTestPortBinding(protocol string, lbPort uint16) networkingv1alpha1.PortBindingStatus {
+	return networkingv1alpha1.PortBindingStatus{
+		Port:             7777,
+		Protocol:         protocol,
+		Pool:             "pool-test",
+		Region:           "ap-guangzhou",
+		LoadbalancerId:   "lb-1",
+		LoadbalancerPort: lbPort,
+		AddressIPVersion: util.GetPtr("IPV4"),
+	}
+}
+
+func TestCLBListenerName(t *testing.T) {
+	binding := newTestPortBinding("TCP_SSL", 30000)
+	if name := clbListenerName(&binding); name != "lb-1-tcp-ssl-30000" {
+		t.Errorf("unexpected name %q", name)
+	}
+	binding.LoadbalancerEndPort = util.GetPtr(uint16(30009))
+	if name := clbListenerName(&binding); name != "lb-1-tcp-ssl-30000-30009" {
+		t.Errorf("unexpected name %q of port range", name)
+	}
+}
+
+func TestDrainCLBListenerBackends(t *testing.T) {
+	backends := []networkingv1alpha1.CLBListenerBackend{
+		{IP: "10.0.0.1", Port: 7777},
+		{IP: "10.0.0.2", Port: 7777, Weight: util.GetPtr(int64(50))},
+	}
+	drained := drainCLBListenerBackends(backends)
+	for _, backend := range drained {
+		if util.GetValue(backend.Weight) != 0 || backend.Weight == nil {
+			t.Errorf("expect backend %s drained, got weight %v", backend.IP, backend.Weight)
+		}
+	}
+	// 不能修改原有的后端列表
+	if backends[0].Weight != nil || *backends[1].Weight != 50 {
+		t.Errorf("original backends should not be modified: %+v", backends)
+	}
+}
+
+func TestMapCLBListenerToCLBBinding(t *testing.T) {
+	lis := &networkingv1alpha1.CLBListener{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "lb-1-tcp-30000",
+			Labels:      map[string]string{constant.CLBBindingTypeLabelKey: "CLBPodBinding"},
+			Annotations: map[string]string{constant.CLBBindingKey: "default/gs-0"},
+		},
+	}
+	requests := mapCLBListenerToCLBBinding("CLBPodBinding")(context.Background(), lis)
+	if len(requests) != 1 || requests[0].Namespace != "default" || requests[0].Name != "gs-0" {
+		t.Errorf("unexpected requests %v", requests)
+	}
+	if requests := mapCLBListenerToCLBBinding("CLBNodeBinding")(context.Background(), lis); len(requests) != 0 {
+		t.Errorf("clblistener of other binding type should be ignored, got %v", requests)
+	}
+}
+
+func TestEnsureCLBListener(t *testing.T) {
+	ctx := context.Background()
+	binding := newTestPortBinding("TCP", 30000)
+	bd := newTestPodBinding(binding)
+	pod := newTestPod("gs-0", nil, "10.0.0.1")
+	c := newFakeClient(t, bd.CLBPodBinding, pod)
+	r := &CLBBindingReconciler[*clbbinding.CLBPodBinding]{Client: c, Scheme: c.Scheme(), Recorder: record.NewFakeRecorder(100)}
+	backend, err := bd.GetAssociatedObject(ctx, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 需要绑定时创建 CLBListener，监听器名称中记录 CLBBinding 的 UID
+	lis, err := r.ensureCLBListener(ctx, bd, backend, &binding, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lis.Labels[constant.CLBBindingUIDLabelKey] != "pb-uid" || lis.Annotations[constant.CLBBindingKey] != "default/gs-0" {
+		t.Errorf("unexpected metadata %+v", lis.ObjectMeta)
+	}
+	if owner := clb.ParseListenerName(util.GetValue(lis.Spec.ListenerName)); owner == nil || owner.UID != "pb-uid" {
+		t.Errorf("unexpected listener name %v", lis.Spec.ListenerName)
+	}
+	if len(lis.Spec.Backends) != 1 || lis.Spec.Backends[0].IP != "10.0.0.1" || lis.Spec.Backends[0].Port != 7777 {
+		t.Errorf("unexpected backends %+v", lis.Spec.Backends)
+	}
+
+	// 等待后端就绪时已绑定的后端权重设为 0
+	lis, err = r.ensureCLBListener(ctx, bd, backend, &binding, false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lis.Spec.Backends) != 1 || lis.Spec.Backends[0].Weight == nil || *lis.Spec.Backends[0].Weight != 0 {
+		t.Errorf("expect backend drained, got %+v", lis.Spec.Backends)
+	}
+
+	// 同一个端口的 CLBListener 已属于其它 CLBBinding，不能接管，也不能删除
+	other := newTestPodBinding(binding)
+	other.UID = "other-uid"
+	if _, err := r.ensureCLBListener(ctx, other, backend, &binding, true, false); errors.Cause(err) != ErrListenerOwnedByOther {
+		t.Errorf("expect ErrListenerOwnedByOther, got %v", err)
+	}
+	if err := r.deleteCLBListener(ctx, other, &binding); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(lis), lis); err != nil {
+		t.Errorf("clblistener of other binding should not be deleted: %v", err)
+	}
+	if err := r.deleteCLBListener(ctx, bd, &binding); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(lis), lis); err == nil {
+		t.Error("expect clblistener deleted")
+	}
+}
+
+func TestEnsureDrainedWithCLBListener(t *testing.T) {
+	ctx := context.Background()
+	setUseCLBListener(t, true)
+	binding := newTestPortBinding("TCP", 30000)
+	bd := newTestPodBinding(binding)
+	bd.Spec.DrainSeconds = util.GetPtr(int64(60))
+	pod := newTestPod("gs-0", nil, "10.0.0.1")
+	c := newFakeClient(t, bd.CLBPodBinding, pod)
+	r := &CLBBindingReconciler[*clbbinding.CLBPodBinding]{Client: c, Scheme: c.Scheme(), Recorder: record.NewFakeRecorder(100)}
+	backend, err := bd.GetAssociatedObject(ctx, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis, err := r.ensureCLBListener(ctx, bd, backend, &binding, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 四层端口通过 CLBListener 摘流，由 CLBListener 控制器修改监听器上的后端权重
+	remain, err := r.ensureDrained(ctx, bd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remain.Seconds() != 60 {
+		t.Errorf("expect wait 60s, got %s", remain)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(lis), lis); err != nil {
+		t.Fatal(err)
+	}
+	if len(lis.Spec.Backends) != 1 || lis.Spec.Backends[0].Weight == nil || *lis.Spec.Backends[0].Weight != 0 {
+		t.Errorf("expect backend drained, got %+v", lis.Spec.Backends)
+	}
+}
+
+func TestGetBindingsWithoutCLBListener(t *testing.T) {
+	ctx := context.Background()
+	migrated := newTestPortBinding("TCP", 30000)
+	legacy := newTestPortBinding("UDP", 30000)
+	layer7 := newTestPortBinding("HTTP", 80)
+	bd := newTestPodBinding(migrated, legacy, layer7)
+	// 其它 CLBBinding 同名的 CLBListener 不算
+	foreign := &networkingv1alpha1.CLBListener{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   clbListenerName(&legacy),
+			Labels: map[string]string{constant.CLBBindingUIDLabelKey: "other-uid"},
+		},
+	}
+	owned := &networkingv1alpha1.CLBListener{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   clbListenerName(&migrated),
+			Labels: map[string]string{constant.CLBBindingUIDLabelKey: "pb-uid"},
+		},
+	}
+	c := newFakeClient(t, bd.CLBPodBinding, foreign, owned)
+	r := &CLBBindingReconciler[*clbbinding.CLBPodBinding]{Client: c, Scheme: c.Scheme(), Recorder: record.NewFakeRecorder(100)}
+	bindings, err := r.getBindingsWithoutCLBListener(ctx, bd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bindings) != 2 || bindings[0].Protocol != "UDP" || bindings[1].Protocol != "HTTP" {
+		t.Errorf("expect legacy and layer7 bindings, got %+v", bindings)
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -45,7 +46,7 @@ type CLBNodeBindingReconciler struct {
 // +kubebuilder:rbac:groups=networking.cloud.tencent.com,resources=clbnodebindings,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=networking.cloud.tencent.com,resources=clbnodebindings/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=networking.cloud.tencent.com,resources=clbnodebindings/finalizers,verbs=update
-// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;patch
 // +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=node.tke.cloud.tencent.com,resources=machines,verbs=get;list;watch
 
@@ -86,6 +87,15 @@ func (r *CLBNodeBindingReconciler) SetupWithManager(mgr ctrl.Manager, workers in
 			&networkingv1alpha1.CLBPortPool{},
 			handler.EnqueueRequestsFromMapFunc(r.findObjectsForCLBPortPool),
 		).
+		Watches(
+			&networkingv1alpha1.CLBPortPool{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsUsingCLBPortPool),
+			builder.WithPredicates(listenerTemplateChangedPredicate),
+		).
+		Watches(
+			&networkingv1alpha1.CLBListener{},
+			handler.EnqueueRequestsFromMapFunc(mapCLBListenerToCLBBinding("CLBNodeBinding")),
+		).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: workers,
 		}).
@@ -125,3 +135,23 @@ func (r *CLBNodeBindingReconciler) findObjectsForCLBPortPool(ctx context.Context
 	}
 	return ret
 }
+
+// 查找所有使用了该端口池的 CLBNodeBinding
+func (r *CLBNodeBindingReconciler) findObjectsUsingCLBPortPool(ctx context.Context, portpool client.Object) []reconcile.Request {
+	list := &networkingv1alpha1.CLBNodeBindingList{}
+	if err := r.List(ctx, list); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list CLBNodeBinding")
+		return []reconcile.Request{}
+	}
+	ret := []reconcile.Request{}
+	for _, cnb := range list.Items {
+		if usePortPool(portpool, cnb.Spec) {
+			ret = append(ret, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name: cnb.GetName(),
+				},
+			})
+		}
+	}
+	return ret
+}
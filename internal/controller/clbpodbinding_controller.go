@@ -24,6 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -34,6 +35,7 @@ import (
 	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
 	"github.com/tkestack/tke-extend-network-controller/internal/clbbinding"
 	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"go.uber.org/multierr"
 )
 
 // CLBPodBindingReconciler reconciles a CLBPodBinding object
@@ -46,7 +48,7 @@ type CLBPodBindingReconciler struct {
 // +kubebuilder:rbac:groups=networking.cloud.tencent.com,resources=clbpodbindings,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=networking.cloud.tencent.com,resources=clbpodbindings/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=networking.cloud.tencent.com,resources=clbpodbindings/finalizers,verbs=update
-// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;patch
 // +kubebuilder:rbac:groups=agones.dev,resources=gameservers,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=node.tke.cloud.tencent.com,resources=machines,verbs=get;list;watch
@@ -77,10 +79,15 @@ func (r *CLBPodBindingReconciler) sync(ctx context.Context, pb *networkingv1alph
 		Scheme:   r.Scheme,
 		Recorder: r.Recorder,
 	}
-	result, err = rr.sync(ctx, clbbinding.WrapCLBPodBinding(pb))
+	bd := clbbinding.WrapCLBPodBinding(pb)
+	result, err = rr.sync(ctx, bd)
 	if err != nil {
 		err = errors.WithStack(err)
 	}
+	// 无论对账是否出错，都将最新状态同步到 Pod 的 readiness gate（出错时状态可能已更新为 Failed）
+	if e := rr.ensureReadinessGate(ctx, bd); e != nil {
+		err = multierr.Append(err, errors.WithStack(e))
+	}
 	return
 }
 
@@ -96,6 +103,20 @@ func (r *CLBPodBindingReconciler) SetupWithManager(mgr ctrl.Manager, workers int
 			&networkingv1alpha1.CLBPortPool{},
 			handler.EnqueueRequestsFromMapFunc(r.findObjectsForCLBPortPool),
 		).
+		Watches(
+			&networkingv1alpha1.CLBPortPool{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsUsingCLBPortPool),
+			builder.WithPredicates(listenerTemplateChangedPredicate),
+		).
+		Watches(
+			&networkingv1alpha1.CLBListener{},
+			handler.EnqueueRequestsFromMapFunc(mapCLBListenerToCLBBinding("CLBPodBinding")),
+		).
+		Watches( // 只缓存 Secret 的元数据，Secret 内容变化时 resourceVersion 也会变化，同样会触发对账
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForSecret),
+			builder.OnlyMetadata,
+		).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: workers,
 		}).
@@ -103,6 +124,25 @@ func (r *CLBPodBindingReconciler) SetupWithManager(mgr ctrl.Manager, workers int
 		Complete(r)
 }
 
+// 证书 Secret 变化时，通知引用了该 Secret 的 CLBPodBinding 对账，更新监听器证书
+func (r *CLBPodBindingReconciler) findObjectsForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	list := &networkingv1alpha1.CLBPodBindingList{}
+	if err := r.List(ctx, list, client.InNamespace(secret.GetNamespace()), client.MatchingFields{CertSecretNameIndex: secret.GetName()}); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list clbpodbindings for secret", "secret", client.ObjectKeyFromObject(secret))
+		return []reconcile.Request{}
+	}
+	reqs := []reconcile.Request{}
+	for _, pb := range list.Items {
+		reqs = append(reqs, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      pb.Name,
+				Namespace: pb.Namespace,
+			},
+		})
+	}
+	return reqs
+}
+
 func (r *CLBPodBindingReconciler) findObjectsForPod(ctx context.Context, pod client.Object) []reconcile.Request {
 	if time := pod.GetDeletionTimestamp(); time != nil && time.IsZero() { // 忽略正在删除的 Pod，默认情况下，Pod 删除完后会自动 GC 删除掉关联的 CLBPodBinding
 		return []reconcile.Request{}
@@ -139,3 +179,24 @@ func (r *CLBPodBindingReconciler) findObjectsForCLBPortPool(ctx context.Context,
 	}
 	return ret
 }
+
+// 查找所有使用了该端口池的 CLBPodBinding
+func (r *CLBPodBindingReconciler) findObjectsUsingCLBPortPool(ctx context.Context, portpool client.Object) []reconcile.Request {
+	list := &networkingv1alpha1.CLBPodBindingList{}
+	if err := r.List(ctx, list); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list CLBPodBinding")
+		return []reconcile.Request{}
+	}
+	ret := []reconcile.Request{}
+	for _, cpb := range list.Items {
+		if usePortPool(portpool, cpb.Spec) {
+			ret = append(ret, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      cpb.GetName(),
+					Namespace: cpb.GetNamespace(),
+				},
+			})
+		}
+	}
+	return ret
+}
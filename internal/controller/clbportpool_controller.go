@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -96,6 +97,11 @@ func (r *CLBPortPoolReconciler) cleanup(ctx context.Context, pool *networkingv1a
 		}
 	}
 
+	// 清理安全组中由该端口池维护的入站规则
+	if err := r.cleanupSecurityGroupRules(ctx, pool); err != nil {
+		errs = append(errs, err)
+	}
+
 	if len(errs) > 0 {
 		return result, multierr.Combine(errs...)
 	}
@@ -114,12 +120,16 @@ func (r *CLBPortPoolReconciler) cleanupExistedLBListeners(ctx context.Context, r
 		return errors.WithStack(err)
 	}
 
-	// 遍历监听器，删除由 TKE 创建的监听器（名称为 TKE-LISTENER）
+	// 遍历监听器，删除由 TKE 创建的监听器（名称为 TKE-LISTENER 或以 TKE-LISTENER_ 开头）
 	// 这些监听器由该端口池的 CLBPodBinding 创建
 	errs := []error{}
 	for _, listener := range listeners {
 		// 只删除 TKE 创建的监听器
-		if listener.ListenerName != clb.TkeListenerName {
+		if !clb.IsTkeListener(listener.ListenerName) {
+			continue
+		}
+		// 名称中记录了端口池的监听器，只删除属于该端口池的（CLB 可能被多个端口池共用）
+		if !clb.ParseListenerName(listener.ListenerName).IsOwnedByPool(poolName) {
 			continue
 		}
 
@@ -280,6 +290,7 @@ func (r *CLBPortPoolReconciler) ensureLbStatus(ctx context.Context, pool *networ
 		constant.TkeClusterIDTagKey:   clusterinfo.ClusterId,
 		constant.TkeCreatedFlagTagKey: constant.TkeCreatedFlagYesValue,
 	}
+	securityGroupsSynced := true
 	for _, lbStatus := range status.LoadbalancerStatuses {
 		lbId := lbStatus.LoadbalancerID
 		if info, ok := lbInfos[lbId]; ok { // clb 存在，更新lb相关信息
@@ -313,6 +324,9 @@ func (r *CLBPortPoolReconciler) ensureLbStatus(ctx context.Context, pool *networ
 					continue
 				}
 			}
+			if !r.ensureSecurityGroups(ctx, pool, lbId, util.GetValue(lbStatus.AutoCreated), info.SecurityGroups) {
+				securityGroupsSynced = false
+			}
 			allocatableLBs = append(allocatableLBs, lbKey)
 		} else { // clb 不存在，通常是已删除，更新状态和端口池
 			if lbStatus.State != networkingv1alpha1.LoadBalancerStateNotFound {
@@ -324,6 +338,10 @@ func (r *CLBPortPoolReconciler) ensureLbStatus(ctx context.Context, pool *networ
 	}
 
 	status.LoadbalancerStatuses = lbStatuses
+	// 所有自动创建的 CLB 都已绑定期望的安全组后才更新记录，确保清空 securityGroups 时能解绑之前绑定的安全组
+	if securityGroupsSynced {
+		status.SecurityGroups = slices.Clone(getExpectedSecurityGroups(pool))
+	}
 
 	// 确保所有可分配的 lb 在分配器缓存中
 	if err := portpool.Allocator.EnsureLbIds(pool.Name, allocatableLBs); err != nil {
@@ -366,6 +384,12 @@ func (r *CLBPortPoolReconciler) createCLB(ctx context.Context, pool *networkingv
 	}
 	// 创建成功，记录 event，更新 state 并记录 lbId
 	r.Recorder.Eventf(pool, corev1.EventTypeNormal, "CreateLoadBalancer", "create clb success: %s", lbId)
+	// 创建 CLB 的接口不支持指定安全组，创建后立即绑定，失败时后续对账重试
+	if sgs := getExpectedSecurityGroups(pool); len(sgs) > 0 {
+		if err := clb.SetSecurityGroups(ctx, pool.GetRegion(), lbId, sgs); err != nil {
+			r.Recorder.Eventf(pool, corev1.EventTypeWarning, "EnsureSecurityGroups", "failed to set security groups for CLB %s: %s", lbId, err.Error())
+		}
+	}
 	status.LoadbalancerStatuses = append(status.LoadbalancerStatuses, networkingv1alpha1.LoadBalancerStatus{
 		LoadbalancerID: lbId,
 		AutoCreated:    util.GetPtr(true),
@@ -438,7 +462,11 @@ func (r *CLBPortPoolReconciler) ensureListenerPrecreate(ctx context.Context, poo
 			return nil
 		}
 		// 有监听器需要创建
-		lisIds, err := clb.BatchCreateListener(ctx, pool.GetRegion(), lbId, protocol, ports, endPorts)
+		extensiveParameters, err := clb.BuildExtensiveParameters(pool.Spec.ListenerTemplate, protocol)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		lisIds, err := clb.BatchCreateListener(ctx, pool.GetRegion(), lbId, protocol, extensiveParameters, ports, endPorts)
 		if err != nil {
 			return errors.WithStack(err)
 		}
@@ -519,6 +547,8 @@ func (r *CLBPortPoolReconciler) sync(ctx context.Context, pool *networkingv1alph
 	if err := r.ensureLb(ctx, pool, status); err != nil {
 		return result, errors.WithStack(err)
 	}
+	// 同步安全组规则，失败时仍先更新状态再返回错误，不影响端口分配
+	sgRulesErr := r.ensureSecurityGroupRules(ctx, pool, status)
 	// 对比 status 是否有变化，如果有变化则更新
 	if !reflect.DeepEqual(*status, pool.Status) { // 确保更新成功，否则一直重试（避免自动创建的 lb id 丢失）
 		if err := util.RetryIfPossible(func() error {
@@ -538,6 +568,9 @@ func (r *CLBPortPoolReconciler) sync(ctx context.Context, pool *networkingv1alph
 			return result, errors.WithStack(err)
 		}
 	}
+	if sgRulesErr != nil {
+		return result, errors.WithStack(sgRulesErr)
+	}
 	return result, nil
 }
 
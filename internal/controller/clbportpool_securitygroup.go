@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/pkg/clb"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+	"github.com/tkestack/tke-extend-network-controller/pkg/vpc"
+	"go.uber.org/multierr"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// 获取端口池期望 CLB 绑定的安全组，未配置时返回 nil
+func getExpectedSecurityGroups(pool *networkingv1alpha1.CLBPortPool) []string {
+	if pool.Spec.AutoCreate == nil || pool.Spec.AutoCreate.Parameters == nil {
+		return nil
+	}
+	return pool.Spec.AutoCreate.Parameters.SecurityGroups
+}
+
+func isSameSecurityGroups(current, expected []string) bool {
+	if len(current) != len(expected) {
+		return false
+	}
+	for _, sg := range expected {
+		if !slices.Contains(current, sg) {
+			return false
+		}
+	}
+	return true
+}
+
+// 确保 CLB 绑定了端口池配置的安全组：自动创建的 CLB 直接修改（securityGroups 被清空时解绑之前绑定的安全组），
+// 已有的 CLB 由用户管理，只产生事件提示不一致。返回自动创建的 CLB 的安全组是否已与端口池一致。
+func (r *CLBPortPoolReconciler) ensureSecurityGroups(ctx context.Context, pool *networkingv1alpha1.CLBPortPool, lbId string, autoCreated bool, current []string) bool {
+	expected := getExpectedSecurityGroups(pool)
+	if len(expected) == 0 && len(pool.Status.SecurityGroups) == 0 { // 从未配置过安全组，不干预 CLB 的安全组
+		return true
+	}
+	if isSameSecurityGroups(current, expected) {
+		return true
+	}
+	if !autoCreated {
+		if len(expected) > 0 {
+			r.Recorder.Eventf(pool, corev1.EventTypeWarning, "SecurityGroupDrift", "security groups of existed clb %s are [%s], expect [%s]", lbId, strings.Join(current, ","), strings.Join(expected, ","))
+		}
+		return true
+	}
+	if err := clb.SetSecurityGroups(ctx, pool.GetRegion(), lbId, expected); err != nil {
+		r.Recorder.Eventf(pool, corev1.EventTypeWarning, "EnsureSecurityGroups", "failed to set security groups for CLB %s: %s", lbId, err.Error())
+		return false
+	}
+	if len(expected) == 0 {
+		r.Recorder.Eventf(pool, corev1.EventTypeNormal, "EnsureSecurityGroups", "unbind security groups [%s] of CLB %s", strings.Join(current, ","), lbId)
+	} else {
+		r.Recorder.Eventf(pool, corev1.EventTypeNormal, "EnsureSecurityGroups", "set security groups of CLB %s to [%s]", lbId, strings.Join(expected, ","))
+	}
+	return true
+}
+
+// 端口池维护的入站规则的描述，用于识别属于该端口池的规则
+func securityGroupRuleDescription(poolName string) string {
+	return fmt.Sprintf("clb-port-pool/%s", poolName)
+}
+
+// 确保安全组中放通端口池端口范围的入站规则与端口池一致（需启用 syncSecurityGroupRules），
+// 从 securityGroups 中移除的安全组或关闭 syncSecurityGroupRules 后，清理之前维护的规则，维护了规则的安全组记录到 status 中。
+func (r *CLBPortPoolReconciler) ensureSecurityGroupRules(ctx context.Context, pool *networkingv1alpha1.CLBPortPool, status *networkingv1alpha1.CLBPortPoolStatus) error {
+	var expected []string
+	if params := pool.Spec.AutoCreate; params != nil && params.Parameters != nil && util.GetValue(params.Parameters.SyncSecurityGroupRules) {
+		expected = params.Parameters.SecurityGroups
+	}
+	description := securityGroupRuleDescription(pool.Name)
+	synced := []string{}
+	errs := []error{}
+	// 清理不再需要维护规则的安全组，清理失败的保留在 status 中下次重试
+	for _, sgId := range pool.Status.SecurityGroupRules {
+		if slices.Contains(expected, sgId) {
+			continue
+		}
+		deleted, err := vpc.DeleteIngressRules(ctx, pool.GetRegion(), sgId, description)
+		if err != nil {
+			r.Recorder.Eventf(pool, corev1.EventTypeWarning, "DeleteSecurityGroupRules", "failed to delete ingress rules of security group %s: %s", sgId, err.Error())
+			errs = append(errs, errors.WithStack(err))
+			synced = append(synced, sgId)
+			continue
+		}
+		if deleted {
+			r.Recorder.Eventf(pool, corev1.EventTypeNormal, "DeleteSecurityGroupRules", "delete ingress rules of security group %s", sgId)
+		}
+	}
+	if len(expected) > 0 {
+		endPort := uint16(65535)
+		if pool.Spec.EndPort != nil {
+			endPort = *pool.Spec.EndPort
+		}
+		portRange := fmt.Sprintf("%d-%d", pool.Spec.StartPort, endPort)
+		if endPort == pool.Spec.StartPort {
+			portRange = fmt.Sprint(endPort)
+		}
+		for _, sgId := range expected {
+			// 先记录再同步，同步失败时可能已创建部分规则，也需要在之后清理
+			synced = append(synced, sgId)
+			changed, err := vpc.EnsureIngressPortRange(ctx, pool.GetRegion(), sgId, description, portRange)
+			if err != nil {
+				r.Recorder.Eventf(pool, corev1.EventTypeWarning, "EnsureSecurityGroupRules", "failed to ensure ingress rules of security group %s: %s", sgId, err.Error())
+				errs = append(errs, errors.WithStack(err))
+				continue
+			}
+			if changed {
+				r.Recorder.Eventf(pool, corev1.EventTypeNormal, "EnsureSecurityGroupRules", "ensure ingress rules of security group %s allow port %s", sgId, portRange)
+			}
+		}
+	}
+	if len(synced) == 0 {
+		synced = nil
+	}
+	status.SecurityGroupRules = synced
+	return multierr.Combine(errs...)
+}
+
+// 删除端口池时清理安全组中由该端口池维护的入站规则
+func (r *CLBPortPoolReconciler) cleanupSecurityGroupRules(ctx context.Context, pool *networkingv1alpha1.CLBPortPool) error {
+	sgIds := slices.Clone(pool.Status.SecurityGroupRules)
+	if params := pool.Spec.AutoCreate; params != nil && params.Parameters != nil && util.GetValue(params.Parameters.SyncSecurityGroupRules) {
+		for _, sgId := range params.Parameters.SecurityGroups {
+			if !slices.Contains(sgIds, sgId) {
+				sgIds = append(sgIds, sgId)
+			}
+		}
+	}
+	errs := []error{}
+	for _, sgId := range sgIds {
+		if _, err := vpc.DeleteIngressRules(ctx, pool.GetRegion(), sgId, securityGroupRuleDescription(pool.Name)); err != nil {
+			errs = append(errs, errors.WithStack(err))
+		}
+	}
+	return multierr.Combine(errs...)
+}
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+)
+
+func TestEnsureSecurityGroupsWithoutChange(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	r := &CLBPortPoolReconciler{Recorder: recorder}
+	pool := &networkingv1alpha1.CLBPortPool{ObjectMeta: metav1.ObjectMeta{Name: "pool"}}
+	// 从未配置过安全组，不修改 CLB 上用户绑定的安全组
+	if !r.ensureSecurityGroups(context.Background(), pool, "lb-1", true, []string{"sg-user"}) {
+		t.Error("expect security groups not configured treated as synced")
+	}
+	// 清空 securityGroups 后，已有的 CLB 由用户管理，不解绑也不产生告警
+	pool.Status.SecurityGroups = []string{"sg-1"}
+	if !r.ensureSecurityGroups(context.Background(), pool, "lb-1", false, []string{"sg-1"}) {
+		t.Error("expect existed clb treated as synced")
+	}
+	pool.Spec.AutoCreate = &networkingv1alpha1.AutoCreateConfig{Parameters: &networkingv1alpha1.CreateLBParameters{SecurityGroups: []string{"sg-2", "sg-1"}}}
+	if !r.ensureSecurityGroups(context.Background(), pool, "lb-1", true, []string{"sg-1", "sg-2"}) {
+		t.Error("expect same security groups in different order treated as synced")
+	}
+	if len(recorder.Events) != 0 {
+		t.Errorf("expect no events, got %d", len(recorder.Events))
+	}
+}
+
+func TestEnsureSecurityGroupRulesDisabled(t *testing.T) {
+	r := &CLBPortPoolReconciler{Recorder: record.NewFakeRecorder(10)}
+	pool := &networkingv1alpha1.CLBPortPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool"},
+		Spec: networkingv1alpha1.CLBPortPoolSpec{
+			AutoCreate: &networkingv1alpha1.AutoCreateConfig{Parameters: &networkingv1alpha1.CreateLBParameters{
+				SecurityGroups:         []string{"sg-1"},
+				SyncSecurityGroupRules: util.GetPtr(false),
+			}},
+		},
+	}
+	status := pool.Status.DeepCopy()
+	if err := r.ensureSecurityGroupRules(context.Background(), pool, status); err != nil {
+		t.Fatal(err)
+	}
+	if status.SecurityGroupRules != nil {
+		t.Errorf("expect no security group rules recorded, got %v", status.SecurityGroupRules)
+	}
+}
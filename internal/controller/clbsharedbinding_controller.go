@@ -0,0 +1,539 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/internal/portpool"
+	"github.com/tkestack/tke-extend-network-controller/pkg/clb"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+)
+
+const clbSharedBindingType = "CLBSharedBinding"
+
+// CLBSharedBindingReconciler reconciles a CLBSharedBinding object
+type CLBSharedBindingReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=networking.cloud.tencent.com,resources=clbsharedbindings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.cloud.tencent.com,resources=clbsharedbindings/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=networking.cloud.tencent.com,resources=clbsharedbindings/finalizers,verbs=update
+// +kubebuilder:rbac:groups=networking.cloud.tencent.com,resources=clblisteners,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+
+// Reconcile 对账 CLBSharedBinding：从端口池分配一个端口，通过 CLBListener 将该端口的监听器绑定到所有选中且就绪的 Pod，
+// 并将监听器的外部地址写入 status。
+func (r *CLBSharedBindingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return ReconcileWithFinalizer(ctx, req, r.Client, &networkingv1alpha1.CLBSharedBinding{}, r.sync, r.cleanup)
+}
+
+func (r *CLBSharedBindingReconciler) sync(ctx context.Context, sb *networkingv1alpha1.CLBSharedBinding) (result ctrl.Result, err error) {
+	if sb.Status.State == "" {
+		if err := r.ensureState(ctx, sb, networkingv1alpha1.CLBBindingStatePending, ""); err != nil {
+			return result, errors.WithStack(err)
+		}
+	}
+	if err := r.ensureSharedBinding(ctx, sb); err != nil {
+		return r.handleError(ctx, sb, err)
+	}
+	return result, nil
+}
+
+func (r *CLBSharedBindingReconciler) ensureState(ctx context.Context, sb *networkingv1alpha1.CLBSharedBinding, state networkingv1alpha1.CLBBindingState, message string) error {
+	status := &sb.Status
+	if status.State == state && status.Message == message {
+		return nil
+	}
+	status.State = state
+	status.Message = message
+	if err := r.Status().Update(ctx, sb); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func (r *CLBSharedBindingReconciler) handleError(ctx context.Context, sb *networkingv1alpha1.CLBSharedBinding, err error) (result ctrl.Result, _ error) {
+	errCause := errors.Cause(err)
+	switch errCause {
+	case ErrLBNotFoundInPool: // lb 扩容了但还未写入端口池的 status 中，重新入队重试
+		log.FromContext(ctx).Info("lb info not found in pool yet, will retry", "err", err)
+		result.RequeueAfter = time.Second
+		return result, nil
+	case ErrCLBListenerDeleting: // 端口上一个使用者的 CLBListener 还在删除中，等待删除完成
+		log.FromContext(ctx).Info("wait clblistener to be deleted, will retry", "err", err)
+		result.RequeueAfter = time.Second
+		return result, nil
+	case portpool.ErrPortPoolNotAllocatable:
+		if err := r.ensureState(ctx, sb, networkingv1alpha1.CLBBindingStatePortPoolNotAllocatable, ""); err != nil {
+			return result, errors.WithStack(err)
+		}
+		return result, nil
+	case portpool.ErrNoPortAvailable:
+		r.Recorder.Event(sb, corev1.EventTypeWarning, "NoPortAvailable", "no port available in port pool, please add clb to port pool")
+		if err := r.ensureState(ctx, sb, networkingv1alpha1.CLBBindingStateNoPortAvailable, ""); err != nil {
+			return result, errors.WithStack(err)
+		}
+		return result, nil
+	}
+	if _, ok := errCause.(*portpool.ErrPoolNotFound); ok {
+		pp := &networkingv1alpha1.CLBPortPool{}
+		if err := r.Get(ctx, client.ObjectKey{Name: sb.Spec.Pool}, pp); err != nil {
+			if apierrors.IsNotFound(err) {
+				r.Recorder.Eventf(sb, corev1.EventTypeWarning, "PoolNotFound", "port pool %q not found, please check the port pool name", sb.Spec.Pool)
+				if err := r.ensureState(ctx, sb, networkingv1alpha1.CLBBindingStatePortPoolNotFound, ""); err != nil {
+					return result, errors.WithStack(err)
+				}
+				return result, nil
+			}
+			return result, errors.WithStack(err)
+		}
+		// 端口池存在但还未加入分配器缓存，端口池就绪后会触发重新对账
+		log.FromContext(ctx).Info("pool found but not exist in pool allocator yet, will retry", "pool", sb.Spec.Pool)
+		result.RequeueAfter = time.Second
+		return result, nil
+	}
+	if clb.IsRequestLimitExceededError(errCause) {
+		log.FromContext(ctx).Info("requeue due to clb api request limit exceeded when reconciling", "err", err)
+		result.RequeueAfter = time.Second
+		return result, nil
+	}
+	if apierrors.IsConflict(errCause) {
+		return result, errors.WithStack(err)
+	}
+	r.Recorder.Event(sb, corev1.EventTypeWarning, "SyncFailed", errCause.Error())
+	if e := r.ensureState(ctx, sb, networkingv1alpha1.CLBBindingStateFailed, errCause.Error()); e != nil {
+		return result, errors.WithStack(e)
+	}
+	return result, errors.WithStack(err)
+}
+
+func (r *CLBSharedBindingReconciler) ensureSharedBinding(ctx context.Context, sb *networkingv1alpha1.CLBSharedBinding) error {
+	if err := r.ensurePortAllocated(ctx, sb); err != nil {
+		return errors.WithStack(err)
+	}
+	binding := sb.Status.PortBinding
+	backends, err := r.getExpectedBackends(ctx, sb, binding)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	lis, err := r.ensureCLBListener(ctx, sb, binding, backends)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if lis.Status.State == networkingv1alpha1.CLBListenerStateFailed {
+		return errors.Errorf("clblistener %s failed: %s", lis.Name, lis.Status.Message)
+	}
+	status := &sb.Status
+	newStatus := status.DeepCopy()
+	if lis.Status.ListenerID != "" {
+		newStatus.PortBinding.ListenerId = lis.Status.ListenerID
+	}
+	// CLBListener 同步完成后才认为绑定成功，CLBListener 状态变化时会触发重新对账
+	if lis.Status.State == networkingv1alpha1.CLBListenerStateSynced && lis.Status.ObservedGeneration == lis.Generation {
+		if len(backends) == 0 {
+			newStatus.State = networkingv1alpha1.CLBBindingStateNoBackend
+		} else {
+			newStatus.State = networkingv1alpha1.CLBBindingStateBound
+		}
+		newStatus.Message = ""
+		newStatus.Backends = len(backends)
+	}
+	address, err := getSharedBindingAddress(ctx, r.Client, binding)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	newStatus.Address = address
+	if !reflect.DeepEqual(newStatus, status) {
+		sb.Status = *newStatus
+		if err := r.Status().Update(ctx, sb); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// 确保已从端口池分配端口，端口池或 lb 被删除、端口池或协议被修改时重新分配
+func (r *CLBSharedBindingReconciler) ensurePortAllocated(ctx context.Context, sb *networkingv1alpha1.CLBSharedBinding) error {
+	spec := &sb.Spec
+	status := &sb.Status
+	if binding := status.PortBinding; binding != nil {
+		removeReason := ""
+		removeMsg := ""
+		if pool := portpool.Allocator.GetPool(binding.Pool); pool == nil {
+			removeReason = "PortPoolDeleted"
+			removeMsg = "port pool has been deleted"
+		} else if !pool.IsLbExists(portpool.NewLBKeyFromBinding(binding)) {
+			removeReason = "CLBDeleted"
+			removeMsg = "clb has been removed"
+		} else if binding.Pool != spec.Pool || binding.Protocol != spec.Protocol {
+			// 端口池和协议在 CRD 中不可变，防御 CEL 校验未生效（如旧版本 CRD）时的修改
+			removeReason = "SpecChanged"
+			removeMsg = fmt.Sprintf("pool or protocol changed to %s/%s", spec.Pool, spec.Protocol)
+		} else {
+			// 应用端口修改后只需更新后端端口和监听器名称，不需要重新分配
+			if binding.Port != spec.Port {
+				binding.Port = spec.Port
+				if err := r.Status().Update(ctx, sb); err != nil {
+					return errors.WithStack(err)
+				}
+			}
+			return nil
+		}
+		r.Recorder.Eventf(sb, corev1.EventTypeWarning, removeReason, "%s (%s/%s/%d/%s)", removeMsg, binding.Pool, binding.LoadbalancerId, binding.LoadbalancerPort, binding.Protocol)
+		if _, err := r.deleteCLBListener(ctx, sb, binding); err != nil {
+			return errors.WithStack(err)
+		}
+		if portpool.Allocator.ReleaseBinding(binding) {
+			notifyPortPoolReconcile(binding.Pool)
+		}
+		status.PortBinding = nil
+		status.Address = ""
+		status.Backends = 0
+		status.State = networkingv1alpha1.CLBBindingStatePending
+		if err := r.Status().Update(ctx, sb); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	// 预创建模式下只能复用预创建的监听器
+	if pool := portpool.Allocator.GetPool(spec.Pool); pool != nil && pool.IsPrecreateListenerEnabled() && !pool.IsProtocolPrecreated(spec.Protocol) {
+		return errors.Errorf("protocol %s is not precreated in port pool %s (listenerPrecreate config)", spec.Protocol, spec.Pool)
+	}
+	allocated, err := portpool.Allocator.Allocate(ctx, []string{spec.Pool}, spec.Protocol, false)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(allocated) == 0 {
+		tryRequestScaleUp(ctx, r.Client, spec.Pool)
+		return portpool.ErrNoPortAvailable
+	}
+	allocatedPort := allocated[0]
+	binding := &networkingv1alpha1.PortBindingStatus{
+		Port:             spec.Port,
+		Protocol:         allocatedPort.Protocol,
+		Pool:             allocatedPort.Name,
+		LoadbalancerId:   allocatedPort.LbId,
+		LoadbalancerPort: allocatedPort.Port,
+		Region:           allocatedPort.Region,
+	}
+	if allocatedPort.EndPort > 0 {
+		binding.LoadbalancerEndPort = &allocatedPort.EndPort
+	}
+	if lbStatus, err := NewLBStatusGetter(r.Client).Get(ctx, allocatedPort.Name, allocatedPort.LbId); err == nil {
+		binding.AddressIPVersion = lbStatus.AddressIPVersion
+	}
+	status.PortBinding = binding
+	status.State = networkingv1alpha1.CLBBindingStateAllocated
+	if err := r.Status().Update(ctx, sb); err != nil {
+		// 更新状态失败，释放已分配端口
+		allocated.Release()
+		return errors.WithStack(err)
+	}
+	notifyPortPoolReconcile(allocatedPort.Name)
+	return nil
+}
+
+// 获取需要绑定到监听器的后端：selector 选中的、未在删除中且已就绪的 Pod
+func (r *CLBSharedBindingReconciler) getExpectedBackends(ctx context.Context, sb *networkingv1alpha1.CLBSharedBinding, binding *networkingv1alpha1.PortBindingStatus) ([]networkingv1alpha1.CLBListenerBackend, error) {
+	backends := []networkingv1alpha1.CLBListenerBackend{}
+	if len(sb.Spec.Selector) == 0 {
+		return backends, nil
+	}
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList, client.InNamespace(sb.Namespace), client.MatchingLabels(sb.Spec.Selector)); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		if condition := util.GetPodCondition(pod, corev1.PodReady); condition == nil || condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		ip := getPodIP(pod, binding.AddressIPVersion)
+		if ip == "" {
+			continue
+		}
+		weight := sb.Spec.Weight
+		if val := pod.Annotations[constant.CLBWeightKey]; val != "" {
+			w, err := strconv.ParseInt(val, 10, 64)
+			if err != nil || w < 0 || w > 100 {
+				r.Recorder.Eventf(sb, corev1.EventTypeWarning, "InvalidWeight", "invalid weight %q of pod %s, must be an integer between 0 and 100", val, pod.Name)
+			} else {
+				weight = &w
+			}
+		}
+		backends = append(backends, networkingv1alpha1.CLBListenerBackend{
+			IP:     ip,
+			Port:   sb.Spec.Port,
+			Weight: weight,
+		})
+	}
+	sort.Slice(backends, func(i, j int) bool {
+		return backends[i].IP < backends[j].IP
+	})
+	return backends, nil
+}
+
+// 获取 Pod 的 IP，IPv6 的 CLB 优先使用 Pod 的 IPv6 地址
+func getPodIP(pod *corev1.Pod, addressIPVersion *string) string {
+	if util.IsIPv6LB(addressIPVersion) {
+		for _, podIP := range pod.Status.PodIPs {
+			if strings.Contains(podIP.IP, ":") {
+				return podIP.IP
+			}
+		}
+	}
+	return pod.Status.PodIP
+}
+
+// 获取监听器的外部访问地址
+func getSharedBindingAddress(ctx context.Context, c client.Client, binding *networkingv1alpha1.PortBindingStatus) (string, error) {
+	lbStatus, err := NewLBStatusGetter(c).Get(ctx, binding.Pool, binding.LoadbalancerId)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	address := util.GetValue(lbStatus.Hostname)
+	if address == "" && len(lbStatus.Ips) > 0 {
+		address = lbStatus.Ips[0]
+	}
+	if address == "" {
+		return "", nil
+	}
+	return fmt.Sprintf("%s:%d", address, binding.LoadbalancerPort), nil
+}
+
+// 构造预期的 CLBListener spec，监听器配置来自端口池的监听器模板
+func (r *CLBSharedBindingReconciler) getExpectedCLBListenerSpec(ctx context.Context, sb *networkingv1alpha1.CLBSharedBinding, binding *networkingv1alpha1.PortBindingStatus, backends []networkingv1alpha1.CLBListenerBackend) (*networkingv1alpha1.CLBListenerSpec, error) {
+	var tpl *networkingv1alpha1.ListenerTemplate
+	pp := &networkingv1alpha1.CLBPortPool{}
+	if err := r.Get(ctx, client.ObjectKey{Name: binding.Pool}, pp); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, errors.WithStack(err)
+		}
+	} else if pp.Spec.ListenerTemplate != nil {
+		tpl = pp.Spec.ListenerTemplate.DeepCopy()
+	}
+	if sb.Spec.HealthCheck != nil {
+		if tpl == nil {
+			tpl = &networkingv1alpha1.ListenerTemplate{}
+		}
+		tpl.HealthCheck = clb.MergeHealthCheck(tpl.HealthCheck, sb.Spec.HealthCheck)
+	}
+	precreated := false
+	if pool := portpool.Allocator.GetPool(binding.Pool); pool != nil && pool.IsPrecreateListenerEnabled() {
+		precreated = true
+	}
+	return &networkingv1alpha1.CLBListenerSpec{
+		LoadbalancerID:   binding.LoadbalancerId,
+		Region:           binding.Region,
+		Port:             binding.LoadbalancerPort,
+		EndPort:          binding.LoadbalancerEndPort,
+		Protocol:         binding.Protocol,
+		ListenerName:     util.GetPtr(clb.BuildListenerName(string(sb.UID), binding.Pool, binding.Port)),
+		ListenerTemplate: tpl,
+		Precreated:       precreated,
+		Backends:         backends,
+	}, nil
+}
+
+// 确保端口对应的 CLBListener 存在，且后端为当前选中的所有 Pod
+func (r *CLBSharedBindingReconciler) ensureCLBListener(ctx context.Context, sb *networkingv1alpha1.CLBSharedBinding, binding *networkingv1alpha1.PortBindingStatus, backends []networkingv1alpha1.CLBListenerBackend) (*networkingv1alpha1.CLBListener, error) {
+	spec, err := r.getExpectedCLBListenerSpec(ctx, sb, binding, backends)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	lis := &networkingv1alpha1.CLBListener{}
+	if err := r.Get(ctx, client.ObjectKey{Name: clbListenerName(binding)}, lis); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, errors.WithStack(err)
+		}
+		return r.createCLBListener(ctx, sb, binding, spec)
+	}
+	if lis.DeletionTimestamp != nil {
+		return nil, errors.Wrapf(ErrCLBListenerDeleting, "clblistener %s is deleting", lis.Name)
+	}
+	if lis.Labels[constant.CLBBindingUIDLabelKey] != string(sb.UID) {
+		r.Recorder.Eventf(
+			sb, corev1.EventTypeWarning, "ListenerConflict",
+			"clblistener %s (%s/%d/%s) is owned by other clbbinding (%s)",
+			lis.Name, binding.LoadbalancerId, binding.LoadbalancerPort, binding.Protocol, lis.Annotations[constant.CLBBindingKey],
+		)
+		return nil, errors.Wrapf(ErrListenerOwnedByOther, "clblistener %s is owned by clbbinding %s", lis.Name, lis.Annotations[constant.CLBBindingKey])
+	}
+	if !reflect.DeepEqual(*spec, lis.Spec) {
+		lis.Spec = *spec
+		if err := r.Update(ctx, lis); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	return lis, nil
+}
+
+func (r *CLBSharedBindingReconciler) createCLBListener(ctx context.Context, sb *networkingv1alpha1.CLBSharedBinding, binding *networkingv1alpha1.PortBindingStatus, spec *networkingv1alpha1.CLBListenerSpec) (*networkingv1alpha1.CLBListener, error) {
+	lis := &networkingv1alpha1.CLBListener{}
+	lis.Name = clbListenerName(binding)
+	lis.Labels = map[string]string{
+		constant.CLBPortPoolLabelKey:    binding.Pool,
+		constant.CLBBindingUIDLabelKey:  string(sb.UID),
+		constant.CLBBindingTypeLabelKey: clbSharedBindingType,
+	}
+	lis.Annotations = map[string]string{
+		constant.CLBBindingKey: client.ObjectKeyFromObject(sb).String(),
+	}
+	// CLBListener 归属于端口池，端口池删除时一并清理
+	pp := &networkingv1alpha1.CLBPortPool{}
+	if err := r.Get(ctx, client.ObjectKey{Name: binding.Pool}, pp); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, errors.WithStack(err)
+		}
+	} else if err := controllerutil.SetControllerReference(pp, lis, r.Scheme); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	lis.Spec = *spec
+	if err := r.Create(ctx, lis); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	log.FromContext(ctx).V(1).Info("clblistener created", "name", lis.Name)
+	return lis, nil
+}
+
+// 删除端口对应的 CLBListener（只删除属于当前 CLBSharedBinding 的），返回 CLBListener 是否已不存在
+func (r *CLBSharedBindingReconciler) deleteCLBListener(ctx context.Context, sb *networkingv1alpha1.CLBSharedBinding, binding *networkingv1alpha1.PortBindingStatus) (bool, error) {
+	lis := &networkingv1alpha1.CLBListener{}
+	if err := r.Get(ctx, client.ObjectKey{Name: clbListenerName(binding)}, lis); err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, errors.WithStack(err)
+	}
+	if lis.Labels[constant.CLBBindingUIDLabelKey] != string(sb.UID) {
+		return true, nil
+	}
+	if lis.DeletionTimestamp != nil {
+		return false, nil
+	}
+	if err := r.Delete(ctx, lis); err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, errors.WithStack(err)
+	}
+	return false, nil
+}
+
+// 删除监听器后再释放端口，避免端口被重新分配时监听器还未删除
+func (r *CLBSharedBindingReconciler) cleanup(ctx context.Context, sb *networkingv1alpha1.CLBSharedBinding) (result ctrl.Result, err error) {
+	if err := r.ensureState(ctx, sb, networkingv1alpha1.CLBBindingStateDeleting, ""); err != nil {
+		return result, errors.WithStack(err)
+	}
+	binding := sb.Status.PortBinding
+	if binding == nil {
+		return result, nil
+	}
+	deleted, err := r.deleteCLBListener(ctx, sb, binding)
+	if err != nil {
+		return result, errors.WithStack(err)
+	}
+	if !deleted {
+		log.FromContext(ctx).V(3).Info("wait clblistener to be deleted")
+		result.RequeueAfter = 3 * time.Second
+		return result, nil
+	}
+	if portpool.Allocator.ReleaseBinding(binding) {
+		notifyPortPoolReconcile(binding.Pool)
+	}
+	return result, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CLBSharedBindingReconciler) SetupWithManager(mgr ctrl.Manager, workers int) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&networkingv1alpha1.CLBSharedBinding{}).
+		Watches(
+			&corev1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForPod),
+		).
+		Watches(
+			&networkingv1alpha1.CLBPortPool{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForCLBPortPool),
+		).
+		Watches(
+			&networkingv1alpha1.CLBListener{},
+			handler.EnqueueRequestsFromMapFunc(mapCLBListenerToCLBBinding(clbSharedBindingType)),
+		).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: workers,
+		}).
+		Named("clbsharedbinding").
+		Complete(r)
+}
+
+// 查找选中了该 Pod 的 CLBSharedBinding
+func (r *CLBSharedBindingReconciler) findObjectsForPod(ctx context.Context, pod client.Object) []reconcile.Request {
+	list := &networkingv1alpha1.CLBSharedBindingList{}
+	if err := r.List(ctx, list, client.InNamespace(pod.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list CLBSharedBinding")
+		return []reconcile.Request{}
+	}
+	ret := []reconcile.Request{}
+	for _, sb := range list.Items {
+		if len(sb.Spec.Selector) == 0 || !labels.SelectorFromSet(sb.Spec.Selector).Matches(labels.Set(pod.GetLabels())) {
+			continue
+		}
+		ret = append(ret, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      sb.Name,
+				Namespace: sb.Namespace,
+			},
+		})
+	}
+	return ret
+}
+
+// 查找使用了该端口池的 CLBSharedBinding
+func (r *CLBSharedBindingReconciler) findObjectsForCLBPortPool(ctx context.Context, portpool client.Object) []reconcile.Request {
+	list := &networkingv1alpha1.CLBSharedBindingList{}
+	if err := r.List(ctx, list); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list CLBSharedBinding")
+		return []reconcile.Request{}
+	}
+	ret := []reconcile.Request{}
+	for _, sb := range list.Items {
+		if sb.Spec.Pool != portpool.GetName() {
+			continue
+		}
+		ret = append(ret, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      sb.Name,
+				Namespace: sb.Namespace,
+			},
+		})
+	}
+	return ret
+}
@@ -0,0 +1,229 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/internal/portpool"
+	"github.com/tkestack/tke-extend-network-controller/pkg/eventsource"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+)
+
+// 在端口分配器中注册测试用的端口池，并返回对应的 CLBPortPool 对象
+func setupTestPortPool(t *testing.T, name string, lbIds ...string) *networkingv1alpha1.CLBPortPool {
+	t.Helper()
+	pp := &networkingv1alpha1.CLBPortPool{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       networkingv1alpha1.CLBPortPoolSpec{StartPort: 30000, Region: util.GetPtr("ap-guangzhou")},
+	}
+	lbKeys := []portpool.LBKey{}
+	for _, lbId := range lbIds {
+		lbKeys = append(lbKeys, portpool.NewLBKey(lbId, "ap-guangzhou"))
+		pp.Status.LoadbalancerStatuses = append(pp.Status.LoadbalancerStatuses, networkingv1alpha1.LoadBalancerStatus{
+			LoadbalancerID: lbId,
+			Ips:            []string{"1.1.1.1"},
+		})
+	}
+	portpool.Allocator.EnsurePool(pp)
+	if err := portpool.Allocator.EnsureLbIds(name, lbKeys); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { portpool.Allocator.RemovePool(name) })
+	return pp
+}
+
+// 端口释放后会通知端口池对账，测试中消费掉通知避免阻塞
+func drainPortPoolEvents(t *testing.T) {
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-eventsource.PortPool:
+			case <-done:
+				return
+			}
+		}
+	}()
+	t.Cleanup(func() { close(done) })
+}
+
+func newTestSharedBinding(pool string) *networkingv1alpha1.CLBSharedBinding {
+	return &networkingv1alpha1.CLBSharedBinding{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "room", UID: "sb-uid"},
+		Spec: networkingv1alpha1.CLBSharedBindingSpec{
+			Selector: map[string]string{"app": "room"},
+			Port:     8080,
+			Protocol: "TCP",
+			Pool:     pool,
+		},
+		Status: networkingv1alpha1.CLBSharedBindingStatus{
+			State: networkingv1alpha1.CLBBindingStateAllocated,
+			PortBinding: &networkingv1alpha1.PortBindingStatus{
+				Port:             8080,
+				Protocol:         "TCP",
+				Pool:             pool,
+				Region:           "ap-guangzhou",
+				LoadbalancerId:   "lb-1",
+				LoadbalancerPort: 30000,
+			},
+		},
+	}
+}
+
+func TestCLBSharedBindingSync(t *testing.T) {
+	ctx := context.Background()
+	pp := setupTestPortPool(t, "pool-shared-sync", "lb-1")
+	sb := newTestSharedBinding(pp.Name)
+	notReady := newTestPod("room-2", map[string]string{"app": "room"}, "10.0.0.3")
+	notReady.Status.Conditions = nil
+	c := newFakeClient(t, pp, sb,
+		newTestPod("room-0", map[string]string{"app": "room"}, "10.0.0.2"),
+		newTestPod("room-1", map[string]string{"app": "room"}, "10.0.0.1"),
+		notReady,
+	)
+	r := &CLBSharedBindingReconciler{Client: c, Scheme: c.Scheme(), Recorder: record.NewFakeRecorder(100)}
+	if _, err := r.sync(ctx, sb); err != nil {
+		t.Fatal(err)
+	}
+	lis := &networkingv1alpha1.CLBListener{}
+	if err := c.Get(ctx, client.ObjectKey{Name: clbListenerName(sb.Status.PortBinding)}, lis); err != nil {
+		t.Fatal(err)
+	}
+	if lis.Labels[constant.CLBBindingUIDLabelKey] != string(sb.UID) {
+		t.Errorf("expect clblistener owned by %s, got labels %v", sb.UID, lis.Labels)
+	}
+	// 只绑定就绪的 Pod，按 IP 排序
+	if len(lis.Spec.Backends) != 2 || lis.Spec.Backends[0].IP != "10.0.0.1" || lis.Spec.Backends[1].IP != "10.0.0.2" {
+		t.Errorf("unexpected backends %+v", lis.Spec.Backends)
+	}
+	if sb.Status.Address != "1.1.1.1:30000" {
+		t.Errorf("unexpected address %q", sb.Status.Address)
+	}
+}
+
+func TestCLBSharedBindingListenerConflict(t *testing.T) {
+	ctx := context.Background()
+	pp := setupTestPortPool(t, "pool-shared-conflict", "lb-1")
+	sb := newTestSharedBinding(pp.Name)
+	// 端口对应的 CLBListener 属于其它 CLBBinding，不能接管
+	other := &networkingv1alpha1.CLBListener{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        clbListenerName(sb.Status.PortBinding),
+			Labels:      map[string]string{constant.CLBBindingUIDLabelKey: "other-uid"},
+			Annotations: map[string]string{constant.CLBBindingKey: "default/other"},
+		},
+		Spec: networkingv1alpha1.CLBListenerSpec{LoadbalancerID: "lb-1", Port: 30000, Protocol: "TCP"},
+	}
+	c := newFakeClient(t, pp, sb, other)
+	r := &CLBSharedBindingReconciler{Client: c, Scheme: c.Scheme(), Recorder: record.NewFakeRecorder(100)}
+	err := r.ensureSharedBinding(ctx, sb)
+	if errors.Cause(err) != ErrListenerOwnedByOther {
+		t.Fatalf("expect ErrListenerOwnedByOther, got %v", err)
+	}
+	lis := &networkingv1alpha1.CLBListener{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(other), lis); err != nil {
+		t.Fatal(err)
+	}
+	if lis.Labels[constant.CLBBindingUIDLabelKey] != "other-uid" || len(lis.Spec.Backends) != 0 {
+		t.Errorf("clblistener of other binding should not be modified: %+v", lis)
+	}
+
+	// 清理时也不能删除其它 CLBBinding 的 CLBListener
+	drainPortPoolEvents(t)
+	if _, err := r.cleanup(ctx, sb); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(other), lis); err != nil {
+		t.Errorf("clblistener of other binding should not be deleted: %v", err)
+	}
+}
+
+func TestCLBSharedBindingCleanup(t *testing.T) {
+	ctx := context.Background()
+	drainPortPoolEvents(t)
+	pp := setupTestPortPool(t, "pool-shared-cleanup", "lb-1")
+	sb := newTestSharedBinding(pp.Name)
+	c := newFakeClient(t, pp, sb)
+	r := &CLBSharedBindingReconciler{Client: c, Scheme: c.Scheme(), Recorder: record.NewFakeRecorder(100)}
+	if _, err := r.sync(ctx, sb); err != nil {
+		t.Fatal(err)
+	}
+	binding := sb.Status.PortBinding
+	lbKey := portpool.NewLBKeyFromBinding(binding)
+	port := portpool.NewProtocolPortFromBinding(binding)
+	portpool.Allocator.MarkAllocated(binding.Pool, lbKey, binding.LoadbalancerPort, nil, binding.Protocol)
+
+	// 先删除 CLBListener，删除完成前不释放端口
+	result, err := r.cleanup(ctx, sb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.RequeueAfter == 0 {
+		t.Error("expect requeue to wait clblistener deleted")
+	}
+	if !portpool.Allocator.IsAllocated(binding.Pool, lbKey, port) {
+		t.Error("port should not be released before clblistener deleted")
+	}
+	if _, err := r.cleanup(ctx, sb); err != nil {
+		t.Fatal(err)
+	}
+	if portpool.Allocator.IsAllocated(binding.Pool, lbKey, port) {
+		t.Error("expect port released after clblistener deleted")
+	}
+}
+
+func TestCLBSharedBindingSpecChanged(t *testing.T) {
+	ctx := context.Background()
+	drainPortPoolEvents(t)
+	pp := setupTestPortPool(t, "pool-shared-changed", "lb-1")
+	sb := newTestSharedBinding(pp.Name)
+	c := newFakeClient(t, pp, sb)
+	r := &CLBSharedBindingReconciler{Client: c, Scheme: c.Scheme(), Recorder: record.NewFakeRecorder(100)}
+
+	// 修改应用端口只更新后端端口，不重新分配
+	sb.Spec.Port = 9090
+	if err := c.Update(ctx, sb); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.ensurePortAllocated(ctx, sb); err != nil {
+		t.Fatal(err)
+	}
+	if binding := sb.Status.PortBinding; binding.Port != 9090 || binding.Pool != pp.Name || binding.LoadbalancerId != "lb-1" {
+		t.Errorf("unexpected port binding after port changed: %+v", binding)
+	}
+
+	// 修改端口池需要释放原端口再重新分配（新端口池只预创建了 UDP 监听器，TCP 分配失败）
+	newPP := &networkingv1alpha1.CLBPortPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool-shared-changed-new"},
+		Spec: networkingv1alpha1.CLBPortPoolSpec{
+			StartPort:         30000,
+			Region:            util.GetPtr("ap-guangzhou"),
+			ListenerPrecreate: &networkingv1alpha1.ListenerPrecreateConfig{Enabled: true, UDP: util.GetPtr(uint16(10))},
+		},
+	}
+	portpool.Allocator.EnsurePool(newPP)
+	t.Cleanup(func() { portpool.Allocator.RemovePool(newPP.Name) })
+	old := sb.Status.PortBinding.DeepCopy()
+	lbKey := portpool.NewLBKeyFromBinding(old)
+	portpool.Allocator.MarkAllocated(old.Pool, lbKey, old.LoadbalancerPort, nil, old.Protocol)
+	sb.Spec.Pool = newPP.Name
+	if err := c.Update(ctx, sb); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.ensurePortAllocated(ctx, sb); err == nil {
+		t.Error("expect allocate error from port pool without precreated tcp listeners")
+	}
+	if sb.Status.PortBinding != nil || sb.Status.State != networkingv1alpha1.CLBBindingStatePending {
+		t.Errorf("expect port binding released, got %+v", sb.Status)
+	}
+	if portpool.Allocator.IsAllocated(old.Pool, lbKey, portpool.NewProtocolPortFromBinding(old)) {
+		t.Error("expect port of previous pool released")
+	}
+}
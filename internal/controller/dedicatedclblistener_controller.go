@@ -0,0 +1,290 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/internal/portpool"
+	"github.com/tkestack/tke-extend-network-controller/pkg/clb"
+	"github.com/tkestack/tke-extend-network-controller/pkg/clusterinfo"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+)
+
+// DedicatedCLBListenerReconciler reconciles a DedicatedCLBListener object
+type DedicatedCLBListenerReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=networking.cloud.tencent.com,resources=dedicatedclblisteners,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.cloud.tencent.com,resources=dedicatedclblisteners/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=networking.cloud.tencent.com,resources=dedicatedclblisteners/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile 对账 DedicatedCLBListener：确保 CLB 监听器存在，且只绑定了 targetPod 指定的 Pod。
+func (r *DedicatedCLBListenerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return ReconcileWithFinalizer(ctx, req, r.Client, &networkingv1alpha1.DedicatedCLBListener{}, r.sync, r.cleanup)
+}
+
+func getDedicatedRegion(region string) string {
+	if region == "" {
+		return clusterinfo.Region
+	}
+	return region
+}
+
+func (r *DedicatedCLBListenerReconciler) sync(ctx context.Context, lis *networkingv1alpha1.DedicatedCLBListener) (result ctrl.Result, err error) {
+	if lis.Status.State == "" {
+		if err := r.ensureState(ctx, lis, networkingv1alpha1.DedicatedCLBListenerStatePending, "", ""); err != nil {
+			return result, errors.WithStack(err)
+		}
+	}
+	if err := r.ensureListener(ctx, lis); err != nil {
+		return r.handleError(ctx, lis, err)
+	}
+	state, address, err := r.ensureTargetPod(ctx, lis)
+	if err != nil {
+		return r.handleError(ctx, lis, err)
+	}
+	if err := r.ensureState(ctx, lis, state, "", address); err != nil {
+		return result, errors.WithStack(err)
+	}
+	return result, nil
+}
+
+func (r *DedicatedCLBListenerReconciler) ensureState(ctx context.Context, lis *networkingv1alpha1.DedicatedCLBListener, state, message, address string) error {
+	status := &lis.Status
+	if status.State == state && status.Message == message && status.Address == address {
+		return nil
+	}
+	status.State = state
+	status.Message = message
+	status.Address = address
+	if err := r.Status().Update(ctx, lis); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func (r *DedicatedCLBListenerReconciler) handleError(ctx context.Context, lis *networkingv1alpha1.DedicatedCLBListener, err error) (result ctrl.Result, _ error) {
+	errCause := errors.Cause(err)
+	if clb.IsRequestLimitExceededError(errCause) {
+		log.FromContext(ctx).Info("requeue due to clb api request limit exceeded when reconciling", "err", err)
+		result.RequeueAfter = time.Second
+		return result, nil
+	}
+	if apierrors.IsConflict(errCause) {
+		return result, errors.WithStack(err)
+	}
+	r.Recorder.Event(lis, corev1.EventTypeWarning, "SyncFailed", errCause.Error())
+	if e := r.ensureState(ctx, lis, networkingv1alpha1.DedicatedCLBListenerStateFailed, errCause.Error(), ""); e != nil {
+		return result, errors.WithStack(e)
+	}
+	// lb 已不存在，没必要重试
+	if clb.IsLoadBalancerNotExistsError(errCause) {
+		return result, nil
+	}
+	return result, errors.WithStack(err)
+}
+
+// 确保监听器已创建，并记录监听器 ID
+func (r *DedicatedCLBListenerReconciler) ensureListener(ctx context.Context, lis *networkingv1alpha1.DedicatedCLBListener) error {
+	spec := &lis.Spec
+	region := getDedicatedRegion(spec.LbRegion)
+	lisId := lis.Status.ListenerId
+	if lisId != "" {
+		current, err := clb.GetListenerById(ctx, region, spec.LbId, lisId)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if current != nil {
+			return nil
+		}
+		log.FromContext(ctx).Info("listener not found, try to recreate", "listenerId", lisId)
+	}
+	current, err := clb.GetListenerByPort(ctx, region, spec.LbId, spec.LbPort, spec.Protocol)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if current != nil {
+		// 只复用自己之前创建的监听器（创建后记录 ID 前失败重试的场景），端口被其它监听器占用时视为冲突
+		if !isDedicatedListenerOwned(lis, current) {
+			r.Recorder.Eventf(lis, corev1.EventTypeWarning, "ListenerConflict", "port %d/%s of clb %s is used by other listener %s (%s)", spec.LbPort, spec.Protocol, spec.LbId, current.ListenerId, current.ListenerName)
+			return errors.Wrapf(ErrListenerOwnedByOther, "listener %s (%s/%d/%s) is not created by %s", current.ListenerId, spec.LbId, spec.LbPort, spec.Protocol, lis.Name)
+		}
+		lisId = current.ListenerId
+	} else {
+		lisId, err = clb.CreateListenerTryBatch(ctx, region, spec.LbId, spec.LbPort, util.GetValue(spec.LbEndPort), spec.Protocol, nil, spec.ExtensiveParameters, dedicatedCLBListenerName(lis))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		r.Recorder.Eventf(lis, corev1.EventTypeNormal, "ListenerCreated", "create listener %s", lisId)
+	}
+	if lis.Status.ListenerId != lisId {
+		lis.Status.ListenerId = lisId
+		if err := r.Status().Update(ctx, lis); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// DedicatedCLBListener 创建的监听器名称，记录了 DedicatedCLBListener 的 UID 和所属的 DedicatedCLBService
+func dedicatedCLBListenerName(lis *networkingv1alpha1.DedicatedCLBListener) string {
+	return clb.BuildListenerName(string(lis.UID), lis.Labels[constant.DedicatedCLBServiceLabelKey], uint16(lis.Spec.LbPort))
+}
+
+// 判断监听器是否由该 DedicatedCLBListener 创建
+func isDedicatedListenerOwned(lis *networkingv1alpha1.DedicatedCLBListener, current *clb.Listener) bool {
+	owner := clb.ParseListenerName(current.ListenerName)
+	return owner != nil && owner.UID == string(lis.UID)
+}
+
+// 确保监听器只绑定了 targetPod，返回监听器的状态和外部地址
+func (r *DedicatedCLBListenerReconciler) ensureTargetPod(ctx context.Context, lis *networkingv1alpha1.DedicatedCLBListener) (state, address string, err error) {
+	spec := &lis.Spec
+	region := getDedicatedRegion(spec.LbRegion)
+	var expected *clb.Target
+	if tp := spec.TargetPod; tp != nil {
+		pod := &corev1.Pod{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: lis.Namespace, Name: tp.PodName}, pod); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return "", "", errors.WithStack(err)
+			}
+		} else if pod.DeletionTimestamp == nil && pod.Status.PodIP != "" {
+			expected = &clb.Target{TargetIP: pod.Status.PodIP, TargetPort: tp.TargetPort}
+		}
+	}
+	targets, err := clb.DescribeTargetsTryBatch(ctx, region, spec.LbId, lis.Status.ListenerId)
+	if err != nil {
+		return "", "", errors.WithStack(err)
+	}
+	toDelete := []*clb.Target{}
+	found := false
+	for _, target := range targets {
+		if expected != nil && target.IsSameBackend(*expected) {
+			found = true
+			continue
+		}
+		toDelete = append(toDelete, target)
+	}
+	if len(toDelete) > 0 {
+		r.Recorder.Eventf(lis, corev1.EventTypeNormal, "DeregisterTarget", "remove unexpected target: %v", toDelete)
+		if err := clb.DeregisterTargetsForListener(ctx, region, spec.LbId, lis.Status.ListenerId, toDelete...); err != nil {
+			return "", "", errors.WithStack(err)
+		}
+	}
+	if expected == nil { // 没有需要绑定的 Pod（未指定或 Pod 还没有 IP），监听器空闲
+		return networkingv1alpha1.DedicatedCLBListenerStateAvailable, "", nil
+	}
+	if !found {
+		if err := clb.RegisterTarget(ctx, region, spec.LbId, lis.Status.ListenerId, *expected); err != nil {
+			return "", "", errors.WithStack(err)
+		}
+		r.Recorder.Eventf(lis, corev1.EventTypeNormal, "RegisterTarget", "register target %s", expected)
+	}
+	host, err := clb.GetClbExternalAddress(ctx, spec.LbId, region)
+	if err != nil {
+		return "", "", errors.WithStack(err)
+	}
+	return networkingv1alpha1.DedicatedCLBListenerStateBound, fmt.Sprintf("%s:%d", host, spec.LbPort), nil
+}
+
+// 删除监听器，由 DedicatedCLBService 分配的端口在监听器删除后释放
+func (r *DedicatedCLBListenerReconciler) cleanup(ctx context.Context, lis *networkingv1alpha1.DedicatedCLBListener) (result ctrl.Result, err error) {
+	spec := &lis.Spec
+	region := getDedicatedRegion(spec.LbRegion)
+	if err := r.ensureState(ctx, lis, networkingv1alpha1.DedicatedCLBListenerStateDeleting, "", ""); err != nil {
+		return result, errors.WithStack(err)
+	}
+	clb.GetListenerCache(clb.LBKey{LbId: spec.LbId, Region: region}).EnsureRemoved(ctx, uint16(spec.LbPort), spec.Protocol)
+	if err := r.deleteListener(ctx, lis); err != nil {
+		errCause := errors.Cause(err)
+		switch {
+		case errCause == clb.ErrListenerNotFound, clb.IsListenerNotFound(errCause), clb.IsLoadBalancerNotExistsError(errCause):
+		case clb.IsRequestLimitExceededError(errCause):
+			result.RequeueAfter = time.Second
+			return result, nil
+		default:
+			r.Recorder.Event(lis, corev1.EventTypeWarning, "DeleteListener", errCause.Error())
+			return result, errors.WithStack(err)
+		}
+	}
+	if svc := lis.Labels[constant.DedicatedCLBServiceLabelKey]; svc != "" {
+		port := portpool.ProtocolPort{Port: uint16(spec.LbPort), EndPort: uint16(util.GetValue(spec.LbEndPort)), Protocol: spec.Protocol}
+		portpool.DedicatedAllocator.Release(dedicatedPoolName(lis.Namespace, svc), portpool.NewLBKey(spec.LbId, region), port)
+	}
+	return result, nil
+}
+
+// 删除 DedicatedCLBListener 创建的监听器：没有记录监听器 ID 时（创建后记录 ID 前失败）通过端口查找，
+// 只删除自己创建的监听器，不能误删端口冲突时其它对象的监听器
+func (r *DedicatedCLBListenerReconciler) deleteListener(ctx context.Context, lis *networkingv1alpha1.DedicatedCLBListener) error {
+	spec := &lis.Spec
+	region := getDedicatedRegion(spec.LbRegion)
+	lisId := lis.Status.ListenerId
+	if lisId == "" {
+		current, err := clb.GetListenerByPort(ctx, region, spec.LbId, spec.LbPort, spec.Protocol)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if current == nil || !isDedicatedListenerOwned(lis, current) {
+			return nil
+		}
+		lisId = current.ListenerId
+	}
+	return errors.WithStack(clb.DeleteListenerById(ctx, region, spec.LbId, lisId))
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DedicatedCLBListenerReconciler) SetupWithManager(mgr ctrl.Manager, workers int) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&networkingv1alpha1.DedicatedCLBListener{}).
+		Watches(
+			&corev1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForPod),
+		).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: workers,
+		}).
+		Named("dedicatedclblistener").
+		Complete(r)
+}
+
+func (r *DedicatedCLBListenerReconciler) findObjectsForPod(ctx context.Context, pod client.Object) []reconcile.Request {
+	list := &networkingv1alpha1.DedicatedCLBListenerList{}
+	if err := r.List(ctx, list, client.InNamespace(pod.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list DedicatedCLBListener")
+		return []reconcile.Request{}
+	}
+	requests := []reconcile.Request{}
+	for _, lis := range list.Items {
+		if lis.Spec.TargetPod != nil && lis.Spec.TargetPod.PodName == pod.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: lis.Namespace,
+					Name:      lis.Name,
+				},
+			})
+		}
+	}
+	return requests
+}
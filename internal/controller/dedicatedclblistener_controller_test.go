@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/pkg/clb"
+)
+
+func TestIsDedicatedListenerOwned(t *testing.T) {
+	lis := &networkingv1alpha1.DedicatedCLBListener{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "gs-0-udp-7777",
+			UID:       "lis-uid",
+			Labels:    map[string]string{constant.DedicatedCLBServiceLabelKey: "gs"},
+		},
+		Spec: networkingv1alpha1.DedicatedCLBListenerSpec{LbId: "lb-1", LbPort: 30000, Protocol: "UDP"},
+	}
+	other := lis.DeepCopy()
+	other.UID = "other-uid"
+	tests := []struct {
+		name         string
+		listenerName string
+		owned        bool
+	}{
+		{"created by itself", dedicatedCLBListenerName(lis), true},
+		{"created by other dedicated listener", dedicatedCLBListenerName(other), false},
+		{"legacy or precreated listener", clb.TkeListenerName, false},
+		{"created by user", "my-listener", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := &clb.Listener{ListenerId: "lbl-1", ListenerName: tt.listenerName}
+			if owned := isDedicatedListenerOwned(lis, current); owned != tt.owned {
+				t.Errorf("isDedicatedListenerOwned(%q) = %v, want %v", tt.listenerName, owned, tt.owned)
+			}
+		})
+	}
+}
@@ -0,0 +1,433 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	clbsdk "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/clb/v20180317"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/internal/portpool"
+	"github.com/tkestack/tke-extend-network-controller/pkg/clb"
+	"github.com/tkestack/tke-extend-network-controller/pkg/clusterinfo"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+)
+
+// DedicatedCLBServiceReconciler reconciles a DedicatedCLBService object
+type DedicatedCLBServiceReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=networking.cloud.tencent.com,resources=dedicatedclbservices,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.cloud.tencent.com,resources=dedicatedclbservices/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=networking.cloud.tencent.com,resources=dedicatedclbservices/finalizers,verbs=update
+// +kubebuilder:rbac:groups=networking.cloud.tencent.com,resources=dedicatedclblisteners,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;update;patch
+
+// Reconcile 对账 DedicatedCLBService：为 selector 选中的每个 Pod 的每个端口从 CLB 上分配一个独立的端口，
+// 并创建 DedicatedCLBListener 来管理对应的监听器。
+func (r *DedicatedCLBServiceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return ReconcileWithFinalizer(ctx, req, r.Client, &networkingv1alpha1.DedicatedCLBService{}, r.sync, r.cleanup)
+}
+
+// DedicatedCLBService 在端口分配器（portpool.DedicatedAllocator）中对应的端口池名称
+func dedicatedPoolName(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// 每个 Pod 的每个端口对应一个 DedicatedCLBListener
+func dedicatedListenerName(podName string, port *networkingv1alpha1.DedicatedCLBServicePort) string {
+	return fmt.Sprintf("%s-%s-%d", podName, strings.ToLower(port.Protocol), port.TargetPort)
+}
+
+var ErrNoDedicatedPortAvailable = errors.New("no available port in clb")
+
+func (r *DedicatedCLBServiceReconciler) sync(ctx context.Context, ds *networkingv1alpha1.DedicatedCLBService) (result ctrl.Result, err error) {
+	poolName := dedicatedPoolName(ds.Namespace, ds.Name)
+	region := getDedicatedRegion(ds.Spec.LbRegion)
+	// 确保端口分配器中的 lb 与 DedicatedCLBService 一致，并根据已有的 DedicatedCLBListener 恢复已分配的端口
+	pp := &networkingv1alpha1.CLBPortPool{}
+	pp.Name = poolName
+	pp.Spec.LbPolicy = util.GetPtr(constant.LbPolicyInOrder)
+	portpool.DedicatedAllocator.EnsurePool(pp)
+	lbKeys := []portpool.LBKey{}
+	for _, lbId := range r.getLbIds(ds) {
+		lbKeys = append(lbKeys, portpool.NewLBKey(lbId, region))
+	}
+	if err := portpool.DedicatedAllocator.EnsureLbIds(poolName, lbKeys); err != nil {
+		return result, errors.WithStack(err)
+	}
+	listeners, err := r.listListeners(ctx, ds)
+	if err != nil {
+		return result, errors.WithStack(err)
+	}
+	existed := make(map[string]*networkingv1alpha1.DedicatedCLBListener)
+	for i := range listeners {
+		lis := &listeners[i]
+		existed[lis.Name] = lis
+		portpool.DedicatedAllocator.MarkAllocated(poolName, portpool.NewLBKey(lis.Spec.LbId, region), uint16(lis.Spec.LbPort), nil, lis.Spec.Protocol)
+	}
+
+	// 获取选中的 Pod
+	podList := &corev1.PodList{}
+	if len(ds.Spec.Selector) > 0 { // 空的 selector 不选中任何 Pod，避免误将命名空间下所有 Pod 都映射出去
+		if err := r.List(ctx, podList, client.InNamespace(ds.Namespace), client.MatchingLabels(ds.Spec.Selector)); err != nil {
+			return result, errors.WithStack(err)
+		}
+	}
+	pods := []*corev1.Pod{}
+	expected := make(map[string]struct{})
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		pods = append(pods, pod)
+		for j := range ds.Spec.Ports {
+			expected[dedicatedListenerName(pod.Name, &ds.Spec.Ports[j])] = struct{}{}
+		}
+	}
+
+	// 先删除不再需要的 DedicatedCLBListener（Pod 被删除或不再被选中），避免端口不足时无法回收，监听器删除后端口由 DedicatedCLBListener 控制器释放
+	for name, lis := range existed {
+		if _, ok := expected[name]; ok || lis.DeletionTimestamp != nil {
+			continue
+		}
+		if err := r.Delete(ctx, lis); err != nil && !apierrors.IsNotFound(err) {
+			return result, errors.WithStack(err)
+		}
+	}
+
+	// 为选中的 Pod 分配端口
+	for _, pod := range pods {
+		for j := range ds.Spec.Ports {
+			port := &ds.Spec.Ports[j]
+			name := dedicatedListenerName(pod.Name, port)
+			if lis, ok := existed[name]; ok {
+				if err := r.ensureAddressAnnotation(ctx, pod, port, lis); err != nil {
+					return result, errors.WithStack(err)
+				}
+				continue
+			}
+			if err := r.allocateListener(ctx, ds, pod, port, name); err != nil {
+				if errors.Cause(err) == ErrNoDedicatedPortAvailable {
+					created, err := r.tryCreateCLB(ctx, ds)
+					if err != nil {
+						return result, errors.WithStack(err)
+					}
+					if created { // 新 CLB 已加入，重新对账分配
+						result.RequeueAfter = time.Second
+						return result, nil
+					}
+					r.Recorder.Event(ds, corev1.EventTypeWarning, "NoPortAvailable", "no available port in clb, add more clb to existedLbIds or enable lbAutoCreate")
+					return result, r.ensureStatus(ctx, ds, listeners)
+				}
+				return result, errors.WithStack(err)
+			}
+		}
+	}
+	return result, r.ensureStatus(ctx, ds, listeners)
+}
+
+// 获取所有可用于分配端口的 CLB：已有的 CLB 和自动创建的 CLB
+func (r *DedicatedCLBServiceReconciler) getLbIds(ds *networkingv1alpha1.DedicatedCLBService) []string {
+	lbIds := []string{}
+	seen := make(map[string]struct{})
+	add := func(lbId string) {
+		if _, ok := seen[lbId]; !ok {
+			seen[lbId] = struct{}{}
+			lbIds = append(lbIds, lbId)
+		}
+	}
+	for _, lbId := range ds.Spec.ExistedLbIds {
+		add(lbId)
+	}
+	for _, lb := range ds.Status.AllocatableLb {
+		if lb.AutoCreate {
+			add(lb.LbId)
+		}
+	}
+	for _, lb := range ds.Status.AllocatedLb {
+		if lb.AutoCreate {
+			add(lb.LbId)
+		}
+	}
+	return lbIds
+}
+
+func (r *DedicatedCLBServiceReconciler) listListeners(ctx context.Context, ds *networkingv1alpha1.DedicatedCLBService) ([]networkingv1alpha1.DedicatedCLBListener, error) {
+	list := &networkingv1alpha1.DedicatedCLBListenerList{}
+	if err := r.List(ctx, list, client.InNamespace(ds.Namespace), client.MatchingLabels{constant.DedicatedCLBServiceLabelKey: ds.Name}); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return list.Items, nil
+}
+
+// 单个 CLB 最多分配的监听器数量
+func (r *DedicatedCLBServiceReconciler) getQuota(ds *networkingv1alpha1.DedicatedCLBService) uint16 {
+	if ds.Spec.MaxPod != nil && *ds.Spec.MaxPod > 0 && *ds.Spec.MaxPod < math.MaxUint16 {
+		return uint16(*ds.Spec.MaxPod)
+	}
+	return math.MaxUint16
+}
+
+// 为 Pod 的端口分配一个 CLB 端口，并创建对应的 DedicatedCLBListener
+func (r *DedicatedCLBServiceReconciler) allocateListener(ctx context.Context, ds *networkingv1alpha1.DedicatedCLBService, pod *corev1.Pod, port *networkingv1alpha1.DedicatedCLBServicePort, name string) error {
+	pool := portpool.DedicatedAllocator.GetPool(dedicatedPoolName(ds.Namespace, ds.Name))
+	if pool == nil {
+		return errors.WithStack(ErrNoDedicatedPortAvailable)
+	}
+	allocated, _ := pool.AllocatePortFromRange(ctx, uint16(ds.Spec.MinPort), uint16(ds.Spec.MaxPort), r.getQuota(ds), 1, port.Protocol)
+	if len(allocated) == 0 {
+		return errors.WithStack(ErrNoDedicatedPortAvailable)
+	}
+	pa := allocated[0]
+	lis := &networkingv1alpha1.DedicatedCLBListener{}
+	lis.Namespace = ds.Namespace
+	lis.Name = name
+	lis.Labels = map[string]string{
+		constant.DedicatedCLBServiceLabelKey: ds.Name,
+	}
+	lis.Spec = networkingv1alpha1.DedicatedCLBListenerSpec{
+		LbId:                pa.LbId,
+		LbRegion:            ds.Spec.LbRegion,
+		LbPort:              int64(pa.Port),
+		Protocol:            port.Protocol,
+		ExtensiveParameters: ds.Spec.ListenerExtensiveParameters,
+		TargetPod: &networkingv1alpha1.TargetPod{
+			PodName:    pod.Name,
+			TargetPort: port.TargetPort,
+		},
+	}
+	if err := controllerutil.SetControllerReference(ds, lis, r.Scheme); err != nil {
+		pa.Release()
+		return errors.WithStack(err)
+	}
+	if err := r.Create(ctx, lis); err != nil {
+		pa.Release()
+		return errors.WithStack(err)
+	}
+	r.Recorder.Eventf(ds, corev1.EventTypeNormal, "PortAllocated", "allocate %s:%d/%s for pod %s", pa.LbId, pa.Port, port.Protocol, pod.Name)
+	return nil
+}
+
+// 端口不足时，如果启用了自动创建，创建一个新的 CLB
+func (r *DedicatedCLBServiceReconciler) tryCreateCLB(ctx context.Context, ds *networkingv1alpha1.DedicatedCLBService) (bool, error) {
+	if !ds.Spec.LbAutoCreate.Enable {
+		return false, nil
+	}
+	req := clbsdk.NewCreateLoadBalancerRequest()
+	if params := ds.Spec.LbAutoCreate.ExtensiveParameters; params != "" {
+		if err := req.FromJsonString(params); err != nil {
+			return false, errors.Wrap(err, "invalid lbAutoCreate.extensiveParameters")
+		}
+	}
+	if req.LoadBalancerType == nil {
+		req.LoadBalancerType = util.GetPtr("OPEN") // 默认使用公网 CLB
+	}
+	if req.VpcId == nil {
+		vpcId := ds.Spec.VpcId
+		if vpcId == "" {
+			vpcId = clusterinfo.VpcId
+		}
+		req.VpcId = &vpcId
+	}
+	req.Tags = append(req.Tags,
+		&clbsdk.TagInfo{TagKey: util.GetPtr(constant.TkeClusterIDTagKey), TagValue: util.GetPtr(clusterinfo.ClusterId)},
+		&clbsdk.TagInfo{TagKey: util.GetPtr(constant.TkeCreatedFlagTagKey), TagValue: util.GetPtr(constant.TkeCreatedFlagYesValue)},
+	)
+	r.Recorder.Event(ds, corev1.EventTypeNormal, "CreateLoadBalancer", "try to create clb")
+	lbId, err := clb.CreateCLB(ctx, getDedicatedRegion(ds.Spec.LbRegion), req)
+	if err != nil {
+		r.Recorder.Eventf(ds, corev1.EventTypeWarning, "CreateLoadBalancer", "create clb failed: %s", err.Error())
+		return false, errors.WithStack(err)
+	}
+	r.Recorder.Eventf(ds, corev1.EventTypeNormal, "CreateLoadBalancer", "create clb success: %s", lbId)
+	// 立即记录自动创建的 CLB，避免泄露（冲突时重新获取最新的对象再记录，不能丢失 lbId）
+	if err := util.RetryIfPossible(func() error {
+		if err := r.Get(ctx, client.ObjectKeyFromObject(ds), ds); err != nil {
+			return err
+		}
+		for _, lb := range ds.Status.AllocatableLb {
+			if lb.LbId == lbId {
+				return nil
+			}
+		}
+		ds.Status.AllocatableLb = append(ds.Status.AllocatableLb, networkingv1alpha1.AllocatableCLBInfo{
+			LbId:       lbId,
+			AutoCreate: true,
+		})
+		return r.Status().Update(ctx, ds)
+	}); err != nil {
+		return false, errors.WithStack(err)
+	}
+	return true, nil
+}
+
+// 将 CLB 外部地址写入 Pod 的注解
+func (r *DedicatedCLBServiceReconciler) ensureAddressAnnotation(ctx context.Context, pod *corev1.Pod, port *networkingv1alpha1.DedicatedCLBServicePort, lis *networkingv1alpha1.DedicatedCLBListener) error {
+	if port.AddressPodAnnotation == "" || lis.Status.Address == "" || pod.Annotations[port.AddressPodAnnotation] == lis.Status.Address {
+		return nil
+	}
+	patch := client.MergeFrom(pod.DeepCopy())
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[port.AddressPodAnnotation] = lis.Status.Address
+	if err := r.Patch(ctx, pod, patch); err != nil {
+		return errors.WithStack(client.IgnoreNotFound(err))
+	}
+	return nil
+}
+
+// 根据已分配的端口更新 CLB 列表状态：监听器数量达到上限或端口范围已用完的 CLB 视为已分配完
+func (r *DedicatedCLBServiceReconciler) ensureStatus(ctx context.Context, ds *networkingv1alpha1.DedicatedCLBService, listeners []networkingv1alpha1.DedicatedCLBListener) error {
+	autoCreated := make(map[string]bool)
+	for _, lb := range ds.Status.AllocatableLb {
+		autoCreated[lb.LbId] = lb.AutoCreate
+	}
+	for _, lb := range ds.Status.AllocatedLb {
+		autoCreated[lb.LbId] = lb.AutoCreate
+	}
+	currentPort := make(map[string]int64)
+	for _, lis := range listeners {
+		currentPort[lis.Spec.LbId] = max(currentPort[lis.Spec.LbId], lis.Spec.LbPort)
+	}
+	region := getDedicatedRegion(ds.Spec.LbRegion)
+	limit := r.getQuota(ds)
+	if portNum := ds.Spec.MaxPort - ds.Spec.MinPort + 1; portNum < int64(limit) {
+		limit = uint16(max(portNum, 0))
+	}
+	status := networkingv1alpha1.DedicatedCLBServiceStatus{}
+	for _, lbId := range r.getLbIds(ds) {
+		allocated := portpool.DedicatedAllocator.AllocatedPorts(dedicatedPoolName(ds.Namespace, ds.Name), portpool.NewLBKey(lbId, region))
+		if allocated >= limit {
+			status.AllocatedLb = append(status.AllocatedLb, networkingv1alpha1.AllocatedCLBInfo{LbId: lbId, AutoCreate: autoCreated[lbId]})
+		} else {
+			status.AllocatableLb = append(status.AllocatableLb, networkingv1alpha1.AllocatableCLBInfo{LbId: lbId, AutoCreate: autoCreated[lbId], CurrentPort: currentPort[lbId]})
+		}
+	}
+	if equalDedicatedStatus(status, ds.Status) {
+		return nil
+	}
+	ds.Status = status
+	if err := r.Status().Update(ctx, ds); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func equalDedicatedStatus(a, b networkingv1alpha1.DedicatedCLBServiceStatus) bool {
+	if len(a.AllocatableLb) != len(b.AllocatableLb) || len(a.AllocatedLb) != len(b.AllocatedLb) {
+		return false
+	}
+	for i := range a.AllocatableLb {
+		if a.AllocatableLb[i] != b.AllocatableLb[i] {
+			return false
+		}
+	}
+	for i := range a.AllocatedLb {
+		if a.AllocatedLb[i] != b.AllocatedLb[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// 删除所有 DedicatedCLBListener，等监听器都清理完后再删除自动创建的 CLB
+func (r *DedicatedCLBServiceReconciler) cleanup(ctx context.Context, ds *networkingv1alpha1.DedicatedCLBService) (result ctrl.Result, err error) {
+	listeners, err := r.listListeners(ctx, ds)
+	if err != nil {
+		return result, errors.WithStack(err)
+	}
+	if len(listeners) > 0 {
+		for i := range listeners {
+			lis := &listeners[i]
+			if lis.DeletionTimestamp != nil {
+				continue
+			}
+			if err := r.Delete(ctx, lis); err != nil && !apierrors.IsNotFound(err) {
+				return result, errors.WithStack(err)
+			}
+		}
+		log.FromContext(ctx).V(3).Info("wait dedicatedclblisteners to be deleted", "remaining", len(listeners))
+		result.RequeueAfter = 3 * time.Second
+		return result, nil
+	}
+	lbIds := []string{}
+	for _, lb := range ds.Status.AllocatableLb {
+		if lb.AutoCreate {
+			lbIds = append(lbIds, lb.LbId)
+		}
+	}
+	for _, lb := range ds.Status.AllocatedLb {
+		if lb.AutoCreate {
+			lbIds = append(lbIds, lb.LbId)
+		}
+	}
+	if len(lbIds) > 0 {
+		r.Recorder.Eventf(ds, corev1.EventTypeNormal, "DeleteLoadBalancer", "delete auto-created clb %v", lbIds)
+		if err := clb.Delete(ctx, getDedicatedRegion(ds.Spec.LbRegion), lbIds...); err != nil {
+			return result, errors.WithStack(err)
+		}
+	}
+	portpool.DedicatedAllocator.RemovePool(dedicatedPoolName(ds.Namespace, ds.Name))
+	return result, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DedicatedCLBServiceReconciler) SetupWithManager(mgr ctrl.Manager, workers int) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&networkingv1alpha1.DedicatedCLBService{}).
+		Owns(&networkingv1alpha1.DedicatedCLBListener{}).
+		Watches(
+			&corev1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForPod),
+		).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: workers,
+		}).
+		Named("dedicatedclbservice").
+		Complete(r)
+}
+
+func (r *DedicatedCLBServiceReconciler) findObjectsForPod(ctx context.Context, pod client.Object) []reconcile.Request {
+	list := &networkingv1alpha1.DedicatedCLBServiceList{}
+	if err := r.List(ctx, list, client.InNamespace(pod.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list DedicatedCLBService")
+		return []reconcile.Request{}
+	}
+	requests := []reconcile.Request{}
+	for _, ds := range list.Items {
+		if len(ds.Spec.Selector) == 0 || !labels.SelectorFromSet(ds.Spec.Selector).Matches(labels.Set(pod.GetLabels())) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: ds.Namespace,
+				Name:      ds.Name,
+			},
+		})
+	}
+	return requests
+}
@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/internal/portpool"
+)
+
+func newTestDedicatedCLBService() *networkingv1alpha1.DedicatedCLBService {
+	return &networkingv1alpha1.DedicatedCLBService{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gs", UID: "ds-uid"},
+		Spec: networkingv1alpha1.DedicatedCLBServiceSpec{
+			LbRegion:     "ap-guangzhou",
+			MinPort:      30000,
+			MaxPort:      30001,
+			Selector:     map[string]string{"app": "gs"},
+			Ports:        []networkingv1alpha1.DedicatedCLBServicePort{{Protocol: "UDP", TargetPort: 7777}},
+			ExistedLbIds: []string{"lb-1"},
+		},
+	}
+}
+
+func listDedicatedListenerPorts(t *testing.T, c client.Client) map[string]int64 {
+	t.Helper()
+	list := &networkingv1alpha1.DedicatedCLBListenerList{}
+	if err := c.List(context.Background(), list); err != nil {
+		t.Fatal(err)
+	}
+	ports := make(map[string]int64)
+	for _, lis := range list.Items {
+		ports[lis.Name] = lis.Spec.LbPort
+	}
+	return ports
+}
+
+func TestDedicatedCLBServiceAllocate(t *testing.T) {
+	ctx := context.Background()
+	ds := newTestDedicatedCLBService()
+	labels := map[string]string{"app": "gs"}
+	c := newFakeClient(t, ds,
+		newTestPod("gs-0", labels, "10.0.0.1"),
+		newTestPod("gs-1", labels, "10.0.0.2"),
+		newTestPod("gs-2", labels, "10.0.0.3"),
+		newTestPod("other", map[string]string{"app": "other"}, "10.0.0.4"),
+	)
+	recorder := record.NewFakeRecorder(100)
+	r := &DedicatedCLBServiceReconciler{Client: c, Scheme: c.Scheme(), Recorder: recorder}
+	defer portpool.DedicatedAllocator.RemovePool(dedicatedPoolName(ds.Namespace, ds.Name))
+
+	if _, err := r.sync(ctx, ds); err != nil {
+		t.Fatal(err)
+	}
+	// 端口范围只有 2 个端口，只能为 2 个 Pod 分配，且端口不能重复
+	ports := listDedicatedListenerPorts(t, c)
+	if len(ports) != 2 {
+		t.Fatalf("expect 2 listeners allocated, got %v", ports)
+	}
+	seen := make(map[int64]bool)
+	for name, port := range ports {
+		if port < 30000 || port > 30001 || seen[port] {
+			t.Errorf("unexpected port %d of listener %s", port, name)
+		}
+		seen[port] = true
+	}
+	if _, ok := ports[dedicatedListenerName("other", &ds.Spec.Ports[0])]; ok {
+		t.Error("pod not selected should not be allocated")
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(ds), ds); err != nil {
+		t.Fatal(err)
+	}
+	if len(ds.Status.AllocatedLb) != 1 || ds.Status.AllocatedLb[0].LbId != "lb-1" {
+		t.Errorf("expect lb-1 fully allocated, got %+v", ds.Status)
+	}
+
+	// Pod 删除后释放其监听器，空出的端口可以分配给剩下的 Pod
+	var released string
+	for name := range ports {
+		released = name
+		break
+	}
+	pod := &corev1.Pod{}
+	podName := released[:len("gs-0")]
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: podName}, pod); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Delete(ctx, pod); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.sync(ctx, ds); err != nil {
+		t.Fatal(err)
+	}
+	ports = listDedicatedListenerPorts(t, c)
+	if _, ok := ports[released]; ok {
+		t.Errorf("listener %s of deleted pod should be deleted", released)
+	}
+	// 模拟 DedicatedCLBListener 控制器清理监听器后释放端口
+	portpool.DedicatedAllocator.Release(dedicatedPoolName(ds.Namespace, ds.Name), portpool.NewLBKey("lb-1", "ap-guangzhou"), portpool.ProtocolPort{Port: 30000, Protocol: "UDP"})
+	portpool.DedicatedAllocator.Release(dedicatedPoolName(ds.Namespace, ds.Name), portpool.NewLBKey("lb-1", "ap-guangzhou"), portpool.ProtocolPort{Port: 30001, Protocol: "UDP"})
+	if _, err := r.sync(ctx, ds); err != nil {
+		t.Fatal(err)
+	}
+	ports = listDedicatedListenerPorts(t, c)
+	if len(ports) != 2 {
+		t.Errorf("expect remaining 2 pods allocated, got %v", ports)
+	}
+}
+
+func TestDedicatedCLBServiceNotPolluteAllocator(t *testing.T) {
+	ds := newTestDedicatedCLBService()
+	c := newFakeClient(t, ds)
+	r := &DedicatedCLBServiceReconciler{Client: c, Scheme: c.Scheme(), Recorder: record.NewFakeRecorder(100)}
+	defer portpool.DedicatedAllocator.RemovePool(dedicatedPoolName(ds.Namespace, ds.Name))
+	if _, err := r.sync(context.Background(), ds); err != nil {
+		t.Fatal(err)
+	}
+	if portpool.DedicatedAllocator.GetPool(dedicatedPoolName(ds.Namespace, ds.Name)) == nil {
+		t.Error("expect pool registered in dedicated allocator")
+	}
+	if portpool.Allocator.GetPool(dedicatedPoolName(ds.Namespace, ds.Name)) != nil {
+		t.Error("dedicated pool should not be registered in port pool allocator")
+	}
+}
+
+func TestDedicatedCLBServiceCleanup(t *testing.T) {
+	ctx := context.Background()
+	ds := newTestDedicatedCLBService()
+	lis := &networkingv1alpha1.DedicatedCLBListener{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "gs-0-udp-7777",
+			Labels:    map[string]string{constant.DedicatedCLBServiceLabelKey: ds.Name},
+		},
+		Spec: networkingv1alpha1.DedicatedCLBListenerSpec{LbId: "lb-1", LbPort: 30000, Protocol: "UDP"},
+	}
+	c := newFakeClient(t, ds, lis)
+	r := &DedicatedCLBServiceReconciler{Client: c, Scheme: c.Scheme(), Recorder: record.NewFakeRecorder(100)}
+	portpool.DedicatedAllocator.EnsurePool(&networkingv1alpha1.CLBPortPool{ObjectMeta: metav1.ObjectMeta{Name: dedicatedPoolName(ds.Namespace, ds.Name)}})
+
+	// 先删除所有 DedicatedCLBListener，等待清理完成
+	result, err := r.cleanup(ctx, ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.RequeueAfter == 0 {
+		t.Error("expect requeue to wait listeners deleted")
+	}
+	if ports := listDedicatedListenerPorts(t, c); len(ports) != 0 {
+		t.Errorf("expect listeners deleted, got %v", ports)
+	}
+	// 监听器都清理完后移除端口池
+	if _, err := r.cleanup(ctx, ds); err != nil {
+		t.Fatal(err)
+	}
+	if portpool.DedicatedAllocator.GetPool(dedicatedPoolName(ds.Namespace, ds.Name)) != nil {
+		t.Error("expect dedicated pool removed")
+	}
+}
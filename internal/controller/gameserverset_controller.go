@@ -22,6 +22,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	gamekruiseiov1alpha1 "github.com/openkruise/kruise-game/apis/v1alpha1"
 	"github.com/pkg/errors"
@@ -32,6 +33,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 // GameServerSetReconciler reconciles a GameServerSet object
@@ -63,6 +65,10 @@ func (r *GameServerSetReconciler) cleanup(ctx context.Context, gss *gamekruiseio
 	}
 	// 还未删除，发起删除请求
 	if err := r.Delete(ctx, pp); err != nil {
+		if apierrors.IsForbidden(err) { // 端口池还在被 CLBBinding 使用（Pod 还未删除完），等待后重试
+			log.FromContext(ctx).Info("port pool is still in use, retry later", "pool", ppName, "reason", err.Error())
+			return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+		}
 		return ctrl.Result{}, errors.WithStack(err)
 	}
 	return ctrl.Result{}, nil
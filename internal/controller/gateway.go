@@ -0,0 +1,282 @@
+package controller
+
+import (
+	"context"
+	"slices"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/pkg/clusterinfo"
+)
+
+// gatewayRoute 抽象 TCPRoute/UDPRoute/TLSRoute 的公共部分
+type gatewayRoute interface {
+	client.Object
+	GetParentRefs() []gatewayv1.ParentReference
+	GetBackendRefs() []gatewayv1.BackendRef
+	GetRouteStatus() *gatewayv1.RouteStatus
+	// 返回原始的路由对象，调用 API 和记录 event 时需使用原始对象
+	GetObject() client.Object
+}
+
+type tcpRoute struct {
+	*gatewayv1.TCPRoute
+}
+
+func (r tcpRoute) GetParentRefs() []gatewayv1.ParentReference {
+	return r.Spec.ParentRefs
+}
+
+func (r tcpRoute) GetBackendRefs() []gatewayv1.BackendRef {
+	refs := []gatewayv1.BackendRef{}
+	for _, rule := range r.Spec.Rules {
+		refs = append(refs, rule.BackendRefs...)
+	}
+	return refs
+}
+
+func (r tcpRoute) GetRouteStatus() *gatewayv1.RouteStatus {
+	return &r.Status.RouteStatus
+}
+
+func (r tcpRoute) GetObject() client.Object {
+	return r.TCPRoute
+}
+
+type udpRoute struct {
+	*gatewayv1.UDPRoute
+}
+
+func (r udpRoute) GetParentRefs() []gatewayv1.ParentReference {
+	return r.Spec.ParentRefs
+}
+
+func (r udpRoute) GetBackendRefs() []gatewayv1.BackendRef {
+	refs := []gatewayv1.BackendRef{}
+	for _, rule := range r.Spec.Rules {
+		refs = append(refs, rule.BackendRefs...)
+	}
+	return refs
+}
+
+func (r udpRoute) GetRouteStatus() *gatewayv1.RouteStatus {
+	return &r.Status.RouteStatus
+}
+
+func (r udpRoute) GetObject() client.Object {
+	return r.UDPRoute
+}
+
+type tlsRoute struct {
+	*gatewayv1.TLSRoute
+}
+
+func (r tlsRoute) GetParentRefs() []gatewayv1.ParentReference {
+	return r.Spec.ParentRefs
+}
+
+func (r tlsRoute) GetBackendRefs() []gatewayv1.BackendRef {
+	refs := []gatewayv1.BackendRef{}
+	for _, rule := range r.Spec.Rules {
+		refs = append(refs, rule.BackendRefs...)
+	}
+	return refs
+}
+
+func (r tlsRoute) GetRouteStatus() *gatewayv1.RouteStatus {
+	return &r.Status.RouteStatus
+}
+
+func (r tlsRoute) GetObject() client.Object {
+	return r.TLSRoute
+}
+
+// gatewayRouteKind 描述一种路由类型：对应的 Gateway 监听器协议、CLB 监听器协议，以及对象的构造方法
+type gatewayRouteKind struct {
+	Kind             string
+	ListenerProtocol gatewayv1.ProtocolType
+	CLBProtocol      string
+	NewObject        func() client.Object
+	NewList          func() client.ObjectList
+	Wrap             func(client.Object) gatewayRoute
+	Items            func(client.ObjectList) []gatewayRoute
+}
+
+// TLSRoute 仅支持 Passthrough 模式，CLB 上使用 TCP 监听器透传
+var gatewayRouteKinds = map[string]*gatewayRouteKind{
+	"TCPRoute": {
+		Kind:             "TCPRoute",
+		ListenerProtocol: gatewayv1.TCPProtocolType,
+		CLBProtocol:      constant.ProtocolTCP,
+		NewObject:        func() client.Object { return &gatewayv1.TCPRoute{} },
+		NewList:          func() client.ObjectList { return &gatewayv1.TCPRouteList{} },
+		Wrap:             func(obj client.Object) gatewayRoute { return tcpRoute{obj.(*gatewayv1.TCPRoute)} },
+		Items: func(list client.ObjectList) []gatewayRoute {
+			items := list.(*gatewayv1.TCPRouteList).Items
+			routes := make([]gatewayRoute, len(items))
+			for i := range items {
+				routes[i] = tcpRoute{&items[i]}
+			}
+			return routes
+		},
+	},
+	"UDPRoute": {
+		Kind:             "UDPRoute",
+		ListenerProtocol: gatewayv1.UDPProtocolType,
+		CLBProtocol:      constant.ProtocolUDP,
+		NewObject:        func() client.Object { return &gatewayv1.UDPRoute{} },
+		NewList:          func() client.ObjectList { return &gatewayv1.UDPRouteList{} },
+		Wrap:             func(obj client.Object) gatewayRoute { return udpRoute{obj.(*gatewayv1.UDPRoute)} },
+		Items: func(list client.ObjectList) []gatewayRoute {
+			items := list.(*gatewayv1.UDPRouteList).Items
+			routes := make([]gatewayRoute, len(items))
+			for i := range items {
+				routes[i] = udpRoute{&items[i]}
+			}
+			return routes
+		},
+	},
+	"TLSRoute": {
+		Kind:             "TLSRoute",
+		ListenerProtocol: gatewayv1.TLSProtocolType,
+		CLBProtocol:      constant.ProtocolTCP,
+		NewObject:        func() client.Object { return &gatewayv1.TLSRoute{} },
+		NewList:          func() client.ObjectList { return &gatewayv1.TLSRouteList{} },
+		Wrap:             func(obj client.Object) gatewayRoute { return tlsRoute{obj.(*gatewayv1.TLSRoute)} },
+		Items: func(list client.ObjectList) []gatewayRoute {
+			items := list.(*gatewayv1.TLSRouteList).Items
+			routes := make([]gatewayRoute, len(items))
+			for i := range items {
+				routes[i] = tlsRoute{&items[i]}
+			}
+			return routes
+		},
+	},
+}
+
+// 获取集群中已安装的路由类型
+func getSupportedGatewayRouteKinds() []*gatewayRouteKind {
+	kinds := []*gatewayRouteKind{}
+	for _, kind := range clusterinfo.GatewayRouteKinds {
+		if k, ok := gatewayRouteKinds[kind]; ok {
+			kinds = append(kinds, k)
+		}
+	}
+	return kinds
+}
+
+// 判断 GatewayClass 是否由本控制器管理
+func isManagedGatewayClass(ctx context.Context, c client.Client, name gatewayv1.ObjectName) (bool, error) {
+	gc := &gatewayv1.GatewayClass{}
+	if err := c.Get(ctx, client.ObjectKey{Name: string(name)}, gc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.WithStack(err)
+	}
+	return gc.Spec.ControllerName == constant.GatewayControllerName, nil
+}
+
+// 获取 Gateway 引用的端口池，通过 addresses 中类型为 networking.cloud.tencent.com/CLBPortPool 的地址指定
+func getGatewayPools(gw *gatewayv1.Gateway) []string {
+	pools := []string{}
+	for _, addr := range gw.Spec.Addresses {
+		if addr.Type != nil && *addr.Type == constant.CLBPortPoolAddressType && !slices.Contains(pools, addr.Value) {
+			pools = append(pools, addr.Value)
+		}
+	}
+	return pools
+}
+
+// 获取监听器支持的路由类型，不支持的监听器返回 nil 和原因
+func getListenerRouteKind(listener *gatewayv1.Listener) (*gatewayRouteKind, gatewayv1.ListenerConditionReason, string) {
+	if listener.Protocol == gatewayv1.TLSProtocolType && listener.TLS != nil && listener.TLS.Mode != nil && *listener.TLS.Mode != gatewayv1.TLSModePassthrough {
+		return nil, gatewayv1.ListenerReasonUnsupportedValue, "only Passthrough mode is supported for TLS listener"
+	}
+	for _, kind := range getSupportedGatewayRouteKinds() {
+		if kind.ListenerProtocol != listener.Protocol {
+			continue
+		}
+		if ak := listener.AllowedRoutes; ak != nil && len(ak.Kinds) > 0 && !slices.ContainsFunc(ak.Kinds, func(k gatewayv1.RouteGroupKind) bool {
+			return isGatewayGroup(k.Group) && string(k.Kind) == kind.Kind
+		}) {
+			return nil, gatewayv1.ListenerReasonInvalidRouteKinds, "allowedRoutes.kinds does not contain " + kind.Kind
+		}
+		return kind, "", ""
+	}
+	return nil, gatewayv1.ListenerReasonUnsupportedProtocol, "unsupported protocol " + string(listener.Protocol)
+}
+
+func isGatewayGroup(group *gatewayv1.Group) bool {
+	return group == nil || *group == gatewayv1.GroupName
+}
+
+// 判断 parentRef 是否引用了该 Gateway
+func isParentRefToGateway(ref *gatewayv1.ParentReference, routeNamespace string, gw *gatewayv1.Gateway) bool {
+	if !isGatewayGroup(ref.Group) || (ref.Kind != nil && *ref.Kind != "Gateway") {
+		return false
+	}
+	namespace := routeNamespace
+	if ref.Namespace != nil {
+		namespace = string(*ref.Namespace)
+	}
+	return namespace == gw.Namespace && string(ref.Name) == gw.Name
+}
+
+// 判断路由能否挂载到监听器上：监听器协议支持该路由类型，且路由所在命名空间被允许
+func isRouteAllowedByListener(ctx context.Context, c client.Client, gw *gatewayv1.Gateway, listener *gatewayv1.Listener, kind *gatewayRouteKind, routeNamespace string) (bool, error) {
+	if k, _, _ := getListenerRouteKind(listener); k != kind {
+		return false, nil
+	}
+	from := gatewayv1.NamespacesFromSame
+	var selector *metav1.LabelSelector
+	if ar := listener.AllowedRoutes; ar != nil && ar.Namespaces != nil {
+		if ar.Namespaces.From != nil {
+			from = *ar.Namespaces.From
+		}
+		selector = ar.Namespaces.Selector
+	}
+	switch from {
+	case gatewayv1.NamespacesFromAll:
+		return true, nil
+	case gatewayv1.NamespacesFromSame:
+		return routeNamespace == gw.Namespace, nil
+	case gatewayv1.NamespacesFromSelector:
+		if selector == nil {
+			return false, nil
+		}
+		sel, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			return false, nil
+		}
+		ns := &corev1.Namespace{}
+		if err := c.Get(ctx, client.ObjectKey{Name: routeNamespace}, ns); err != nil {
+			return false, errors.WithStack(client.IgnoreNotFound(err))
+		}
+		return sel.Matches(labels.Set(ns.Labels)), nil
+	}
+	return false, nil
+}
+
+// 获取 parentRef 通过 sectionName 和 port 选中的 Gateway 监听器
+func getParentRefListeners(ref *gatewayv1.ParentReference, gw *gatewayv1.Gateway) []*gatewayv1.Listener {
+	listeners := []*gatewayv1.Listener{}
+	for i := range gw.Spec.Listeners {
+		listener := &gw.Spec.Listeners[i]
+		if ref.SectionName != nil && *ref.SectionName != listener.Name {
+			continue
+		}
+		if ref.Port != nil && *ref.Port != listener.Port {
+			continue
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners
+}
@@ -0,0 +1,309 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+)
+
+// GatewayReconciler reconciles a Gateway object
+type GatewayReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+
+// Reconcile 对账本控制器管理的 Gateway：校验引用的端口池和监听器，并将端口池中 CLB 的地址、
+// 各监听器挂载的路由数量写入 status。端口的分配由路由控制器完成。
+func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	gw := &gatewayv1.Gateway{}
+	if err := r.Get(ctx, req.NamespacedName, gw); err != nil {
+		return result, errors.WithStack(client.IgnoreNotFound(err))
+	}
+	if gw.DeletionTimestamp != nil {
+		return result, nil
+	}
+	if managed, err := isManagedGatewayClass(ctx, r.Client, gw.Spec.GatewayClassName); err != nil {
+		return result, errors.WithStack(err)
+	} else if !managed {
+		return result, nil
+	}
+	return result, r.sync(ctx, gw)
+}
+
+func (r *GatewayReconciler) sync(ctx context.Context, gw *gatewayv1.Gateway) error {
+	status := gw.Status.DeepCopy()
+	setCondition := func(conditionType, reason string, ok bool, message string) {
+		cond := metav1.Condition{
+			Type:               conditionType,
+			Status:             metav1.ConditionTrue,
+			Reason:             reason,
+			Message:            message,
+			ObservedGeneration: gw.Generation,
+		}
+		if !ok {
+			cond.Status = metav1.ConditionFalse
+		}
+		meta.SetStatusCondition(&status.Conditions, cond)
+	}
+
+	// 校验 addresses：只支持端口池类型的地址
+	pools := getGatewayPools(gw)
+	unsupported := []string{}
+	for _, addr := range gw.Spec.Addresses {
+		if addr.Type == nil || *addr.Type != constant.CLBPortPoolAddressType {
+			unsupported = append(unsupported, addr.Value)
+		}
+	}
+	switch {
+	case len(unsupported) > 0:
+		setCondition(string(gatewayv1.GatewayConditionAccepted), string(gatewayv1.GatewayReasonUnsupportedAddress), false,
+			fmt.Sprintf("only address type %s is supported, unsupported addresses: %s", constant.CLBPortPoolAddressType, strings.Join(unsupported, ",")))
+	case len(pools) == 0:
+		setCondition(string(gatewayv1.GatewayConditionAccepted), string(gatewayv1.GatewayReasonInvalidParameters), false,
+			fmt.Sprintf("at least one address of type %s is required", constant.CLBPortPoolAddressType))
+	default:
+		setCondition(string(gatewayv1.GatewayConditionAccepted), string(gatewayv1.GatewayReasonAccepted), true, "")
+	}
+
+	// 端口池中 CLB 的地址作为 Gateway 的地址
+	addresses, missingPools, err := r.getPoolAddresses(ctx, pools)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	status.Addresses = addresses
+	switch {
+	case len(missingPools) > 0:
+		setCondition(string(gatewayv1.GatewayConditionProgrammed), string(gatewayv1.GatewayReasonAddressNotUsable), false,
+			fmt.Sprintf("port pool not found: %s", strings.Join(missingPools, ",")))
+	case len(addresses) == 0:
+		setCondition(string(gatewayv1.GatewayConditionProgrammed), string(gatewayv1.GatewayReasonAddressNotAssigned), false,
+			"no loadbalancer is available in port pools yet")
+	default:
+		setCondition(string(gatewayv1.GatewayConditionProgrammed), string(gatewayv1.GatewayReasonProgrammed), true, "")
+	}
+
+	// 监听器状态
+	listeners := []gatewayv1.ListenerStatus{}
+	for i := range gw.Spec.Listeners {
+		listener := &gw.Spec.Listeners[i]
+		ls := gatewayv1.ListenerStatus{Name: listener.Name}
+		for _, old := range gw.Status.Listeners {
+			if old.Name == listener.Name {
+				ls.Conditions = slices.Clone(old.Conditions)
+				break
+			}
+		}
+		setListenerCondition := func(conditionType gatewayv1.ListenerConditionType, reason gatewayv1.ListenerConditionReason, ok bool, message string) {
+			cond := metav1.Condition{
+				Type:               string(conditionType),
+				Status:             metav1.ConditionTrue,
+				Reason:             string(reason),
+				Message:            message,
+				ObservedGeneration: gw.Generation,
+			}
+			if !ok {
+				cond.Status = metav1.ConditionFalse
+			}
+			meta.SetStatusCondition(&ls.Conditions, cond)
+		}
+		kind, reason, message := getListenerRouteKind(listener)
+		if kind == nil {
+			ls.SupportedKinds = []gatewayv1.RouteGroupKind{}
+			if reason == gatewayv1.ListenerReasonInvalidRouteKinds {
+				setListenerCondition(gatewayv1.ListenerConditionResolvedRefs, reason, false, message)
+				setListenerCondition(gatewayv1.ListenerConditionAccepted, gatewayv1.ListenerReasonAccepted, true, "")
+			} else {
+				setListenerCondition(gatewayv1.ListenerConditionAccepted, reason, false, message)
+				setListenerCondition(gatewayv1.ListenerConditionResolvedRefs, gatewayv1.ListenerReasonResolvedRefs, true, "")
+			}
+			setListenerCondition(gatewayv1.ListenerConditionProgrammed, gatewayv1.ListenerReasonInvalid, false, message)
+		} else {
+			ls.SupportedKinds = []gatewayv1.RouteGroupKind{{Group: util.GetPtr(gatewayv1.Group(gatewayv1.GroupName)), Kind: gatewayv1.Kind(kind.Kind)}}
+			attached, err := r.countAttachedRoutes(ctx, gw, listener, kind)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			ls.AttachedRoutes = attached
+			setListenerCondition(gatewayv1.ListenerConditionAccepted, gatewayv1.ListenerReasonAccepted, true, "")
+			setListenerCondition(gatewayv1.ListenerConditionResolvedRefs, gatewayv1.ListenerReasonResolvedRefs, true, "")
+			setListenerCondition(gatewayv1.ListenerConditionProgrammed, gatewayv1.ListenerReasonProgrammed, true, "")
+		}
+		listeners = append(listeners, ls)
+	}
+	status.Listeners = listeners
+
+	if !reflect.DeepEqual(*status, gw.Status) {
+		gw.Status = *status
+		if err := r.Status().Update(ctx, gw); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// 获取端口池中所有 CLB 的地址，同时返回不存在的端口池
+func (r *GatewayReconciler) getPoolAddresses(ctx context.Context, pools []string) (addresses []gatewayv1.GatewayStatusAddress, missingPools []string, err error) {
+	addresses = []gatewayv1.GatewayStatusAddress{}
+	for _, pool := range pools {
+		pp := &networkingv1alpha1.CLBPortPool{}
+		if err := r.Get(ctx, client.ObjectKey{Name: pool}, pp); err != nil {
+			if apierrors.IsNotFound(err) {
+				missingPools = append(missingPools, pool)
+				continue
+			}
+			return nil, nil, errors.WithStack(err)
+		}
+		for _, lbStatus := range pp.Status.LoadbalancerStatuses {
+			if lbStatus.State != networkingv1alpha1.LoadBalancerStateRunning {
+				continue
+			}
+			addr := gatewayv1.GatewayStatusAddress{Type: util.GetPtr(gatewayv1.IPAddressType)}
+			if hostname := util.GetValue(lbStatus.Hostname); hostname != "" {
+				addr.Type = util.GetPtr(gatewayv1.HostnameAddressType)
+				addr.Value = hostname
+			} else if len(lbStatus.Ips) > 0 {
+				addr.Value = lbStatus.Ips[0]
+			} else {
+				continue
+			}
+			if !slices.Contains(addresses, addr) {
+				addresses = append(addresses, addr)
+			}
+		}
+	}
+	return addresses, missingPools, nil
+}
+
+// 统计挂载到监听器上的路由数量
+func (r *GatewayReconciler) countAttachedRoutes(ctx context.Context, gw *gatewayv1.Gateway, listener *gatewayv1.Listener, kind *gatewayRouteKind) (int32, error) {
+	list := kind.NewList()
+	if err := r.List(ctx, list); err != nil {
+		return 0, errors.WithStack(err)
+	}
+	count := int32(0)
+	for _, route := range kind.Items(list) {
+		if route.GetDeletionTimestamp() != nil {
+			continue
+		}
+		for _, ref := range route.GetParentRefs() {
+			if !isParentRefToGateway(&ref, route.GetNamespace(), gw) {
+				continue
+			}
+			if !slices.Contains(getParentRefListeners(&ref, gw), listener) {
+				continue
+			}
+			allowed, err := isRouteAllowedByListener(ctx, r.Client, gw, listener, kind, route.GetNamespace())
+			if err != nil {
+				return 0, errors.WithStack(err)
+			}
+			if allowed {
+				count++
+				break
+			}
+		}
+	}
+	return count, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GatewayReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&gatewayv1.Gateway{}).
+		Watches(
+			&gatewayv1.GatewayClass{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForGatewayClass),
+		).
+		Watches(
+			&networkingv1alpha1.CLBPortPool{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForCLBPortPool),
+		)
+	for _, kind := range getSupportedGatewayRouteKinds() {
+		b = b.Watches(
+			kind.NewObject(),
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForRoute(kind)),
+		)
+	}
+	return b.Named("gateway").Complete(r)
+}
+
+func (r *GatewayReconciler) listGateways(ctx context.Context, match func(*gatewayv1.Gateway) bool) []reconcile.Request {
+	list := &gatewayv1.GatewayList{}
+	if err := r.List(ctx, list); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list Gateway")
+		return []reconcile.Request{}
+	}
+	ret := []reconcile.Request{}
+	for i := range list.Items {
+		gw := &list.Items[i]
+		if match(gw) {
+			ret = append(ret, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      gw.Name,
+					Namespace: gw.Namespace,
+				},
+			})
+		}
+	}
+	return ret
+}
+
+func (r *GatewayReconciler) findObjectsForGatewayClass(ctx context.Context, gc client.Object) []reconcile.Request {
+	return r.listGateways(ctx, func(gw *gatewayv1.Gateway) bool {
+		return string(gw.Spec.GatewayClassName) == gc.GetName()
+	})
+}
+
+func (r *GatewayReconciler) findObjectsForCLBPortPool(ctx context.Context, pp client.Object) []reconcile.Request {
+	return r.listGateways(ctx, func(gw *gatewayv1.Gateway) bool {
+		return slices.Contains(getGatewayPools(gw), pp.GetName())
+	})
+}
+
+// 路由变化时通知其引用的 Gateway 重新统计挂载的路由数量
+func (r *GatewayReconciler) findObjectsForRoute(kind *gatewayRouteKind) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		route := kind.Wrap(obj)
+		ret := []reconcile.Request{}
+		for _, ref := range route.GetParentRefs() {
+			if !isGatewayGroup(ref.Group) || (ref.Kind != nil && *ref.Kind != "Gateway") {
+				continue
+			}
+			namespace := route.GetNamespace()
+			if ref.Namespace != nil {
+				namespace = string(*ref.Namespace)
+			}
+			ret = append(ret, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      string(ref.Name),
+					Namespace: namespace,
+				},
+			})
+		}
+		return ret
+	}
+}
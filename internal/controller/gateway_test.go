@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/pkg/clusterinfo"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+)
+
+func newTestGateway() *gatewayv1.Gateway {
+	addrType := gatewayv1.AddressType(constant.CLBPortPoolAddressType)
+	return &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gw"},
+		Spec: gatewayv1.GatewaySpec{
+			Addresses: []gatewayv1.GatewaySpecAddress{
+				{Type: &addrType, Value: "pool-a"},
+				{Type: &addrType, Value: "pool-b"},
+				{Type: &addrType, Value: "pool-a"},
+				{Value: "1.1.1.1"},
+			},
+			Listeners: []gatewayv1.Listener{
+				{Name: "tcp", Port: 80, Protocol: gatewayv1.TCPProtocolType},
+				{Name: "udp", Port: 80, Protocol: gatewayv1.UDPProtocolType},
+				{Name: "http", Port: 8080, Protocol: gatewayv1.HTTPProtocolType},
+			},
+		},
+	}
+}
+
+func TestGetGatewayPools(t *testing.T) {
+	pools := getGatewayPools(newTestGateway())
+	if len(pools) != 2 || pools[0] != "pool-a" || pools[1] != "pool-b" {
+		t.Errorf("unexpected pools %v", pools)
+	}
+}
+
+func TestGatewayParentRef(t *testing.T) {
+	gw := newTestGateway()
+	otherNs := gatewayv1.Namespace("other")
+	service := gatewayv1.Kind("Service")
+	if !isParentRefToGateway(&gatewayv1.ParentReference{Name: "gw"}, "default", gw) {
+		t.Error("expect parentRef in same namespace matched")
+	}
+	if isParentRefToGateway(&gatewayv1.ParentReference{Name: "gw", Namespace: &otherNs}, "default", gw) {
+		t.Error("expect parentRef to other namespace not matched")
+	}
+	if isParentRefToGateway(&gatewayv1.ParentReference{Name: "gw", Kind: &service}, "default", gw) {
+		t.Error("expect parentRef of other kind not matched")
+	}
+	section := gatewayv1.SectionName("udp")
+	if listeners := getParentRefListeners(&gatewayv1.ParentReference{Name: "gw", SectionName: &section}, gw); len(listeners) != 1 || listeners[0].Name != "udp" {
+		t.Errorf("expect only udp listener selected by sectionName, got %v", listeners)
+	}
+	port := gatewayv1.PortNumber(80)
+	if listeners := getParentRefListeners(&gatewayv1.ParentReference{Name: "gw", Port: &port}, gw); len(listeners) != 2 {
+		t.Errorf("expect 2 listeners selected by port, got %d", len(listeners))
+	}
+}
+
+func TestGetListenerRouteKind(t *testing.T) {
+	kinds := clusterinfo.GatewayRouteKinds
+	clusterinfo.GatewayRouteKinds = []string{"TCPRoute", "TLSRoute"}
+	defer func() { clusterinfo.GatewayRouteKinds = kinds }()
+
+	if kind, _, _ := getListenerRouteKind(&gatewayv1.Listener{Protocol: gatewayv1.TCPProtocolType}); kind == nil || kind.Kind != "TCPRoute" {
+		t.Errorf("expect TCPRoute for TCP listener, got %v", kind)
+	}
+	// UDPRoute 未安装
+	if kind, reason, _ := getListenerRouteKind(&gatewayv1.Listener{Protocol: gatewayv1.UDPProtocolType}); kind != nil || reason != gatewayv1.ListenerReasonUnsupportedProtocol {
+		t.Errorf("expect UDP listener unsupported, got %v %s", kind, reason)
+	}
+	terminate := gatewayv1.TLSModeTerminate
+	if kind, reason, _ := getListenerRouteKind(&gatewayv1.Listener{Protocol: gatewayv1.TLSProtocolType, TLS: &gatewayv1.ListenerTLSConfig{Mode: &terminate}}); kind != nil || reason != gatewayv1.ListenerReasonUnsupportedValue {
+		t.Errorf("expect TLS terminate listener unsupported, got %v %s", kind, reason)
+	}
+	udpRouteKind := gatewayv1.RouteGroupKind{Kind: "UDPRoute"}
+	if kind, reason, _ := getListenerRouteKind(&gatewayv1.Listener{
+		Protocol:      gatewayv1.TCPProtocolType,
+		AllowedRoutes: &gatewayv1.AllowedRoutes{Kinds: []gatewayv1.RouteGroupKind{udpRouteKind}},
+	}); kind != nil || reason != gatewayv1.ListenerReasonInvalidRouteKinds {
+		t.Errorf("expect invalid route kinds, got %v %s", kind, reason)
+	}
+}
+
+func TestGatewayRouteEnsureSharedBindings(t *testing.T) {
+	ctx := context.Background()
+	route := tcpRoute{&gatewayv1.TCPRoute{
+		TypeMeta:   metav1.TypeMeta{APIVersion: gatewayv1.GroupVersion.String(), Kind: "TCPRoute"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "game", UID: "route-uid"},
+	}}
+	kind := gatewayRouteKinds["TCPRoute"]
+	// 路由不再使用的端口池对应的 CLBSharedBinding 需要被删除
+	stale := &networkingv1alpha1.CLBSharedBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      gatewaySharedBindingName(kind, route, "pool-old"),
+			Labels:    map[string]string{constant.GatewayRouteUIDLabelKey: "route-uid"},
+		},
+		Spec: networkingv1alpha1.CLBSharedBindingSpec{Pool: "pool-old", Port: 7777, Protocol: "TCP"},
+	}
+	// 已有的 CLBSharedBinding 已分配到地址
+	existing := &networkingv1alpha1.CLBSharedBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      gatewaySharedBindingName(kind, route, "pool-a"),
+			Labels:    map[string]string{constant.GatewayRouteUIDLabelKey: "route-uid"},
+		},
+		Spec:   networkingv1alpha1.CLBSharedBindingSpec{Pool: "pool-a", Port: 7000, Protocol: "TCP"},
+		Status: networkingv1alpha1.CLBSharedBindingStatus{Address: "1.1.1.1:30000"},
+	}
+	c := newFakeClient(t, route.TCPRoute, stale, existing)
+	r := &GatewayRouteReconciler{Client: c, Scheme: c.Scheme(), Recorder: record.NewFakeRecorder(100), Kind: "TCPRoute"}
+	backend := &gatewayRouteBackend{Selector: map[string]string{"app": "game"}, Port: 7777, Weight: util.GetPtr(int64(10))}
+	addresses, err := r.ensureSharedBindings(ctx, kind, route, backend, []string{"pool-a", "pool-b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(addresses) != 1 || addresses[0] != "1.1.1.1:30000" {
+		t.Errorf("unexpected addresses %v", addresses)
+	}
+	list := &networkingv1alpha1.CLBSharedBindingList{}
+	if err := c.List(ctx, list); err != nil {
+		t.Fatal(err)
+	}
+	pools := map[string]networkingv1alpha1.CLBSharedBindingSpec{}
+	for _, sb := range list.Items {
+		pools[sb.Spec.Pool] = sb.Spec
+	}
+	if len(pools) != 2 {
+		t.Fatalf("expect shared bindings for pool-a and pool-b, got %v", pools)
+	}
+	for _, pool := range []string{"pool-a", "pool-b"} {
+		spec, ok := pools[pool]
+		if !ok || spec.Port != 7777 || spec.Protocol != constant.ProtocolTCP || util.GetValue(spec.Weight) != 10 {
+			t.Errorf("unexpected shared binding spec of %s: %+v", pool, spec)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+)
+
+// GatewayClassReconciler reconciles a GatewayClass object
+type GatewayClassReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gatewayclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gatewayclasses/status,verbs=get;update;patch
+
+// Reconcile 接受 controllerName 为本控制器的 GatewayClass
+func (r *GatewayClassReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	gc := &gatewayv1.GatewayClass{}
+	if err := r.Get(ctx, req.NamespacedName, gc); err != nil {
+		return result, errors.WithStack(client.IgnoreNotFound(err))
+	}
+	if gc.Spec.ControllerName != constant.GatewayControllerName || gc.DeletionTimestamp != nil {
+		return result, nil
+	}
+	changed := meta.SetStatusCondition(&gc.Status.Conditions, metav1.Condition{
+		Type:               string(gatewayv1.GatewayClassConditionStatusAccepted),
+		Status:             metav1.ConditionTrue,
+		Reason:             string(gatewayv1.GatewayClassReasonAccepted),
+		Message:            "accepted by " + constant.GatewayControllerName,
+		ObservedGeneration: gc.Generation,
+	})
+	if changed {
+		if err := r.Status().Update(ctx, gc); err != nil {
+			return result, errors.WithStack(err)
+		}
+	}
+	return result, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GatewayClassReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&gatewayv1.GatewayClass{}).
+		Named("gatewayclass").
+		Complete(r)
+}
@@ -0,0 +1,390 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+)
+
+// GatewayRouteReconciler reconciles TCPRoute/UDPRoute/TLSRoute objects
+type GatewayRouteReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// 路由类型：TCPRoute/UDPRoute/TLSRoute
+	Kind string
+}
+
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=tcproutes;udproutes;tlsroutes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=tcproutes/status;udproutes/status;tlsroutes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch
+// +kubebuilder:rbac:groups=networking.cloud.tencent.com,resources=clbsharedbindings,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile 对账挂载到本控制器管理的 Gateway 上的路由：为路由在 Gateway 引用的每个端口池中创建一个 CLBSharedBinding，
+// 由 CLBSharedBinding 分配端口并将监听器绑定到 backendRef 指向的 Service 的 Pod，分配到的地址写入路由的 status。
+// CLBSharedBinding 归属于路由，路由删除后级联删除并释放端口。
+func (r *GatewayRouteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	kind := gatewayRouteKinds[r.Kind]
+	obj := kind.NewObject()
+	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
+		return result, errors.WithStack(client.IgnoreNotFound(err))
+	}
+	if obj.GetDeletionTimestamp() != nil {
+		return result, nil
+	}
+	return result, r.sync(ctx, kind, kind.Wrap(obj))
+}
+
+// 路由解析后端的结果，Service 的 selector 选中的 Pod 作为监听器的后端
+type gatewayRouteBackend struct {
+	Selector map[string]string
+	Port     uint16
+	Weight   *int64
+}
+
+func (r *GatewayRouteReconciler) sync(ctx context.Context, kind *gatewayRouteKind, route gatewayRoute) error {
+	routeStatus := route.GetRouteStatus()
+	newStatus := routeStatus.DeepCopy()
+	// 保留其它控制器写入的 parent 状态
+	newStatus.Parents = slices.DeleteFunc(newStatus.Parents, func(p gatewayv1.RouteParentStatus) bool {
+		return p.ControllerName == constant.GatewayControllerName
+	})
+
+	backend, resolvedReason, resolvedMsg, err := r.resolveBackend(ctx, kind, route)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	type parentResult struct {
+		ref      gatewayv1.ParentReference
+		accepted bool
+		reason   gatewayv1.RouteConditionReason
+		message  string
+	}
+	results := []parentResult{}
+	pools := []string{}
+	for _, ref := range route.GetParentRefs() {
+		gw, err := r.getManagedGateway(ctx, route, &ref)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if gw == nil {
+			continue
+		}
+		res := parentResult{ref: ref}
+		listeners := getParentRefListeners(&ref, gw)
+		if len(listeners) == 0 {
+			res.reason = gatewayv1.RouteReasonNoMatchingParent
+			res.message = "no listener matches the sectionName or port of parentRef"
+		} else {
+			for _, listener := range listeners {
+				allowed, err := isRouteAllowedByListener(ctx, r.Client, gw, listener, kind, route.GetNamespace())
+				if err != nil {
+					return errors.WithStack(err)
+				}
+				if allowed {
+					res.accepted = true
+					break
+				}
+			}
+			if !res.accepted {
+				res.reason = gatewayv1.RouteReasonNotAllowedByListeners
+				res.message = fmt.Sprintf("%s is not allowed by listeners of gateway %s/%s", kind.Kind, gw.Namespace, gw.Name)
+			}
+		}
+		if res.accepted {
+			for _, pool := range getGatewayPools(gw) {
+				if !slices.Contains(pools, pool) {
+					pools = append(pools, pool)
+				}
+			}
+		}
+		results = append(results, res)
+	}
+
+	// 只有后端解析成功时才分配端口
+	if backend == nil {
+		pools = nil
+	}
+	addresses, err := r.ensureSharedBindings(ctx, kind, route, backend, pools)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	for _, res := range results {
+		parent := gatewayv1.RouteParentStatus{
+			ParentRef:      res.ref,
+			ControllerName: constant.GatewayControllerName,
+		}
+		for _, old := range routeStatus.Parents {
+			if old.ControllerName == constant.GatewayControllerName && reflect.DeepEqual(old.ParentRef, res.ref) {
+				parent.Conditions = slices.Clone(old.Conditions)
+				break
+			}
+		}
+		accepted := metav1.Condition{
+			Type:               string(gatewayv1.RouteConditionAccepted),
+			Status:             metav1.ConditionTrue,
+			Reason:             string(gatewayv1.RouteReasonAccepted),
+			ObservedGeneration: route.GetGeneration(),
+		}
+		if !res.accepted {
+			accepted.Status = metav1.ConditionFalse
+			accepted.Reason = string(res.reason)
+			accepted.Message = res.message
+		} else if len(addresses) > 0 {
+			accepted.Message = "clb addresses: " + strings.Join(addresses, ",")
+		} else if backend != nil {
+			accepted.Message = "waiting for clb port allocation"
+		}
+		meta.SetStatusCondition(&parent.Conditions, accepted)
+		resolved := metav1.Condition{
+			Type:               string(gatewayv1.RouteConditionResolvedRefs),
+			Status:             metav1.ConditionTrue,
+			Reason:             string(gatewayv1.RouteReasonResolvedRefs),
+			ObservedGeneration: route.GetGeneration(),
+		}
+		if backend == nil {
+			resolved.Status = metav1.ConditionFalse
+			resolved.Reason = string(resolvedReason)
+			resolved.Message = resolvedMsg
+		}
+		meta.SetStatusCondition(&parent.Conditions, resolved)
+		newStatus.Parents = append(newStatus.Parents, parent)
+	}
+
+	if !reflect.DeepEqual(newStatus, routeStatus) {
+		*routeStatus = *newStatus
+		if err := r.Status().Update(ctx, route.GetObject()); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// 获取 parentRef 引用的 Gateway，非本控制器管理的 Gateway 返回 nil
+func (r *GatewayRouteReconciler) getManagedGateway(ctx context.Context, route gatewayRoute, ref *gatewayv1.ParentReference) (*gatewayv1.Gateway, error) {
+	if !isGatewayGroup(ref.Group) || (ref.Kind != nil && *ref.Kind != "Gateway") {
+		return nil, nil
+	}
+	namespace := route.GetNamespace()
+	if ref.Namespace != nil {
+		namespace = string(*ref.Namespace)
+	}
+	gw := &gatewayv1.Gateway{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: string(ref.Name)}, gw); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.WithStack(err)
+	}
+	if managed, err := isManagedGatewayClass(ctx, r.Client, gw.Spec.GatewayClassName); err != nil || !managed {
+		return nil, errors.WithStack(err)
+	}
+	return gw, nil
+}
+
+// 解析路由的 backendRef，目前只支持同命名空间的单个 Service，且 targetPort 必须是数字
+func (r *GatewayRouteReconciler) resolveBackend(ctx context.Context, kind *gatewayRouteKind, route gatewayRoute) (*gatewayRouteBackend, gatewayv1.RouteConditionReason, string, error) {
+	refs := route.GetBackendRefs()
+	if len(refs) != 1 {
+		return nil, gatewayv1.RouteReasonBackendNotFound, "exactly one backendRef is required", nil
+	}
+	ref := refs[0]
+	if (ref.Group != nil && *ref.Group != "") || (ref.Kind != nil && *ref.Kind != "Service") {
+		return nil, gatewayv1.RouteReasonInvalidKind, "only Service backendRef is supported", nil
+	}
+	if ref.Namespace != nil && string(*ref.Namespace) != route.GetNamespace() {
+		return nil, gatewayv1.RouteReasonRefNotPermitted, "cross namespace backendRef is not supported", nil
+	}
+	if ref.Port == nil {
+		return nil, gatewayv1.RouteReasonBackendNotFound, "port of backendRef is required", nil
+	}
+	svc := &corev1.Service{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: route.GetNamespace(), Name: string(ref.Name)}, svc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, gatewayv1.RouteReasonBackendNotFound, fmt.Sprintf("service %s not found", ref.Name), nil
+		}
+		return nil, "", "", errors.WithStack(err)
+	}
+	if len(svc.Spec.Selector) == 0 {
+		return nil, gatewayv1.RouteReasonBackendNotFound, fmt.Sprintf("service %s has no selector", svc.Name), nil
+	}
+	for _, port := range svc.Spec.Ports {
+		if port.Port != *ref.Port {
+			continue
+		}
+		protocol := port.Protocol
+		if protocol == "" {
+			protocol = corev1.ProtocolTCP
+		}
+		if string(protocol) != kind.CLBProtocol {
+			return nil, gatewayv1.RouteReasonUnsupportedProtocol, fmt.Sprintf("protocol of service port %d is %s, expect %s", port.Port, protocol, kind.CLBProtocol), nil
+		}
+		targetPort := port.Port
+		if port.TargetPort.StrVal != "" {
+			return nil, gatewayv1.RouteReasonBackendNotFound, fmt.Sprintf("named targetPort %s of service %s is not supported", port.TargetPort.StrVal, svc.Name), nil
+		} else if port.TargetPort.IntVal > 0 {
+			targetPort = port.TargetPort.IntVal
+		}
+		backend := &gatewayRouteBackend{
+			Selector: svc.Spec.Selector,
+			Port:     uint16(targetPort),
+		}
+		// 只有一个后端，权重仅用于区分是否摘流
+		if ref.Weight != nil && *ref.Weight == 0 {
+			backend.Weight = util.GetPtr(int64(0))
+		}
+		return backend, "", "", nil
+	}
+	return nil, gatewayv1.RouteReasonBackendNotFound, fmt.Sprintf("port %d not found in service %s", *ref.Port, svc.Name), nil
+}
+
+func gatewaySharedBindingName(kind *gatewayRouteKind, route gatewayRoute, pool string) string {
+	return fmt.Sprintf("%s-%s-%s", strings.ToLower(kind.Kind), route.GetName(), pool)
+}
+
+// 确保路由在每个端口池中都有对应的 CLBSharedBinding，删除多余的，返回已分配的地址
+func (r *GatewayRouteReconciler) ensureSharedBindings(ctx context.Context, kind *gatewayRouteKind, route gatewayRoute, backend *gatewayRouteBackend, pools []string) ([]string, error) {
+	list := &networkingv1alpha1.CLBSharedBindingList{}
+	if err := r.List(ctx, list, client.InNamespace(route.GetNamespace()), client.MatchingLabels{constant.GatewayRouteUIDLabelKey: string(route.GetUID())}); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	existing := map[string]*networkingv1alpha1.CLBSharedBinding{}
+	for i := range list.Items {
+		existing[list.Items[i].Name] = &list.Items[i]
+	}
+	addresses := []string{}
+	for _, pool := range pools {
+		name := gatewaySharedBindingName(kind, route, pool)
+		spec := networkingv1alpha1.CLBSharedBindingSpec{
+			Selector: backend.Selector,
+			Port:     backend.Port,
+			Protocol: kind.CLBProtocol,
+			Pool:     pool,
+			Weight:   backend.Weight,
+		}
+		sb, ok := existing[name]
+		delete(existing, name)
+		if !ok {
+			sb = &networkingv1alpha1.CLBSharedBinding{}
+			sb.Namespace = route.GetNamespace()
+			sb.Name = name
+			sb.Labels = map[string]string{constant.GatewayRouteUIDLabelKey: string(route.GetUID())}
+			sb.Spec = spec
+			if err := controllerutil.SetControllerReference(route.GetObject(), sb, r.Scheme); err != nil {
+				return nil, errors.WithStack(err)
+			}
+			if err := r.Create(ctx, sb); err != nil {
+				return nil, errors.WithStack(err)
+			}
+			r.Recorder.Eventf(route.GetObject(), corev1.EventTypeNormal, "CLBSharedBindingCreated", "create CLBSharedBinding %s for port pool %s", name, pool)
+			continue
+		}
+		if !reflect.DeepEqual(sb.Spec, spec) {
+			sb.Spec = spec
+			if err := r.Update(ctx, sb); err != nil {
+				return nil, errors.WithStack(err)
+			}
+		}
+		if sb.Status.Address != "" {
+			addresses = append(addresses, sb.Status.Address)
+		}
+	}
+	for _, sb := range existing {
+		if sb.DeletionTimestamp != nil {
+			continue
+		}
+		if err := r.Delete(ctx, sb); err != nil && !apierrors.IsNotFound(err) {
+			return nil, errors.WithStack(err)
+		}
+		r.Recorder.Eventf(route.GetObject(), corev1.EventTypeNormal, "CLBSharedBindingDeleted", "delete CLBSharedBinding %s", sb.Name)
+	}
+	sort.Strings(addresses)
+	return addresses, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GatewayRouteReconciler) SetupWithManager(mgr ctrl.Manager, workers int) error {
+	kind := gatewayRouteKinds[r.Kind]
+	return ctrl.NewControllerManagedBy(mgr).
+		For(kind.NewObject()).
+		Owns(&networkingv1alpha1.CLBSharedBinding{}).
+		Watches(
+			&gatewayv1.Gateway{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForGateway),
+		).
+		Watches(
+			&corev1.Service{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForService),
+		).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: workers,
+		}).
+		Named(strings.ToLower(kind.Kind)).
+		Complete(r)
+}
+
+func (r *GatewayRouteReconciler) listRoutes(ctx context.Context, opts []client.ListOption, match func(gatewayRoute) bool) []reconcile.Request {
+	kind := gatewayRouteKinds[r.Kind]
+	list := kind.NewList()
+	if err := r.List(ctx, list, opts...); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list "+kind.Kind)
+		return []reconcile.Request{}
+	}
+	ret := []reconcile.Request{}
+	for _, route := range kind.Items(list) {
+		if match(route) {
+			ret = append(ret, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      route.GetName(),
+					Namespace: route.GetNamespace(),
+				},
+			})
+		}
+	}
+	return ret
+}
+
+// 查找引用了该 Gateway 的路由
+func (r *GatewayRouteReconciler) findObjectsForGateway(ctx context.Context, obj client.Object) []reconcile.Request {
+	gw := obj.(*gatewayv1.Gateway)
+	return r.listRoutes(ctx, nil, func(route gatewayRoute) bool {
+		return slices.ContainsFunc(route.GetParentRefs(), func(ref gatewayv1.ParentReference) bool {
+			return isParentRefToGateway(&ref, route.GetNamespace(), gw)
+		})
+	})
+}
+
+// 查找 backendRef 指向该 Service 的路由
+func (r *GatewayRouteReconciler) findObjectsForService(ctx context.Context, svc client.Object) []reconcile.Request {
+	return r.listRoutes(ctx, []client.ListOption{client.InNamespace(svc.GetNamespace())}, func(route gatewayRoute) bool {
+		return slices.ContainsFunc(route.GetBackendRefs(), func(ref gatewayv1.BackendRef) bool {
+			return string(ref.Name) == svc.GetName()
+		})
+	})
+}
@@ -0,0 +1,323 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/cleanupqueue"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/internal/portpool"
+	"github.com/tkestack/tke-extend-network-controller/pkg/clb"
+	"github.com/tkestack/tke-extend-network-controller/pkg/kube"
+	"github.com/tkestack/tke-extend-network-controller/pkg/ssl"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// 清理队列的默认处理间隔
+const defaultGCInterval = time.Minute
+
+var gcLog = ctrl.Log.WithName("gc")
+
+// GCReconciler 定期处理持久化清理队列中的任务（强制清理 CLBBinding 时遗留的监听器和 rs），
+// 清理失败的任务保留在队列中，下个周期继续重试，超过最大重试次数后放弃。同时清理 kubernetes.io/tls Secret 更新证书后不再被引用的旧证书。
+type GCReconciler struct {
+	client.Client
+	Interval time.Duration
+}
+
+func (r *GCReconciler) NeedLeaderElection() bool {
+	return true
+}
+
+func (r *GCReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Interval <= 0 {
+		r.Interval = defaultGCInterval
+	}
+	return mgr.Add(r)
+}
+
+func (r *GCReconciler) Start(ctx context.Context) error {
+	gcLog.Info("starting gc", "interval", r.Interval.String())
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-cleanupqueue.Notify():
+		}
+		if err := r.drain(ctx); err != nil {
+			gcLog.Error(err, "drain cleanup queue failed")
+		}
+		if err := r.cleanupStaleCerts(ctx); err != nil {
+			gcLog.Error(err, "cleanup stale certs failed")
+		}
+	}
+}
+
+// 处理队列中所有的清理任务
+func (r *GCReconciler) drain(ctx context.Context) error {
+	tasks, err := cleanupqueue.List(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(tasks) == 0 {
+		return nil
+	}
+	done := []*cleanupqueue.Task{}
+	failed := []*cleanupqueue.Task{}
+	for _, task := range tasks {
+		if err := r.cleanup(ctx, task); err != nil {
+			if task.Retries+1 >= cleanupqueue.MaxRetries { // 一直清理失败（如权限不足），放弃清理，避免队列无限增长
+				gcLog.Error(
+					err, "cleanup failed too many times, give up, please cleanup the cloud resources manually",
+					"task", task.Key(), "source", task.Source, "region", task.Region, "lbId", task.LbId,
+					"listenerId", task.ListenerId, "port", task.Port, "protocol", task.Protocol, "retries", task.Retries+1,
+				)
+				done = append(done, task)
+				continue
+			}
+			gcLog.Error(err, "cleanup failed, will retry", "task", task.Key(), "source", task.Source, "retries", task.Retries)
+			task.Retries++
+			task.LastError = err.Error()
+			failed = append(failed, task)
+			continue
+		}
+		gcLog.Info("cleanup success", "task", task.Key(), "source", task.Source)
+		done = append(done, task)
+	}
+	if err := cleanupqueue.Update(ctx, done, failed); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// 清理单个任务，监听器或 lb 已不存在视为清理成功
+func (r *GCReconciler) cleanup(ctx context.Context, task *cleanupqueue.Task) error {
+	if task.LocationId != "" { // 七层端口绑定，只删除转发规则
+		if err := deleteLayer7Rule(ctx, task.Region, task.LbId, task.ListenerId, task.LocationId, task.Domain, task.Url); err != nil {
+			return errors.WithStack(err)
+		}
+		return nil
+	}
+	lis, err := clb.GetListenerByIdOrPort(ctx, task.Region, task.LbId, task.ListenerId, int64(task.Port), task.Protocol)
+	if err != nil {
+		if clb.IsLoadBalancerNotExistsError(errors.Cause(err)) {
+			return nil
+		}
+		return errors.WithStack(err)
+	}
+	if lis == nil {
+		return nil
+	}
+	// 强制清理时端口已释放，可能已被重新分配给其它 CLBBinding
+	allocated := portpool.Allocator.IsAllocated(
+		task.Pool, portpool.NewLBKey(task.LbId, task.Region),
+		portpool.ProtocolPort{Port: task.Port, EndPort: task.EndPort, Protocol: task.Protocol},
+	)
+	if task.DeregisterOnly { // 预创建监听器，仅解绑 rs
+		if allocated { // 已被重新分配，残留的 rs 由新的 CLBBinding 对账时清理
+			return nil
+		}
+		if err := clb.DeregisterAllTargetsTryBatch(ctx, task.Region, task.LbId, lis.ListenerId); err != nil {
+			return errors.WithStack(err)
+		}
+		return nil
+	}
+	if owner := clb.ParseListenerName(lis.ListenerName); owner != nil {
+		if owner.UID != task.OwnerUID && !slices.Contains(task.PreviousOwnerUIDs, owner.UID) { // 监听器属于其它 CLBBinding，不能删除
+			return nil
+		}
+	} else if allocated { // 无法识别归属的监听器，端口已被重新分配时可能已被其它 CLBBinding 复用，不能删除
+		return nil
+	}
+	clb.GetListenerCache(clb.LBKey{Region: task.Region, LbId: task.LbId}).EnsureRemoved(ctx, task.Port, task.Protocol)
+	if err := clb.DeleteListenerById(ctx, task.Region, task.LbId, lis.ListenerId); err != nil {
+		errCause := errors.Cause(err)
+		if errCause == clb.ErrListenerNotFound || clb.IsLoadBalancerNotExistsError(errCause) {
+			return nil
+		}
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// 清理 Secret 中记录的旧证书：证书没有被任何 CLBBinding 或 CLBListener 引用时从 SSL 证书服务删除，并从 Secret 的注解中移除
+func (r *GCReconciler) cleanupStaleCerts(ctx context.Context) error {
+	// 只有控制器上传过证书的 Secret 才会有待清理的旧证书
+	secrets := &corev1.SecretList{}
+	if err := r.List(ctx, secrets, client.MatchingLabels{constant.ManagedCertLabelKey: "true"}); err != nil {
+		return errors.WithStack(err)
+	}
+	var inUse map[string]bool
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		staleCertIds := kube.GetStaleCertIds(secret)
+		if len(staleCertIds) == 0 {
+			continue
+		}
+		if inUse == nil {
+			certIds, err := r.getCertIdsInUse(ctx, secrets.Items)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			inUse = certIds
+		}
+		remain := []string{}
+		for _, certId := range staleCertIds {
+			if inUse[certId] {
+				remain = append(remain, certId)
+				continue
+			}
+			if err := ssl.DeleteCertificate(ctx, certId); err != nil {
+				gcLog.Error(err, "delete stale cert failed, will retry", "certId", certId, "secret", client.ObjectKeyFromObject(secret))
+				remain = append(remain, certId)
+				continue
+			}
+			gcLog.Info("stale cert deleted", "certId", certId, "secret", client.ObjectKeyFromObject(secret))
+		}
+		if len(remain) == len(staleCertIds) {
+			continue
+		}
+		var value any // 全部清理完成时删除注解
+		if len(remain) > 0 {
+			value = strings.Join(remain, ",")
+		}
+		patchMap := map[string]any{
+			"metadata": map[string]any{
+				"annotations": map[string]any{
+					constant.StaleCertIdsKey: value,
+				},
+			},
+		}
+		if err := kube.PatchMap(ctx, r.Client, secret, patchMap); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// 获取所有正在使用的证书 ID：CLBBinding、CLBListener、端口池七层监听器和 DedicatedCLBListener 引用的证书，
+// 以及 Secret 当前记录的证书（相同的证书上传时会返回已有的证书 ID，多个 Secret 可能共用同一个证书）
+func (r *GCReconciler) getCertIdsInUse(ctx context.Context, secrets []corev1.Secret) (map[string]bool, error) {
+	certIds := make(map[string]bool)
+	for _, secret := range secrets {
+		for _, key := range []string{constant.CertIdKey, constant.CACertIdKey} {
+			if id := secret.GetAnnotations()[key]; id != "" {
+				certIds[id] = true
+			}
+		}
+	}
+	pbl := &networkingv1alpha1.CLBPodBindingList{}
+	if err := r.List(ctx, pbl); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	nbl := &networkingv1alpha1.CLBNodeBindingList{}
+	if err := r.List(ctx, nbl); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	statuses := []*networkingv1alpha1.CLBBindingStatus{}
+	for i := range pbl.Items {
+		statuses = append(statuses, &pbl.Items[i].Status)
+	}
+	for i := range nbl.Items {
+		statuses = append(statuses, &nbl.Items[i].Status)
+	}
+	for _, status := range statuses {
+		for _, binding := range status.PortBindings {
+			if binding.CertId != nil {
+				certIds[*binding.CertId] = true
+			}
+			if binding.CACertId != nil {
+				certIds[*binding.CACertId] = true
+			}
+			for _, id := range binding.ExtraCertIds {
+				certIds[id] = true
+			}
+		}
+	}
+	ll := &networkingv1alpha1.CLBListenerList{}
+	if err := r.List(ctx, ll); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for _, lis := range ll.Items {
+		if lis.Spec.CertId != nil {
+			certIds[*lis.Spec.CertId] = true
+		}
+		if lis.Spec.CACertId != nil {
+			certIds[*lis.Spec.CACertId] = true
+		}
+		for _, id := range lis.Spec.ExtraCertIds {
+			certIds[id] = true
+		}
+	}
+	ppl := &networkingv1alpha1.CLBPortPoolList{}
+	if err := r.List(ctx, ppl); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for _, pp := range ppl.Items {
+		if pp.Spec.Layer7 != nil && util.GetValue(pp.Spec.Layer7.CertId) != "" {
+			certIds[*pp.Spec.Layer7.CertId] = true
+		}
+	}
+	// DedicatedCLBListener 的证书配置在创建监听器的参数中
+	dll := &networkingv1alpha1.DedicatedCLBListenerList{}
+	if err := r.List(ctx, dll); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for _, lis := range dll.Items {
+		for _, id := range getExtensiveParametersCertIds(lis.Spec.ExtensiveParameters) {
+			certIds[id] = true
+		}
+	}
+	return certIds, nil
+}
+
+// 创建监听器参数中的证书配置
+type extensiveParametersCert struct {
+	Certificate *struct {
+		CertId   *string `json:"CertId"`
+		CertCaId *string `json:"CertCaId"`
+	} `json:"Certificate"`
+	MultiCertInfo *struct {
+		CertList []struct {
+			CertId *string `json:"CertId"`
+		} `json:"CertList"`
+	} `json:"MultiCertInfo"`
+}
+
+// 获取创建监听器参数（JSON 格式）中引用的证书 ID，参数无法解析时忽略
+func getExtensiveParametersCertIds(params string) []string {
+	if params == "" {
+		return nil
+	}
+	cert := &extensiveParametersCert{}
+	if err := json.Unmarshal([]byte(params), cert); err != nil {
+		return nil
+	}
+	ids := []string{}
+	if c := cert.Certificate; c != nil {
+		for _, id := range []*string{c.CertId, c.CertCaId} {
+			if util.GetValue(id) != "" {
+				ids = append(ids, *id)
+			}
+		}
+	}
+	if cert.MultiCertInfo != nil {
+		for _, c := range cert.MultiCertInfo.CertList {
+			if util.GetValue(c.CertId) != "" {
+				ids = append(ids, *c.CertId)
+			}
+		}
+	}
+	return ids
+}
@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/cleanupqueue"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+)
+
+func TestGCGetCertIdsInUse(t *testing.T) {
+	pool := &networkingv1alpha1.CLBPortPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool-l7"},
+		Spec: networkingv1alpha1.CLBPortPoolSpec{
+			StartPort: 30000,
+			Layer7:    &networkingv1alpha1.Layer7Config{Domain: "{{node}}.example.com", CertId: util.GetPtr("cert-pool")},
+		},
+	}
+	dedicated := &networkingv1alpha1.DedicatedCLBListener{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gs-0-tcp-7777"},
+		Spec: networkingv1alpha1.DedicatedCLBListenerSpec{
+			LbId:                "lb-1",
+			LbPort:              30000,
+			Protocol:            "TCP",
+			ExtensiveParameters: `{"Certificate":{"CertId":"cert-dedicated","CertCaId":"cert-dedicated-ca"},"MultiCertInfo":{"CertList":[{"CertId":"cert-multi"}]}}`,
+		},
+	}
+	podBinding := &networkingv1alpha1.CLBPodBinding{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gs-0"},
+		Status: networkingv1alpha1.CLBBindingStatus{
+			PortBindings: []networkingv1alpha1.PortBindingStatus{{CertId: util.GetPtr("cert-binding"), ExtraCertIds: []string{"cert-extra"}}},
+		},
+	}
+	c := newFakeClient(t, pool, dedicated, podBinding)
+	r := &GCReconciler{Client: c}
+	// 内容相同的证书上传后返回同一个证书 ID，其它 Secret 当前使用的证书也不能删除
+	secrets := []corev1.Secret{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "a", Annotations: map[string]string{
+			constant.CertIdKey:       "cert-new",
+			constant.StaleCertIdsKey: "cert-shared,cert-stale",
+		}}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "b", Annotations: map[string]string{
+			constant.CertIdKey:   "cert-shared",
+			constant.CACertIdKey: "cert-ca",
+		}}},
+	}
+	inUse, err := r.getCertIdsInUse(context.Background(), secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"cert-new", "cert-shared", "cert-ca", "cert-pool", "cert-dedicated", "cert-dedicated-ca", "cert-multi", "cert-binding", "cert-extra"} {
+		if !inUse[id] {
+			t.Errorf("expect %s in use", id)
+		}
+	}
+	if inUse["cert-stale"] {
+		t.Error("stale cert not referenced by anything should not be in use")
+	}
+}
+
+func TestGCDrainGiveUp(t *testing.T) {
+	ctx := context.Background()
+	cloud := useFakeCloud(t)
+	cloud.SetError("DescribeListeners", "UnauthorizedOperation")
+	c := newFakeClient(t)
+	cleanupqueue.SetClient(c, c, "kube-system")
+	retry := &cleanupqueue.Task{Region: "ap-gc-test", LbId: "lb-gc", Port: 30000, Protocol: "TCP", OwnerUID: "uid-1"}
+	giveUp := &cleanupqueue.Task{Region: "ap-gc-test", LbId: "lb-gc", Port: 30001, Protocol: "TCP", OwnerUID: "uid-1", Retries: cleanupqueue.MaxRetries - 1}
+	if err := cleanupqueue.Add(ctx, retry, giveUp); err != nil {
+		t.Fatal(err)
+	}
+	r := &GCReconciler{Client: c}
+	if err := r.drain(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// 清理失败的任务保留并累加重试次数，超过最大重试次数的任务放弃清理并从队列中移除
+	tasks, err := cleanupqueue.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 || tasks[0].Key() != retry.Key() || tasks[0].Retries != 1 || tasks[0].LastError == "" {
+		t.Fatalf("expect only retry task kept with retries 1, got %+v", tasks)
+	}
+}
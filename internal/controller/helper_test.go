@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/pkg/clb"
+	"github.com/tkestack/tke-extend-network-controller/pkg/cloudapi"
+)
+
+// 构造单元测试使用的 fake client，status 作为子资源更新
+func newFakeClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := networkingv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithStatusSubresource(
+		&networkingv1alpha1.CLBPortPool{},
+		&networkingv1alpha1.CLBPodBinding{},
+		&networkingv1alpha1.CLBNodeBinding{},
+		&networkingv1alpha1.CLBListener{},
+		&networkingv1alpha1.DedicatedCLBService{},
+		&networkingv1alpha1.DedicatedCLBListener{},
+		&networkingv1alpha1.CLBSharedBinding{},
+	).Build()
+}
+
+func newTestPod(name string, labels map[string]string, ip string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Labels: labels},
+		Status: corev1.PodStatus{
+			PodIP:      ip,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+// 影子模式的测试使用独立的地域，避免复用其它测试创建的云 API client
+const dryRunTestRegion = "ap-dry-run-test"
+
+// 开启影子模式，云 API 写操作只记录不执行，用于检查控制器预期的调用
+func enableTestDryRun(t *testing.T) {
+	t.Helper()
+	cloudapi.Init("test-id", "test-key")
+	clb.SetDryRun(true)
+	t.Cleanup(func() { clb.SetDryRun(false) })
+}
+
+// 模拟云 API 的 HTTP transport：按接口名返回预设的结果（未预设的接口返回空结果），并记录每次调用的请求参数
+type fakeCloudTransport struct {
+	mu      sync.Mutex
+	results map[string]map[string]any
+	errors  map[string]string
+	calls   map[string][]map[string]any
+}
+
+// 使用模拟的云 API，需使用其它测试没有用过的地域，避免复用已创建的云 API client
+func useFakeCloud(t *testing.T) *fakeCloudTransport {
+	t.Helper()
+	transport := &fakeCloudTransport{
+		results: make(map[string]map[string]any),
+		errors:  make(map[string]string),
+		calls:   make(map[string][]map[string]any),
+	}
+	cloudapi.Init("test-id", "test-key")
+	cloudapi.SetTransport(transport)
+	t.Cleanup(func() { cloudapi.SetTransport(nil) })
+	return transport
+}
+
+// 设置接口返回的结果
+func (t *fakeCloudTransport) SetResult(action string, result map[string]any) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.results[action] = result
+	delete(t.errors, action)
+}
+
+// 设置接口返回的错误码
+func (t *fakeCloudTransport) SetError(action, code string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.errors[action] = code
+}
+
+// 获取接口被调用时的请求参数
+func (t *fakeCloudTransport) Calls(action string) []map[string]any {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]map[string]any{}, t.calls[action]...)
+}
+
+func (t *fakeCloudTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	action := req.Header.Get("X-TC-Action")
+	params := map[string]any{}
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		_ = json.Unmarshal(body, &params)
+	}
+	t.mu.Lock()
+	t.calls[action] = append(t.calls[action], params)
+	result := map[string]any{}
+	if code, ok := t.errors[action]; ok {
+		result["Error"] = map[string]any{"Code": code, "Message": "fake error"}
+	} else if action == "DescribeTaskStatus" {
+		result["Status"] = 0
+	} else {
+		for k, v := range t.results[action] {
+			result[k] = v
+		}
+	}
+	result["RequestId"] = fmt.Sprintf("fake-%s-%d", action, len(t.calls[action]))
+	t.mu.Unlock()
+	data, err := json.Marshal(map[string]any{"Response": result})
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(data)),
+		Request:    req,
+	}, nil
+}
+
+// 设置是否通过 CLBListener 管理监听器，测试结束后恢复
+func setUseCLBListener(t *testing.T, enabled bool) {
+	t.Helper()
+	old := UseCLBListener
+	UseCLBListener = enabled
+	t.Cleanup(func() { UseCLBListener = old })
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/tkestack/tke-extend-network-controller/internal/clbbinding"
 	"github.com/tkestack/tke-extend-network-controller/internal/constant"
 	"github.com/tkestack/tke-extend-network-controller/pkg/eventsource"
+	"github.com/tkestack/tke-extend-network-controller/pkg/kube"
 	"github.com/tkestack/tke-extend-network-controller/pkg/util"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -38,6 +39,7 @@ import (
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -65,6 +67,9 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 }
 
 func (r *PodReconciler) sync(ctx context.Context, pod *corev1.Pod) (result ctrl.Result, err error) {
+	if err := r.ensureDrainFinalizerReleased(ctx, pod); err != nil {
+		return result, errors.WithStack(err)
+	}
 	// 获取 obj 的注解
 	if pod.Annotations[constant.EnableCLBPortMappingsKey] != "" {
 		result, err = r.syncCLBBinding(ctx, pod, clbbinding.NewCLBPodBinding())
@@ -92,6 +97,19 @@ func (r *PodReconciler) sync(ctx context.Context, pod *corev1.Pod) (result ctrl.
 	return
 }
 
+// 摘流 finalizer 由 CLBPodBinding 在摘流结束或清理完成后移除，Pod 删除时如果 CLBPodBinding 已不存在（如被手动删除，
+// 或 Pod 去掉了端口映射注解），不会再有对账移除该 finalizer，此时直接移除，避免 Pod 一直处于 Terminating 状态。
+func (r *PodReconciler) ensureDrainFinalizerReleased(ctx context.Context, pod *corev1.Pod) error {
+	if pod.DeletionTimestamp.IsZero() || !controllerutil.ContainsFinalizer(pod, constant.DrainFinalizer) {
+		return nil
+	}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(pod), &networkingv1alpha1.CLBPodBinding{}); err == nil || !apierrors.IsNotFound(err) {
+		return errors.WithStack(err)
+	}
+	log.FromContext(ctx).Info("remove drain finalizer of deleting pod without clbpodbinding")
+	return errors.WithStack(kube.RemovePodFinalizer(ctx, pod, constant.DrainFinalizer))
+}
+
 type HostPortMapping struct {
 	// 应用端口
 	ContainerPort uint16 `json:"containerPort"`
@@ -211,7 +229,9 @@ func (r *PodReconciler) SetupWithManager(mgr ctrl.Manager, workers int) error {
 }
 
 func (r *PodReconciler) findObjectsForPod(_ context.Context, obj client.Object) []reconcile.Request {
-	if anno := obj.GetAnnotations(); anno != nil && (anno[constant.EnableCLBPortMappingsKey] != "" || anno[constant.EnableCLBHostPortMapping] != "") {
+	// 带有摘流 finalizer 的 Pod 去掉端口映射注解后也需要对账，以便删除时移除 finalizer
+	if anno := obj.GetAnnotations(); (anno != nil && (anno[constant.EnableCLBPortMappingsKey] != "" || anno[constant.EnableCLBHostPortMapping] != "")) ||
+		controllerutil.ContainsFinalizer(obj, constant.DrainFinalizer) {
 		return []reconcile.Request{
 			{
 				NamespacedName: types.NamespacedName{
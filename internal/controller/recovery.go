@@ -0,0 +1,366 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/clbbinding"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/pkg/clb"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// RecoveryReport 记录从 CLB 监听器重建 CLBBinding 的结果
+type RecoveryReport struct {
+	// 已重建的 CLBBinding
+	Recovered []string
+	// CLBBinding 已存在，跳过
+	Skipped []string
+	// 无法匹配到 Pod/Node 的监听器或 rs，只记录，不做任何清理
+	Unmatched []string
+}
+
+// BindingRecovery 用于 CLBPodBinding/CLBNodeBinding 丢失后（如误删 CRD、etcd 回滚）的灾难恢复：
+// 扫描端口池中所有 CLB 上由控制器创建的监听器及其绑定的 rs，按 IP 和端口匹配到开启了端口映射的
+// Pod/Node，重建 CLBBinding 及其 status，控制器启动时会根据 status 重建端口分配器的状态，
+// 避免重新分配端口导致玩家地址变化以及旧监听器泄露。
+// 需要在控制器停止运行时执行，避免控制器在恢复完成前为 Pod/Node 分配新的端口。
+type BindingRecovery struct {
+	client.Client
+	// 只输出匹配结果，不创建 CLBBinding
+	DryRun bool
+}
+
+// 待重建 CLBBinding 的 Pod/Node
+type recoveryBackend struct {
+	obj      client.Object
+	binding  clbbinding.CLBBinding
+	exists   bool
+	bindings []networkingv1alpha1.PortBindingStatus
+	// 匹配到的监听器名称中记录的原 CLBBinding 的 UID
+	previousUIDs []string
+}
+
+func (b *recoveryBackend) String() string {
+	return fmt.Sprintf("%s %s", b.binding.GetType(), client.ObjectKeyFromObject(b.obj))
+}
+
+// 查找 rs 端口对应的端口映射配置，返回匹配到的端口池
+func (b *recoveryBackend) match(port int64, protocol string, pools []string) (*networkingv1alpha1.PortEntry, string) {
+	for i := range b.binding.GetSpec().Ports {
+		entry := &b.binding.GetSpec().Ports[i]
+		if int64(entry.Port) != port {
+			continue
+		}
+		if entry.Protocol != protocol && !(entry.Protocol == constant.ProtocolTCPUDP && (protocol == constant.ProtocolTCP || protocol == constant.ProtocolUDP)) {
+			continue
+		}
+		for _, pool := range pools {
+			if slices.Contains(entry.Pools, pool) {
+				return entry, pool
+			}
+		}
+	}
+	return nil, ""
+}
+
+// 待重建 CLBBinding 的 Pod/Node 集合，按 IP 和端口索引：hostNetwork 的 Pod 与所在 Node 的 IP 相同，需结合端口区分
+type recoveryBackends struct {
+	items []*recoveryBackend
+	index map[string][]*recoveryBackend
+}
+
+func recoveryBackendKey(ip string, port int64) string {
+	return net.JoinHostPort(ip, strconv.FormatInt(port, 10))
+}
+
+func (bs *recoveryBackends) add(backend *recoveryBackend, ips []string) {
+	bs.items = append(bs.items, backend)
+	ports := []int64{}
+	for _, entry := range backend.binding.GetSpec().Ports {
+		if !slices.Contains(ports, int64(entry.Port)) {
+			ports = append(ports, int64(entry.Port))
+		}
+	}
+	for _, ip := range ips {
+		if ip == "" {
+			continue
+		}
+		for _, port := range ports {
+			key := recoveryBackendKey(ip, port)
+			if !slices.Contains(bs.index[key], backend) { // PodIP 与 PodIPs 中的 IP 重复
+				bs.index[key] = append(bs.index[key], backend)
+			}
+		}
+	}
+}
+
+// 查找 rs 对应的 Pod/Node 及其端口映射配置，IP 和端口相同的 Pod/Node 中只能有一个匹配 rs 的协议和端口池
+func (bs *recoveryBackends) match(target clb.Target, protocol string, pools []string) (*recoveryBackend, *networkingv1alpha1.PortEntry, string, error) {
+	candidates := bs.index[recoveryBackendKey(target.TargetIP, target.TargetPort)]
+	if len(candidates) == 0 {
+		return nil, nil, "", fmt.Errorf("no pod or node with port mapping found for target %s", target)
+	}
+	var matched *recoveryBackend
+	var matchedEntry *networkingv1alpha1.PortEntry
+	var matchedPool string
+	names := []string{}
+	for _, backend := range candidates {
+		entry, pool := backend.match(target.TargetPort, protocol, pools)
+		if entry == nil {
+			continue
+		}
+		names = append(names, backend.String())
+		matched, matchedEntry, matchedPool = backend, entry, pool
+	}
+	switch len(names) {
+	case 0:
+		return nil, nil, "", fmt.Errorf("no port mapping of pod or node matches target %s", target)
+	case 1:
+		return matched, matchedEntry, matchedPool, nil
+	default:
+		return nil, nil, "", fmt.Errorf("target %s matches multiple port mappings: %s", target, strings.Join(names, ", "))
+	}
+}
+
+func (b *recoveryBackend) isBound(pool string, port uint16, protocol string) bool {
+	for _, binding := range b.bindings {
+		if binding.Pool == pool && binding.Port == port && binding.Protocol == protocol {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *BindingRecovery) Run(ctx context.Context) (*RecoveryReport, error) {
+	report := &RecoveryReport{}
+	backends, err := r.listBackends(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	// 汇总所有端口池的 CLB（一个 CLB 可能被多个端口池使用）
+	ppl := &networkingv1alpha1.CLBPortPoolList{}
+	if err := r.List(ctx, ppl); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	lbPools := make(map[clb.LBKey][]*networkingv1alpha1.CLBPortPool)
+	lbKeys := []clb.LBKey{}
+	for i := range ppl.Items {
+		pp := &ppl.Items[i]
+		for _, lbStatus := range pp.Status.LoadbalancerStatuses {
+			if lbStatus.State == networkingv1alpha1.LoadBalancerStateNotFound {
+				continue
+			}
+			key := clb.LBKey{LbId: lbStatus.LoadbalancerID, Region: pp.GetRegion()}
+			if _, ok := lbPools[key]; !ok {
+				lbKeys = append(lbKeys, key)
+			}
+			lbPools[key] = append(lbPools[key], pp)
+		}
+	}
+
+	for _, lbKey := range lbKeys {
+		pools := lbPools[lbKey]
+		listeners, err := clb.GetAllListeners(ctx, lbKey.Region, lbKey.LbId)
+		if err != nil {
+			if clb.IsLoadBalancerNotExistsError(errors.Cause(err)) {
+				report.Unmatched = append(report.Unmatched, fmt.Sprintf("lb %s not exists", lbKey.LbId))
+				continue
+			}
+			return nil, errors.WithStack(err)
+		}
+		for _, lis := range listeners {
+			if !clb.IsTkeListener(lis.ListenerName) {
+				continue
+			}
+			// 候选端口池，名称中记录了端口池的监听器只属于该端口池
+			candidates := []*networkingv1alpha1.CLBPortPool{}
+			owner := clb.ParseListenerName(lis.ListenerName)
+			if owner.IsLayer7() { // 七层共享监听器的后端绑定在转发规则上，不在恢复范围内
+				continue
+			}
+			for _, pp := range pools {
+				if owner.IsOwnedByPool(pp.Name) {
+					candidates = append(candidates, pp)
+				}
+			}
+			if len(candidates) == 0 {
+				continue
+			}
+			poolNames := []string{}
+			precreated := false
+			for _, pp := range candidates {
+				poolNames = append(poolNames, pp.Name)
+				if pp.Spec.ListenerPrecreate != nil && pp.Spec.ListenerPrecreate.Enabled {
+					precreated = true
+				}
+			}
+			lisDesc := fmt.Sprintf("listener %s (%s/%d/%s)", lis.ListenerId, lbKey.LbId, lis.Port, lis.Protocol)
+			targets, err := clb.DescribeTargetsTryBatch(ctx, lbKey.Region, lbKey.LbId, lis.ListenerId)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			if len(targets) == 0 {
+				if !precreated { // 预创建的监听器没有 rs 属于正常情况
+					report.Unmatched = append(report.Unmatched, lisDesc+" has no target")
+				}
+				continue
+			}
+			for _, target := range targets {
+				backend, entry, poolName, err := backends.match(*target, lis.Protocol, poolNames)
+				if err != nil {
+					report.Unmatched = append(report.Unmatched, fmt.Sprintf("%s: %s", lisDesc, err.Error()))
+					continue
+				}
+				if backend.isBound(poolName, entry.Port, lis.Protocol) {
+					report.Unmatched = append(report.Unmatched, fmt.Sprintf("%s: port %d/%s of %s is already bound to other listener", lisDesc, entry.Port, lis.Protocol, backend))
+					continue
+				}
+				binding := networkingv1alpha1.PortBindingStatus{
+					Port:             entry.Port,
+					Protocol:         lis.Protocol,
+					Pool:             poolName,
+					LoadbalancerId:   lbKey.LbId,
+					LoadbalancerPort: uint16(lis.Port),
+					ListenerId:       lis.ListenerId,
+					Region:           lbKey.Region,
+				}
+				if lis.EndPort > 0 {
+					endPort := uint16(lis.EndPort)
+					binding.LoadbalancerEndPort = &endPort
+				}
+				for _, pp := range candidates {
+					if pp.Name != poolName {
+						continue
+					}
+					for _, lbStatus := range pp.Status.LoadbalancerStatuses {
+						if lbStatus.LoadbalancerID == lbKey.LbId {
+							binding.AddressIPVersion = lbStatus.AddressIPVersion
+						}
+					}
+				}
+				certs, err := getPortCerts(ctx, r.Client, backend.obj.GetNamespace(), entry)
+				if err != nil {
+					report.Unmatched = append(report.Unmatched, fmt.Sprintf("%s: get cert of %s failed: %s", lisDesc, backend, err.Error()))
+					continue
+				}
+				certs.apply(&binding)
+				backend.bindings = append(backend.bindings, binding)
+				if owner != nil && !slices.Contains(backend.previousUIDs, owner.UID) {
+					backend.previousUIDs = append(backend.previousUIDs, owner.UID)
+				}
+			}
+		}
+	}
+
+	// 重建 CLBBinding
+	for _, backend := range backends.items {
+		if len(backend.bindings) == 0 {
+			continue
+		}
+		if backend.exists {
+			report.Skipped = append(report.Skipped, backend.String()+" already exists")
+			continue
+		}
+		if !r.DryRun {
+			if err := r.createBinding(ctx, backend); err != nil {
+				return report, errors.WithStack(err)
+			}
+		}
+		report.Recovered = append(report.Recovered, fmt.Sprintf("%s with %d port bindings", backend, len(backend.bindings)))
+	}
+	return report, nil
+}
+
+// 列出所有开启了端口映射的 Pod/Node，按 IP 和端口索引
+func (r *BindingRecovery) listBackends(ctx context.Context) (*recoveryBackends, error) {
+	backends := &recoveryBackends{index: make(map[string][]*recoveryBackend)}
+	add := func(obj client.Object, binding clbbinding.CLBBinding, ips []string) error {
+		anno := obj.GetAnnotations()
+		if anno[constant.EnableCLBPortMappingsKey] != "true" || !obj.GetDeletionTimestamp().IsZero() {
+			return nil
+		}
+		spec, err := clbbinding.GenerateSpec(anno)
+		if err != nil {
+			return errors.Wrapf(err, "invalid port mapping annotation of %s", client.ObjectKeyFromObject(obj))
+		}
+		binding.SetName(obj.GetName())
+		binding.SetNamespace(obj.GetNamespace())
+		*binding.GetSpec() = *spec
+		backend := &recoveryBackend{obj: obj, binding: binding}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(binding.GetObject()), binding.GetObject()); err == nil {
+			backend.exists = true
+		} else if !apierrors.IsNotFound(err) {
+			return errors.WithStack(err)
+		}
+		backends.add(backend, ips)
+		return nil
+	}
+
+	pl := &corev1.PodList{}
+	if err := r.List(ctx, pl); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for i := range pl.Items {
+		pod := &pl.Items[i]
+		ips := []string{pod.Status.PodIP}
+		for _, podIP := range pod.Status.PodIPs {
+			ips = append(ips, podIP.IP)
+		}
+		if err := add(pod, clbbinding.NewCLBPodBinding(), ips); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	nl := &corev1.NodeList{}
+	if err := r.List(ctx, nl); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for i := range nl.Items {
+		node := &nl.Items[i]
+		ips := []string{}
+		for _, addr := range node.Status.Addresses {
+			if addr.Type == corev1.NodeInternalIP {
+				ips = append(ips, addr.Address)
+			}
+		}
+		if err := add(node, clbbinding.NewCLBNodeBinding(), ips); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	return backends, nil
+}
+
+// 创建 CLBBinding 并写入匹配到的端口绑定，后续由控制器对账确保监听器和 rs 符合预期
+func (r *BindingRecovery) createBinding(ctx context.Context, backend *recoveryBackend) error {
+	bd := backend.binding.GetObject()
+	// 重建的 CLBBinding 的 UID 与原 CLBBinding 不同，记录原 UID，控制器对账时将监听器名称改为新的 UID
+	if len(backend.previousUIDs) > 0 {
+		bd.SetAnnotations(map[string]string{constant.PreviousUIDsKey: strings.Join(backend.previousUIDs, ",")})
+	}
+	if backend.obj.GetAnnotations()[constant.Ratain] != "true" {
+		if err := controllerutil.SetOwnerReference(backend.obj, bd, r.Scheme()); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if err := r.Create(ctx, bd); err != nil {
+		return errors.WithStack(err)
+	}
+	clbbinding.SortPortBindings(backend.bindings)
+	status := backend.binding.GetStatus()
+	status.PortBindings = backend.bindings
+	status.State = networkingv1alpha1.CLBBindingStateAllocated
+	if err := r.Status().Update(ctx, bd); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/clbbinding"
+	"github.com/tkestack/tke-extend-network-controller/pkg/clb"
+)
+
+func newTestRecoveryBackend(obj client.Object, binding clbbinding.CLBBinding, ports ...networkingv1alpha1.PortEntry) *recoveryBackend {
+	binding.SetNamespace(obj.GetNamespace())
+	binding.SetName(obj.GetName())
+	binding.GetSpec().Ports = ports
+	return &recoveryBackend{obj: obj, binding: binding}
+}
+
+func TestRecoveryBackendsMatch(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gs-0"}}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+	other := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gs-1"}}
+	// hostNetwork 的 Pod 与所在 Node 的 IP 相同
+	podBackend := newTestRecoveryBackend(pod, clbbinding.NewCLBPodBinding(), networkingv1alpha1.PortEntry{Port: 7777, Protocol: "UDP", Pools: []string{"pool-a"}})
+	nodeBackend := newTestRecoveryBackend(node, clbbinding.NewCLBNodeBinding(), networkingv1alpha1.PortEntry{Port: 8888, Protocol: "TCP", Pools: []string{"pool-a"}})
+	otherBackend := newTestRecoveryBackend(other, clbbinding.NewCLBPodBinding(), networkingv1alpha1.PortEntry{Port: 7777, Protocol: "TCP", Pools: []string{"pool-a"}})
+	backends := &recoveryBackends{index: make(map[string][]*recoveryBackend)}
+	backends.add(podBackend, []string{"10.0.0.1", "10.0.0.1"})
+	backends.add(nodeBackend, []string{"10.0.0.1"})
+	backends.add(otherBackend, []string{"10.0.0.1"})
+
+	pools := []string{"pool-a"}
+	if backend, entry, _, err := backends.match(clb.Target{TargetIP: "10.0.0.1", TargetPort: 7777}, "UDP", pools); err != nil || backend != podBackend || entry.Port != 7777 {
+		t.Errorf("expect target matched pod, got %v %v", backend, err)
+	}
+	if backend, _, _, err := backends.match(clb.Target{TargetIP: "10.0.0.1", TargetPort: 8888}, "TCP", pools); err != nil || backend != nodeBackend {
+		t.Errorf("expect target matched node, got %v %v", backend, err)
+	}
+	if backend, _, _, err := backends.match(clb.Target{TargetIP: "10.0.0.1", TargetPort: 7777}, "TCP", pools); err != nil || backend != otherBackend {
+		t.Errorf("expect target matched by protocol, got %v %v", backend, err)
+	}
+	if _, _, _, err := backends.match(clb.Target{TargetIP: "10.0.0.1", TargetPort: 9999}, "TCP", pools); err == nil {
+		t.Error("expect no backend matched")
+	}
+	if _, _, _, err := backends.match(clb.Target{TargetIP: "10.0.0.1", TargetPort: 7777}, "UDP", []string{"pool-b"}); err == nil {
+		t.Error("expect no port mapping matched")
+	}
+}
@@ -0,0 +1,233 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/pkg/errors"
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/clbbinding"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/pkg/clusterinfo"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// 导出文件的版本，文件格式变化时递增
+const Version = "v1"
+
+// State 集群迁移时导出的端口池和端口分配状态
+type State struct {
+	Version    string         `json:"version"`
+	ClusterId  string         `json:"clusterId,omitempty"`
+	ExportTime metav1.Time    `json:"exportTime"`
+	Pools      []PoolState    `json:"pools,omitempty"`
+	Bindings   []BindingState `json:"bindings,omitempty"`
+}
+
+type PoolState struct {
+	Name   string                               `json:"name"`
+	Spec   networkingv1alpha1.CLBPortPoolSpec   `json:"spec"`
+	Status networkingv1alpha1.CLBPortPoolStatus `json:"status,omitempty"`
+}
+
+// BindingState 一个 CLBPodBinding/CLBNodeBinding 的端口分配
+type BindingState struct {
+	// CLBPodBinding 或 CLBNodeBinding
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// 原 CLBBinding 的 UID，监听器名称中记录了该 UID
+	UID          string                                 `json:"uid,omitempty"`
+	Owner        *OwnerIdentity                         `json:"owner,omitempty"`
+	Spec         networkingv1alpha1.CLBBindingSpec      `json:"spec"`
+	PortBindings []networkingv1alpha1.PortBindingStatus `json:"portBindings,omitempty"`
+}
+
+// OwnerIdentity 端口分配所属的 Pod/Node
+type OwnerIdentity struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// Report 记录导入结果
+type Report struct {
+	Imported []string
+	Skipped  []string
+	Failed   []string
+}
+
+// Export 导出当前集群所有端口池的配置和状态，以及所有 CLBBinding 的端口分配
+func Export(ctx context.Context, c client.Client) (*State, error) {
+	state := &State{
+		Version:    Version,
+		ClusterId:  clusterinfo.ClusterId,
+		ExportTime: metav1.Now(),
+	}
+	ppl := &networkingv1alpha1.CLBPortPoolList{}
+	if err := c.List(ctx, ppl); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for _, pp := range ppl.Items {
+		state.Pools = append(state.Pools, PoolState{
+			Name:   pp.Name,
+			Spec:   pp.Spec,
+			Status: pp.Status,
+		})
+	}
+	add := func(bd clbbinding.CLBBinding) {
+		if len(bd.GetStatus().PortBindings) == 0 {
+			return
+		}
+		bs := BindingState{
+			Kind:         bd.GetType(),
+			Namespace:    bd.GetNamespace(),
+			Name:         bd.GetName(),
+			UID:          string(bd.GetUID()),
+			Spec:         *bd.GetSpec(),
+			PortBindings: bd.GetStatus().PortBindings,
+		}
+		if refs := bd.GetOwnerReferences(); len(refs) > 0 {
+			bs.Owner = &OwnerIdentity{Kind: refs[0].Kind, Name: refs[0].Name}
+		}
+		state.Bindings = append(state.Bindings, bs)
+	}
+	pbl := &networkingv1alpha1.CLBPodBindingList{}
+	if err := c.List(ctx, pbl); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for i := range pbl.Items {
+		add(clbbinding.WrapCLBPodBinding(&pbl.Items[i]))
+	}
+	nbl := &networkingv1alpha1.CLBNodeBindingList{}
+	if err := c.List(ctx, nbl); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for i := range nbl.Items {
+		add(clbbinding.WrapCLBNodeBinding(&nbl.Items[i]))
+	}
+	return state, nil
+}
+
+// Import 在新集群中导入端口池和端口分配：
+//  1. 重建端口池并写入端口池状态，自动创建的 CLB 由新集群接管，其它 CLB 作为已有 CLB 纳管；
+//  2. 重建 CLBBinding 并标记为导入的，保留原有的端口分配，控制器启动时会将这些端口标记为已分配，
+//     监听器名称中的原 CLBBinding UID 由控制器对账时改为新的 UID；
+//  3. 同名的 Pod/Node 会直接接管对应的 CLBBinding，其它 Pod/Node 可通过注解认领。
+//
+// 需要在控制器停止运行时执行。
+func Import(ctx context.Context, c client.Client, state *State, dryRun bool) (*Report, error) {
+	if state.Version != Version {
+		return nil, errors.Errorf("unsupported version %q, expect %q", state.Version, Version)
+	}
+	report := &Report{}
+	for _, ps := range state.Pools {
+		desc := "CLBPortPool " + ps.Name
+		if err := importPool(ctx, c, ps, dryRun); err != nil {
+			if apierrors.IsAlreadyExists(errors.Cause(err)) {
+				report.Skipped = append(report.Skipped, desc+" already exists")
+				continue
+			}
+			report.Failed = append(report.Failed, fmt.Sprintf("%s: %s", desc, err.Error()))
+			continue
+		}
+		report.Imported = append(report.Imported, desc)
+	}
+	for _, bs := range state.Bindings {
+		desc := fmt.Sprintf("%s %s", bs.Kind, client.ObjectKey{Namespace: bs.Namespace, Name: bs.Name})
+		if err := importBinding(ctx, c, bs, dryRun); err != nil {
+			if apierrors.IsAlreadyExists(errors.Cause(err)) {
+				report.Skipped = append(report.Skipped, desc+" already exists")
+				continue
+			}
+			report.Failed = append(report.Failed, fmt.Sprintf("%s: %s", desc, err.Error()))
+			continue
+		}
+		report.Imported = append(report.Imported, fmt.Sprintf("%s with %d port bindings", desc, len(bs.PortBindings)))
+	}
+	return report, nil
+}
+
+func importPool(ctx context.Context, c client.Client, ps PoolState, dryRun bool) error {
+	pp := &networkingv1alpha1.CLBPortPool{}
+	pp.Name = ps.Name
+	pp.Spec = *ps.Spec.DeepCopy()
+	status := ps.Status.DeepCopy()
+	// 保留 CLB 是否为自动创建的：自动创建的 CLB 由新集群接管（标签中的集群 ID 会被更新，删除端口池时一并删除），
+	// 不加入 exsistedLoadBalancerIDs，其它 CLB 作为已有 CLB 纳管
+	lbStatuses := []networkingv1alpha1.LoadBalancerStatus{}
+	for _, lbStatus := range status.LoadbalancerStatuses {
+		if lbStatus.State == networkingv1alpha1.LoadBalancerStateNotFound {
+			continue
+		}
+		if !util.GetValue(lbStatus.AutoCreated) && !slices.Contains(pp.Spec.ExsistedLoadBalancerIDs, lbStatus.LoadbalancerID) {
+			pp.Spec.ExsistedLoadBalancerIDs = append(pp.Spec.ExsistedLoadBalancerIDs, lbStatus.LoadbalancerID)
+		}
+		lbStatuses = append(lbStatuses, lbStatus)
+	}
+	status.LoadbalancerStatuses = lbStatuses
+	if dryRun {
+		return nil
+	}
+	if err := c.Create(ctx, pp); err != nil {
+		return errors.WithStack(err)
+	}
+	// 写入 CLB 状态，控制器启动时据此初始化端口分配器，确保导入的端口分配生效
+	pp.Status = *status
+	if err := c.Status().Update(ctx, pp); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func importBinding(ctx context.Context, c client.Client, bs BindingState, dryRun bool) error {
+	var bd clbbinding.CLBBinding
+	var owner client.Object
+	switch bs.Kind {
+	case "CLBPodBinding":
+		bd = clbbinding.NewCLBPodBinding()
+		owner = &corev1.Pod{}
+	case "CLBNodeBinding":
+		bd = clbbinding.NewCLBNodeBinding()
+		owner = &corev1.Node{}
+	default:
+		return errors.Errorf("unknown kind %q", bs.Kind)
+	}
+	bd.SetNamespace(bs.Namespace)
+	bd.SetName(bs.Name)
+	anno := map[string]string{constant.ImportedKey: "true"}
+	// 导入的 CLBBinding 的 UID 与原集群不同，记录原 UID，控制器对账时将监听器名称改为新的 UID
+	if bs.UID != "" {
+		anno[constant.PreviousUIDsKey] = bs.UID
+	}
+	bd.SetAnnotations(anno)
+	*bd.GetSpec() = bs.Spec
+	// 新集群中已有同名 Pod/Node，直接关联
+	if err := c.Get(ctx, client.ObjectKey{Namespace: bs.Namespace, Name: bs.Name}, owner); err == nil {
+		if owner.GetAnnotations()[constant.Ratain] != "true" {
+			if err := controllerutil.SetOwnerReference(owner, bd, c.Scheme()); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+	} else if !apierrors.IsNotFound(err) {
+		return errors.WithStack(err)
+	}
+	if dryRun {
+		return nil
+	}
+	if err := c.Create(ctx, bd.GetObject()); err != nil {
+		return errors.WithStack(err)
+	}
+	status := bd.GetStatus()
+	status.PortBindings = bs.PortBindings
+	status.State = networkingv1alpha1.CLBBindingStateAllocated
+	if err := c.Status().Update(ctx, bd.GetObject()); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package migration
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+)
+
+func newFakeClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := networkingv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithStatusSubresource(
+		&networkingv1alpha1.CLBPortPool{},
+		&networkingv1alpha1.CLBPodBinding{},
+		&networkingv1alpha1.CLBNodeBinding{},
+	).Build()
+}
+
+func newSourceObjects() []client.Object {
+	pool := &networkingv1alpha1.CLBPortPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool"},
+		Spec:       networkingv1alpha1.CLBPortPoolSpec{StartPort: 30000, ExsistedLoadBalancerIDs: []string{"lb-existed"}},
+		Status: networkingv1alpha1.CLBPortPoolStatus{
+			State: networkingv1alpha1.CLBPortPoolStateActive,
+			LoadbalancerStatuses: []networkingv1alpha1.LoadBalancerStatus{
+				{LoadbalancerID: "lb-existed"},
+				{LoadbalancerID: "lb-auto", AutoCreated: util.GetPtr(true)},
+				{LoadbalancerID: "lb-gone", State: networkingv1alpha1.LoadBalancerStateNotFound},
+			},
+		},
+	}
+	portBinding := func(port uint16) []networkingv1alpha1.PortBindingStatus {
+		return []networkingv1alpha1.PortBindingStatus{{Port: port, Protocol: "TCP", Pool: "pool", LoadbalancerId: "lb-auto", LoadbalancerPort: 30000 + port}}
+	}
+	spec := func(port uint16) networkingv1alpha1.CLBBindingSpec {
+		return networkingv1alpha1.CLBBindingSpec{Ports: []networkingv1alpha1.PortEntry{{Port: port, Protocol: "TCP", Pools: []string{"pool"}}}}
+	}
+	pb := &networkingv1alpha1.CLBPodBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default", Name: "pod-0", UID: "old-pod-uid",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "v1", Kind: "Pod", Name: "pod-0", UID: "old-pod"}},
+		},
+		Spec:   spec(80),
+		Status: networkingv1alpha1.CLBBindingStatus{State: networkingv1alpha1.CLBBindingStateBound, PortBindings: portBinding(80)},
+	}
+	nb := &networkingv1alpha1.CLBNodeBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-0", UID: "old-node-uid"},
+		Spec:       spec(81),
+		Status:     networkingv1alpha1.CLBBindingStatus{State: networkingv1alpha1.CLBBindingStateBound, PortBindings: portBinding(81)},
+	}
+	// 没有分配端口的 CLBBinding 不导出
+	empty := &networkingv1alpha1.CLBPodBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-1"}, Spec: spec(82)}
+	return []client.Object{pool, pb, nb, empty}
+}
+
+func TestExportImport(t *testing.T) {
+	ctx := context.Background()
+	state, err := Export(ctx, newFakeClient(t, newSourceObjects()...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Pools) != 1 || len(state.Bindings) != 2 {
+		t.Fatalf("expect 1 pool and 2 bindings exported, got %d pools and %d bindings", len(state.Pools), len(state.Bindings))
+	}
+
+	// 新集群中有同名的 Pod 和 Node，Node 设置了 retain 注解
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-0", UID: "new-pod"}}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-0", UID: "new-node", Annotations: map[string]string{constant.Ratain: "true"}}}
+	c := newFakeClient(t, pod, node)
+	report, err := Import(ctx, c, state, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Imported) != 3 || len(report.Skipped) != 0 || len(report.Failed) != 0 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	// 自动创建的 CLB 由新集群接管，不作为已有 CLB 纳管，已不存在的 CLB 不导入
+	pool := &networkingv1alpha1.CLBPortPool{}
+	if err := c.Get(ctx, client.ObjectKey{Name: "pool"}, pool); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(pool.Spec.ExsistedLoadBalancerIDs, []string{"lb-existed"}) {
+		t.Errorf("expect only lb-existed in exsistedLoadBalancerIDs, got %v", pool.Spec.ExsistedLoadBalancerIDs)
+	}
+	if lbs := pool.Status.LoadbalancerStatuses; len(lbs) != 2 || lbs[1].LoadbalancerID != "lb-auto" || !util.GetValue(lbs[1].AutoCreated) {
+		t.Errorf("unexpected lb statuses: %+v", lbs)
+	}
+
+	pb := &networkingv1alpha1.CLBPodBinding{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "pod-0"}, pb); err != nil {
+		t.Fatal(err)
+	}
+	if pb.Annotations[constant.ImportedKey] != "true" || pb.Annotations[constant.PreviousUIDsKey] != "old-pod-uid" {
+		t.Errorf("unexpected annotations of imported clbpodbinding: %v", pb.Annotations)
+	}
+	if refs := pb.OwnerReferences; len(refs) != 1 || refs[0].UID != types.UID("new-pod") {
+		t.Errorf("expect clbpodbinding owned by new pod, got %+v", refs)
+	}
+	if pb.Status.State != networkingv1alpha1.CLBBindingStateAllocated || len(pb.Status.PortBindings) != 1 || pb.Status.PortBindings[0].LoadbalancerPort != 30080 {
+		t.Errorf("unexpected status of imported clbpodbinding: %+v", pb.Status)
+	}
+	// 设置了 retain 注解的 Node 不关联 CLBNodeBinding
+	nb := &networkingv1alpha1.CLBNodeBinding{}
+	if err := c.Get(ctx, client.ObjectKey{Name: "node-0"}, nb); err != nil {
+		t.Fatal(err)
+	}
+	if len(nb.OwnerReferences) != 0 || nb.Annotations[constant.PreviousUIDsKey] != "old-node-uid" {
+		t.Errorf("unexpected imported clbnodebinding: %+v", nb.ObjectMeta)
+	}
+
+	// 重复导入时跳过已存在的对象
+	report, err = Import(ctx, c, state, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Skipped) != 3 || len(report.Imported) != 0 || len(report.Failed) != 0 {
+		t.Errorf("expect all skipped on second import, got %+v", report)
+	}
+}
+
+func TestImportDryRun(t *testing.T) {
+	ctx := context.Background()
+	state, err := Export(ctx, newFakeClient(t, newSourceObjects()...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newFakeClient(t)
+	report, err := Import(ctx, c, state, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Imported) != 3 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	pools := &networkingv1alpha1.CLBPortPoolList{}
+	if err := c.List(ctx, pools); err != nil {
+		t.Fatal(err)
+	}
+	if len(pools.Items) != 0 {
+		t.Errorf("expect nothing created in dry run, got %d pools", len(pools.Items))
+	}
+	state.Version = "v0"
+	if _, err := Import(ctx, c, state, true); err == nil {
+		t.Error("expect error for unsupported version")
+	}
+}
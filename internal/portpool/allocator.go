@@ -139,6 +139,7 @@ func (pa *PortAllocator) EnsurePool(pool *networkingv1alpha1.CLBPortPool) (added
 			}
 		}
 	}
+	p.listenerPrecreate = pool.Spec.ListenerPrecreate.DeepCopy()
 	if lcp := pool.Spec.ListenerPrecreate; lcp != nil && lcp.Enabled {
 		p.maxPort = &MaxPort{}
 		startPort := pool.Spec.StartPort
@@ -184,6 +185,19 @@ func (pa *PortAllocator) Allocate(ctx context.Context, pools []string, protocol
 	}
 }
 
+// AllocateLayer7 在每个端口池中为七层转发规则分配共享监听器
+func (pa *PortAllocator) AllocateLayer7(ctx context.Context, pools []string, protocol string) (PortAllocations, error) {
+	portPools, err := pa.getPortPools(pools)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if ports, err := portPools.AllocateLayer7(ctx, protocol); err != nil {
+		return nil, errors.WithStack(err)
+	} else {
+		return ports, nil
+	}
+}
+
 func (pa *PortAllocator) ReleaseBinding(binding *networkingv1alpha1.PortBindingStatus) bool {
 	return pa.Release(binding.Pool, NewLBKeyFromBinding(binding), NewProtocolPortFromBinding(binding))
 }
@@ -196,6 +210,14 @@ func (pa *PortAllocator) Release(pool string, lbKey LBKey, port ProtocolPort) bo
 	return false
 }
 
+// IsAllocated 判断端口是否已被分配
+func (pa *PortAllocator) IsAllocated(pool string, lbKey LBKey, port ProtocolPort) bool {
+	if pp := pa.GetPool(pool); pp != nil {
+		return pp.IsAllocated(lbKey, port)
+	}
+	return false
+}
+
 func (pa *PortAllocator) IsLbExists(pool string, lbKey LBKey) bool {
 	if pp := pa.GetPool(pool); pp != nil {
 		return pp.IsLbExists(lbKey)
@@ -212,6 +234,10 @@ func (pa *PortAllocator) RemoveLB(pool string, lbKey LBKey) bool {
 
 var Allocator = NewPortAllocator()
 
+// DedicatedAllocator DedicatedCLBService 使用的端口分配器，每个 DedicatedCLBService 对应一个端口池，
+// 与 CLBPortPool 的分配器隔离，避免出现在端口池的分配、扩容等流程中
+var DedicatedAllocator = NewPortAllocator()
+
 func (pa *PortAllocator) MarkAllocated(poolName string, lbKey LBKey, port uint16, endPort *uint16, protocol string) {
 	pa.mu.Lock()
 	defer pa.mu.Unlock()
@@ -225,6 +251,6 @@ func (pa *PortAllocator) MarkAllocated(poolName string, lbKey LBKey, port uint16
 		finalEndPort = *endPort
 	}
 	if lb := pool.cache[lbKey]; lb != nil {
-		lb[ProtocolPort{Port: port, EndPort: finalEndPort, Protocol: protocol}] = struct{}{}
+		lb[ProtocolPort{Port: port, EndPort: finalEndPort, Protocol: protocol}.Key()] = struct{}{}
 	}
 }
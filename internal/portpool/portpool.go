@@ -11,6 +11,7 @@ import (
 
 	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
 	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 )
@@ -26,7 +27,7 @@ type LBPort struct {
 type ProtocolPort struct {
 	Port     uint16 // 端口号
 	EndPort  uint16 // 结束端口号
-	Protocol string // 协议 TCP/UDP/QUIC/TCP_SSL
+	Protocol string // 协议 TCP/UDP/QUIC/TCP_SSL/HTTP/HTTPS
 }
 
 func (p ProtocolPort) Key() ProtocolPort {
@@ -36,6 +37,8 @@ func (p ProtocolPort) Key() ProtocolPort {
 		l4Protocol = "TCP"
 	case "QUIC":
 		l4Protocol = "UDP"
+	case constant.ProtocolHTTP, constant.ProtocolHTTPS:
+		l4Protocol = "TCP"
 	}
 	return ProtocolPort{
 		Port:     p.Port,
@@ -142,6 +145,7 @@ type PortPool struct {
 	LbBlacklist          map[LBKey]struct{}
 	lbBlacklist          []string
 	maxPort              *MaxPort
+	listenerPrecreate    *networkingv1alpha1.ListenerPrecreateConfig
 	scaleUpRequested     atomic.Bool // 是否有扩容请求
 	scaleUpJustCompleted atomic.Bool // 是否刚完成扩容（用于吸收一轮分配失败）
 	mu                   sync.Mutex
@@ -155,21 +159,9 @@ func (pp *PortPool) IsPrecreateListenerEnabled() bool {
 
 // IsProtocolPrecreated 判断指定协议是否预创建了监听器。
 // 预创建模式下，绑定只能复用预创建的监听器，不能再动态创建。
-// TCPUDP 协议同时需要 TCP 和 UDP 监听器，两者都预创建了才算预创建。
 func (pp *PortPool) IsProtocolPrecreated(protocol string) bool {
-	if pp.maxPort == nil {
-		return true // 未启用预创建，不限制
-	}
-	switch protocol {
-	case constant.ProtocolTCP:
-		return pp.maxPort.Tcp > 0
-	case constant.ProtocolUDP:
-		return pp.maxPort.Udp > 0
-	case constant.ProtocolTCPUDP:
-		return pp.maxPort.Tcp > 0 && pp.maxPort.Udp > 0
-	default: // TCP_SSL / QUIC 等预创建不支持的协议
-		return false
-	}
+	spec := networkingv1alpha1.CLBPortPoolSpec{ListenerPrecreate: pp.listenerPrecreate}
+	return spec.IsProtocolPrecreated(protocol)
 }
 
 func (pp *PortPool) getCache() iter.Seq2[LBKey, map[ProtocolPort]struct{}] {
@@ -329,6 +321,26 @@ func (pp *PortPool) AllocatePort(ctx context.Context, quota int64, port, endPort
 	return nil, quotaExceeded
 }
 
+// 为七层转发规则分配共享监听器：按 lb 分配策略选择 lb，已创建了共享监听器的 lb 直接复用，否则需要 lb 还有监听器配额，
+// 并将共享监听器占用的端口标记为已分配
+func (pp *PortPool) AllocateLayer7(ctx context.Context, quota uint16, port ProtocolPort) ([]PortAllocation, bool) {
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+	if len(pp.cache) == 0 {
+		return nil, true
+	}
+	for lbKey, allocated := range pp.getCache() {
+		if _, exists := allocated[port.Key()]; !exists {
+			if uint16(len(allocated)+1) > quota { // 监听器数量已满，换下个 lb
+				continue
+			}
+			allocated[port.Key()] = struct{}{}
+		}
+		return []PortAllocation{{PortPool: pp, ProtocolPort: port, LBKey: lbKey}}, false
+	}
+	return nil, true
+}
+
 func (pp *PortPool) RemoveLB(lbKey LBKey) bool {
 	pp.mu.Lock()
 	defer pp.mu.Unlock()
@@ -355,10 +367,25 @@ func (pp *PortPool) ReleasePort(lbKey LBKey, port ProtocolPort) bool {
 	if !exists {
 		return false
 	}
+	if util.IsLayer7Protocol(port.Protocol) { // 七层共享监听器被多个转发规则共用，不随单个转发规则释放
+		return false
+	}
 	delete(cache, port.Key())
 	return true
 }
 
+// 判断端口是否已被分配
+func (pp *PortPool) IsAllocated(lbKey LBKey, port ProtocolPort) bool {
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+	cache, exists := pp.cache[lbKey]
+	if !exists {
+		return false
+	}
+	_, allocated := cache[port.Key()]
+	return allocated
+}
+
 func (pp *PortPool) AllocatedPorts(lbKey LBKey) uint16 {
 	pp.mu.Lock()
 	defer pp.mu.Unlock()
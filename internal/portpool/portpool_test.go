@@ -1,6 +1,7 @@
 package portpool
 
 import (
+	"context"
 	"sync"
 	"testing"
 )
@@ -89,3 +90,40 @@ func TestRequestScaleUp(t *testing.T) {
 		}
 	})
 }
+
+func TestAllocateLayer7(t *testing.T) {
+	lb1 := NewLBKey("lb-1", "ap-guangzhou")
+	lb2 := NewLBKey("lb-2", "ap-guangzhou")
+	https := ProtocolPort{Port: 443, Protocol: "HTTPS"}
+	pp := &PortPool{
+		Name:     "test-pool",
+		LbPolicy: "InOrder",
+		cache: map[LBKey]map[ProtocolPort]struct{}{
+			lb1: {{Port: 30000, Protocol: "TCP"}: {}, {Port: 30001, Protocol: "TCP"}: {}},
+			lb2: {},
+		},
+		lbList: []LBKey{lb1, lb2},
+	}
+	// lb-1 监听器数量已满，分配到 lb-2 并占用共享监听器端口
+	result, quotaExceeded := pp.AllocateLayer7(context.Background(), 2, https)
+	if quotaExceeded || len(result) != 1 || result[0].LBKey != lb2 {
+		t.Fatalf("expect allocated on lb-2, got %v (quotaExceeded=%v)", result, quotaExceeded)
+	}
+	if _, ok := pp.cache[lb2][https.Key()]; !ok {
+		t.Error("expect shared listener port marked as allocated")
+	}
+	// lb-2 监听器数量已满，但已有共享监听器，继续复用
+	pp.cache[lb2][ProtocolPort{Port: 30000, Protocol: "TCP"}] = struct{}{}
+	result, quotaExceeded = pp.AllocateLayer7(context.Background(), 2, https)
+	if quotaExceeded || len(result) != 1 || result[0].LBKey != lb2 {
+		t.Fatalf("expect shared listener on lb-2 reused, got %v (quotaExceeded=%v)", result, quotaExceeded)
+	}
+	// 释放转发规则不释放共享监听器
+	if pp.ReleasePort(lb2, https) {
+		t.Error("shared listener should not be released")
+	}
+	// 没有 lb 能创建 HTTP 共享监听器
+	if _, quotaExceeded := pp.AllocateLayer7(context.Background(), 2, ProtocolPort{Port: 80, Protocol: "HTTP"}); !quotaExceeded {
+		t.Error("expect quota exceeded")
+	}
+}
@@ -99,6 +99,7 @@ var (
 	ErrQuotaNotEqual          = errors.New("quota not equal")
 	ErrQuotaNotFound          = errors.New("quota not found")
 	ErrPortPoolNotAllocatable = errors.New("port pool not allocatable")
+	ErrLayer7NotConfigured    = errors.New("layer7 not configured in port pool")
 )
 
 func portsToAllocate(port, endPort uint16, protocol string) (ports []ProtocolPort) {
@@ -181,3 +182,30 @@ func (pp PortPools) AllocatePort(ctx context.Context, protocol string, useSamePo
 	}
 	return ports, nil
 }
+
+// 从一个或多个端口池中为七层转发规则分配共享监听器，每个端口池分配一个，任一端口池无法分配时返回空结果
+// （共享监听器被多个转发规则共用，已分配的不需要释放）
+func (pp PortPools) AllocateLayer7(ctx context.Context, protocol string) (ports PortAllocations, err error) {
+	for _, portPool := range pp {
+		cpp, err := kube.GetCLBPortPool(ctx, portPool.Name)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if cpp.Status.State != networkingv1alpha1.CLBPortPoolStateActive {
+			return nil, ErrPortPoolNotAllocatable
+		}
+		if cpp.Spec.Layer7 == nil {
+			return nil, errors.Wrapf(ErrLayer7NotConfigured, "port pool %s", portPool.Name)
+		}
+		if cpp.Status.Quota == 0 {
+			return nil, ErrQuotaNotFound
+		}
+		port := ProtocolPort{Port: cpp.Spec.Layer7.GetListenerPort(protocol), Protocol: protocol}
+		result, quotaExceeded := portPool.AllocateLayer7(ctx, cpp.Status.Quota, port)
+		if quotaExceeded || len(result) == 0 {
+			return nil, nil
+		}
+		ports = append(ports, result...)
+	}
+	return ports, nil
+}
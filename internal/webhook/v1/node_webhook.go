@@ -0,0 +1,95 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var nodelog = logf.Log.WithName("node-resource")
+
+// SetupNodeWebhookWithManager registers the webhook for Node in the manager.
+func SetupNodeWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &corev1.Node{}).
+		WithCustomValidator(&NodeCustomValidator{Client: mgr.GetClient()}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate--v1-node,mutating=false,failurePolicy=ignore,sideEffects=None,groups="",resources=nodes,verbs=create;update,versions=v1,name=vnode-v1.kb.io,admissionReviewVersions=v1
+
+// NodeCustomValidator 校验 Node 的 CLB 端口映射注解，与 Pod 使用同样的校验逻辑，但 Node 不支持引用证书 secret。
+type NodeCustomValidator struct {
+	Client client.Client
+}
+
+var _ webhook.CustomValidator = &NodeCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type Node.
+func (v *NodeCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		return nil, fmt.Errorf("expected a Node object but got %T", obj)
+	}
+	return nil, v.validate(ctx, node)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type Node.
+func (v *NodeCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldNode, ok := oldObj.(*corev1.Node)
+	if !ok {
+		return nil, fmt.Errorf("expected a Node object for the oldObj but got %T", oldObj)
+	}
+	node, ok := newObj.(*corev1.Node)
+	if !ok {
+		return nil, fmt.Errorf("expected a Node object for the newObj but got %T", newObj)
+	}
+	// kubelet 会频繁更新 Node，仅在端口映射相关注解变化时校验
+	if !portMappingAnnotationsChanged(oldNode.GetAnnotations(), node.GetAnnotations()) {
+		return nil, nil
+	}
+	return nil, v.validate(ctx, node)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type Node.
+func (v *NodeCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *NodeCustomValidator) validate(ctx context.Context, node *corev1.Node) error {
+	allErrs, err := validatePortMappings(ctx, v.Client, "", node.GetAnnotations())
+	if err != nil {
+		return err
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+	nodelog.V(3).Info("reject node with invalid clb port mapping", "name", node.GetName(), "errors", allErrs.ToAggregate().Error())
+	return apierrors.NewInvalid(schema.GroupKind{Kind: "Node"}, node.GetName(), allErrs)
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var podlog = logf.Log.WithName("pod-resource")
+
+// SetupPodWebhookWithManager registers the webhook for Pod in the manager.
+func SetupPodWebhookWithManager(mgr ctrl.Manager, schedulingGate bool) error {
+	return ctrl.NewWebhookManagedBy(mgr, &corev1.Pod{}).
+		WithCustomDefaulter(&PodCustomDefaulter{SchedulingGate: schedulingGate}).
+		WithCustomValidator(&PodCustomValidator{Client: mgr.GetClient()}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate--v1-pod,mutating=true,failurePolicy=ignore,sideEffects=None,groups="",resources=pods,verbs=create,versions=v1,name=mpod-v1.kb.io,admissionReviewVersions=v1
+
+// PodCustomDefaulter 为启用了 CLB 端口映射的 Pod 注入：
+//  1. networking.cloud.tencent.com/clb-bound readiness gate（需声明 networking.cloud.tencent.com/inject-clb-readiness-gate 注解），
+//     让 Pod 在 CLB 映射生效后才 Ready。
+//  2. networking.cloud.tencent.com/clb-port-allocated scheduling gate（需启用 SchedulingGate），让 Pod 在分配到 CLB 端口后才被调度，
+//     避免端口池端口不足时 Pod 仍被调度和启动，白白占用节点资源。
+type PodCustomDefaulter struct {
+	SchedulingGate bool
+}
+
+var _ webhook.CustomDefaulter = &PodCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind Pod.
+func (d *PodCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return fmt.Errorf("expected a Pod object but got %T", obj)
+	}
+	anno := pod.GetAnnotations()
+	if anno[constant.EnableCLBPortMappingsKey] != "true" {
+		return nil
+	}
+	if anno[constant.InjectCLBReadinessGateKey] == "true" && !util.HasReadinessGate(pod, constant.CLBBoundConditionType) {
+		podlog.V(3).Info("inject clb readiness gate", "namespace", pod.GetNamespace(), "name", pod.GetName(), "generateName", pod.GetGenerateName())
+		pod.Spec.ReadinessGates = append(pod.Spec.ReadinessGates, corev1.PodReadinessGate{
+			ConditionType: constant.CLBBoundConditionType,
+		})
+	}
+	// 已经调度的 Pod（指定了 nodeName）不能再添加 scheduling gate
+	if d.SchedulingGate && pod.Spec.NodeName == "" && !util.HasSchedulingGate(pod, constant.CLBPortSchedulingGate) {
+		podlog.V(3).Info("inject clb scheduling gate", "namespace", pod.GetNamespace(), "name", pod.GetName(), "generateName", pod.GetGenerateName())
+		pod.Spec.SchedulingGates = append(pod.Spec.SchedulingGates, corev1.PodSchedulingGate{
+			Name: constant.CLBPortSchedulingGate,
+		})
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate--v1-pod,mutating=false,failurePolicy=ignore,sideEffects=None,groups="",resources=pods,verbs=create;update,versions=v1,name=vpod-v1.kb.io,admissionReviewVersions=v1
+
+// PodCustomValidator 校验 Pod 的 CLB 端口映射注解，在创建时就拒绝无效的映射，
+// 避免 Pod 创建后才在 CLBPodBinding 的事件中发现配置错误。
+type PodCustomValidator struct {
+	Client client.Client
+}
+
+var _ webhook.CustomValidator = &PodCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type Pod.
+func (v *PodCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil, fmt.Errorf("expected a Pod object but got %T", obj)
+	}
+	return nil, v.validate(ctx, pod)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type Pod.
+func (v *PodCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldPod, ok := oldObj.(*corev1.Pod)
+	if !ok {
+		return nil, fmt.Errorf("expected a Pod object for the oldObj but got %T", oldObj)
+	}
+	pod, ok := newObj.(*corev1.Pod)
+	if !ok {
+		return nil, fmt.Errorf("expected a Pod object for the newObj but got %T", newObj)
+	}
+	// 注解未变化时不校验，避免端口池等外部资源变化后阻塞 Pod 的其它更新（如 status、label）
+	if !portMappingAnnotationsChanged(oldPod.GetAnnotations(), pod.GetAnnotations()) {
+		return nil, nil
+	}
+	return nil, v.validate(ctx, pod)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type Pod.
+func (v *PodCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *PodCustomValidator) validate(ctx context.Context, pod *corev1.Pod) error {
+	allErrs, err := validatePortMappings(ctx, v.Client, pod.GetNamespace(), pod.GetAnnotations())
+	if err != nil {
+		return err
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+	name := pod.GetName()
+	if name == "" {
+		name = pod.GetGenerateName()
+	}
+	podlog.V(3).Info("reject pod with invalid clb port mapping", "namespace", pod.GetNamespace(), "name", name, "errors", allErrs.ToAggregate().Error())
+	return apierrors.NewInvalid(schema.GroupKind{Kind: "Pod"}, name, allErrs)
+}
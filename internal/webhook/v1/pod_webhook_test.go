@@ -0,0 +1,150 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPodDefaulterInjectReadinessGate(t *testing.T) {
+	d := &PodCustomDefaulter{}
+	newPod := func(anno map[string]string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test", Annotations: anno}}
+	}
+	// 没有声明注入注解，不注入
+	pod := newPod(map[string]string{constant.EnableCLBPortMappingsKey: "true"})
+	if err := d.Default(context.Background(), pod); err != nil {
+		t.Fatal(err)
+	}
+	if len(pod.Spec.ReadinessGates) != 0 {
+		t.Errorf("expect no readiness gate, got %v", pod.Spec.ReadinessGates)
+	}
+	// 注入且不重复注入
+	pod = newPod(map[string]string{
+		constant.EnableCLBPortMappingsKey:  "true",
+		constant.InjectCLBReadinessGateKey: "true",
+	})
+	for range 2 {
+		if err := d.Default(context.Background(), pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(pod.Spec.ReadinessGates) != 1 || pod.Spec.ReadinessGates[0].ConditionType != constant.CLBBoundConditionType {
+		t.Errorf("unexpected readiness gates %v", pod.Spec.ReadinessGates)
+	}
+}
+
+func TestPodDefaulterInjectSchedulingGate(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:        "test",
+		Annotations: map[string]string{constant.EnableCLBPortMappingsKey: "true"},
+	}}
+	// 未启用 scheduling gate，不注入
+	if err := (&PodCustomDefaulter{}).Default(context.Background(), pod); err != nil {
+		t.Fatal(err)
+	}
+	if len(pod.Spec.SchedulingGates) != 0 {
+		t.Errorf("expect no scheduling gate, got %v", pod.Spec.SchedulingGates)
+	}
+	d := &PodCustomDefaulter{SchedulingGate: true}
+	for range 2 {
+		if err := d.Default(context.Background(), pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(pod.Spec.SchedulingGates) != 1 || pod.Spec.SchedulingGates[0].Name != constant.CLBPortSchedulingGate {
+		t.Errorf("unexpected scheduling gates %v", pod.Spec.SchedulingGates)
+	}
+	// 已调度的 Pod 不注入
+	pod = &corev1.Pod{ObjectMeta: pod.ObjectMeta, Spec: corev1.PodSpec{NodeName: "node1"}}
+	if err := d.Default(context.Background(), pod); err != nil {
+		t.Fatal(err)
+	}
+	if len(pod.Spec.SchedulingGates) != 0 {
+		t.Errorf("expect no scheduling gate for scheduled pod, got %v", pod.Spec.SchedulingGates)
+	}
+}
+
+func TestPodValidatorPortMappings(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = networkingv1alpha1.AddToScheme(scheme)
+	apiClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&networkingv1alpha1.CLBPortPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "pool-precreate"},
+			Spec: networkingv1alpha1.CLBPortPoolSpec{
+				StartPort:         30000,
+				ListenerPrecreate: &networkingv1alpha1.ListenerPrecreateConfig{Enabled: true, TCP: util.GetPtr(uint16(10))},
+			},
+		},
+		&networkingv1alpha1.CLBPortPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "pool-a"},
+			Spec:       networkingv1alpha1.CLBPortPoolSpec{StartPort: 30000},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cert"},
+			Data:       map[string][]byte{"qcloud_cert_id": []byte("abc")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "tls-cert"},
+			Type:       corev1.SecretTypeTLS,
+			Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte("key")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ca-cert"},
+			Data:       map[string][]byte{"ca.crt": []byte("ca")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "empty"},
+		},
+	).Build()
+	v := &PodCustomValidator{Client: apiClient}
+	newPod := func(mapping string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test", Annotations: map[string]string{
+			constant.EnableCLBPortMappingsKey: "true",
+			constant.CLBPortMappingsKey:       mapping,
+		}}}
+	}
+	tests := []struct {
+		mapping string
+		valid   bool
+	}{
+		{"80 TCP pool-precreate", true},
+		{"443 TCP_SSL pool-precreate certSecret=cert", false}, // 预创建模式不支持 TCP_SSL
+		{"80 UDP pool-precreate", false},
+		{"80 TCP pool-not-exist", false},
+		{"80 SCTP pool-precreate", false},
+		{"80 TCP pool-precreate useSamePort", false},
+		{"80 TCP pool-precreate healthCheck=off", true},
+		{"443 TCP_SSL pool-a certSecret=cert", true},
+		{"443 TCP_SSL pool-a certSecret=tls-cert", true},
+		{"443 TCP_SSL pool-a certSecret=empty", false},
+		{"443 TCP_SSL pool-a certSecret=cert,extraCertSecret=tls-cert,caCertSecret=ca-cert", true},
+		{"443 TCP_SSL pool-a certSecret=cert,extraCertSecret=empty", false},
+		{"443 TCP_SSL pool-a certSecret=cert,caCertSecret=cert", false}, // Secret 中没有 CA 证书
+		{"443 TCP_SSL pool-a caCertSecret=ca-cert", false},
+		{"80 TCP pool-a certSecret=cert,caCertSecret=ca-cert", false},
+	}
+	for _, tt := range tests {
+		_, err := v.ValidateCreate(context.Background(), newPod(tt.mapping))
+		if tt.valid && err != nil {
+			t.Errorf("mapping %q: unexpected error %v", tt.mapping, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("mapping %q: expect error", tt.mapping)
+		}
+	}
+	// 端口映射注解未变化时更新不校验
+	pod := newPod("80 TCP pool-not-exist")
+	if _, err := v.ValidateUpdate(context.Background(), pod, pod.DeepCopy()); err != nil {
+		t.Errorf("unexpected error on unchanged update: %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/clbbinding"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/pkg/kube"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+)
+
+var annotationsPath = field.NewPath("metadata").Child("annotations")
+
+// portMappingAnnotations 影响 CLB 端口映射的注解，更新时仅当这些注解变化才做校验
+var portMappingAnnotations = []string{
+	constant.EnableCLBPortMappingsKey,
+	constant.CLBPortMappingsKey,
+	constant.CLBWaitBackendReadyKey,
+	constant.CLBDrainSecondsKey,
+}
+
+func portMappingAnnotationsChanged(oldAnno, newAnno map[string]string) bool {
+	for _, key := range portMappingAnnotations {
+		if oldAnno[key] != newAnno[key] {
+			return true
+		}
+	}
+	return false
+}
+
+// validatePortMappings 校验 Pod/Node 上的 CLB 端口映射注解，与控制器使用同一个解析器，
+// 并检查引用的端口池是否存在、协议是否与端口池的监听器预创建配置兼容、证书 secret 是否存在。
+// namespace 为空表示校验的是 Node，Node 不支持引用证书 secret。
+func validatePortMappings(ctx context.Context, apiClient client.Client, namespace string, anno map[string]string) (field.ErrorList, error) {
+	var allErrs field.ErrorList
+	if anno[constant.EnableCLBPortMappingsKey] != "true" {
+		return nil, nil
+	}
+	mappingPath := annotationsPath.Key(constant.CLBPortMappingsKey)
+	ports, err := clbbinding.ParsePortMappingsStrict(anno[constant.CLBPortMappingsKey])
+	if err != nil {
+		return append(allErrs, field.Invalid(mappingPath, anno[constant.CLBPortMappingsKey], err.Error())), nil
+	}
+	// 端口映射解析通过后，其余注解（如摘流时长）的错误由 GenerateSpec 报出
+	if _, err := clbbinding.GenerateSpec(anno); err != nil {
+		allErrs = append(allErrs, field.Invalid(annotationsPath.Key(constant.CLBDrainSecondsKey), anno[constant.CLBDrainSecondsKey], err.Error()))
+	}
+	if len(ports) == 0 {
+		return append(allErrs, field.Required(mappingPath, "port mapping is required when clb port mapping is enabled")), nil
+	}
+	pools := make(map[string]*networkingv1alpha1.CLBPortPool)
+	for i, port := range ports {
+		entry := fmt.Sprintf("%d %s", port.Port, port.Protocol)
+		for _, poolName := range port.Pools {
+			pool, ok := pools[poolName]
+			if !ok {
+				pool = &networkingv1alpha1.CLBPortPool{}
+				if err := apiClient.Get(ctx, client.ObjectKey{Name: poolName}, pool); err != nil {
+					if !apierrors.IsNotFound(err) {
+						return nil, errors.WithStack(err)
+					}
+					pool = nil
+				}
+				pools[poolName] = pool
+			}
+			if pool == nil {
+				allErrs = append(allErrs, field.NotFound(mappingPath.Index(i), fmt.Sprintf("port pool %q referenced by %q", poolName, entry)))
+				continue
+			}
+			if !pool.Spec.IsProtocolPrecreated(port.Protocol) {
+				allErrs = append(allErrs, field.Invalid(mappingPath.Index(i), entry, fmt.Sprintf(
+					"protocol %s is not precreated in port pool %s, precreate mode only allows reusing precreated listeners",
+					port.Protocol, poolName,
+				)))
+			}
+			if util.IsLayer7Protocol(port.Protocol) {
+				if pool.Spec.Layer7 == nil {
+					allErrs = append(allErrs, field.Invalid(mappingPath.Index(i), entry, fmt.Sprintf("protocol %s requires layer7 config in port pool %s", port.Protocol, poolName)))
+				} else if port.Protocol == constant.ProtocolHTTPS && util.GetValue(pool.Spec.Layer7.CertId) == "" {
+					allErrs = append(allErrs, field.Invalid(mappingPath.Index(i), entry, fmt.Sprintf("protocol %s requires layer7.certId in port pool %s", port.Protocol, poolName)))
+				}
+			}
+		}
+		secretName := util.GetValue(port.CertSecretName)
+		if secretName == "" {
+			if port.Protocol == "TCP_SSL" || port.Protocol == "QUIC" {
+				allErrs = append(allErrs, field.Required(mappingPath.Index(i), fmt.Sprintf("certSecret is required for protocol %s", port.Protocol)))
+			} else if len(port.ExtraCertSecretNames) > 0 || util.GetValue(port.CACertSecretName) != "" {
+				allErrs = append(allErrs, field.Invalid(mappingPath.Index(i), entry, "extraCertSecret and caCertSecret require certSecret"))
+			}
+			continue
+		}
+		if namespace == "" {
+			allErrs = append(allErrs, field.Forbidden(mappingPath.Index(i), "certSecret is not supported on Node"))
+			continue
+		}
+		if port.Protocol != "TCP_SSL" && port.Protocol != "QUIC" && (len(port.ExtraCertSecretNames) > 0 || util.GetValue(port.CACertSecretName) != "") {
+			allErrs = append(allErrs, field.Invalid(mappingPath.Index(i), entry, fmt.Sprintf("extraCertSecret and caCertSecret are not supported for protocol %s", port.Protocol)))
+			continue
+		}
+		// 只校验 Secret 中包含证书，kubernetes.io/tls 类型 Secret 中的证书和 ca.crt 中的 CA 证书由控制器在分配端口时上传
+		for _, name := range append([]string{secretName}, port.ExtraCertSecretNames...) {
+			secret := &corev1.Secret{}
+			if err := apiClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, secret); err != nil {
+				if !apierrors.IsNotFound(err) {
+					return nil, err
+				}
+				allErrs = append(allErrs, field.NotFound(mappingPath.Index(i), fmt.Sprintf("cert secret %s/%s", namespace, name)))
+			} else if !kube.HasCert(secret) {
+				allErrs = append(allErrs, field.Invalid(mappingPath.Index(i), name, fmt.Sprintf("cert secret %s/%s has no qcloud_cert_id and is not a kubernetes.io/tls secret", namespace, name)))
+			}
+		}
+		if caSecretName := util.GetValue(port.CACertSecretName); caSecretName != "" {
+			secret := &corev1.Secret{}
+			if err := apiClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: caSecretName}, secret); err != nil {
+				if !apierrors.IsNotFound(err) {
+					return nil, err
+				}
+				allErrs = append(allErrs, field.NotFound(mappingPath.Index(i), fmt.Sprintf("ca cert secret %s/%s", namespace, caSecretName)))
+			} else if !kube.HasCACert(secret) {
+				allErrs = append(allErrs, field.Invalid(mappingPath.Index(i), caSecretName, fmt.Sprintf("ca cert secret %s/%s has no qcloud_ca_cert_id or ca.crt", namespace, caSecretName)))
+			}
+		}
+	}
+	return allErrs, nil
+}
@@ -0,0 +1,233 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/clbbinding"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/pkg/clb"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var clbbindinglog = logf.Log.WithName("clbbinding-resource")
+
+// SetupCLBPodBindingWebhookWithManager registers the webhook for CLBPodBinding in the manager.
+func SetupCLBPodBindingWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &networkingv1alpha1.CLBPodBinding{}).
+		WithCustomValidator(&CLBBindingCustomValidator{Client: mgr.GetClient()}).
+		Complete()
+}
+
+// SetupCLBNodeBindingWebhookWithManager registers the webhook for CLBNodeBinding in the manager.
+func SetupCLBNodeBindingWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &networkingv1alpha1.CLBNodeBinding{}).
+		WithCustomValidator(&CLBBindingCustomValidator{Client: mgr.GetClient()}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-networking-cloud-tencent-com-v1alpha1-clbpodbinding,mutating=false,failurePolicy=fail,sideEffects=None,groups=networking.cloud.tencent.com,resources=clbpodbindings,verbs=create;update,versions=v1alpha1,name=vclbpodbinding-v1alpha1.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-networking-cloud-tencent-com-v1alpha1-clbnodebinding,mutating=false,failurePolicy=fail,sideEffects=None,groups=networking.cloud.tencent.com,resources=clbnodebindings,verbs=create;update,versions=v1alpha1,name=vclbnodebinding-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// CLBBindingCustomValidator 校验 CLBPodBinding 和 CLBNodeBinding，两者的 spec 相同，共用校验逻辑。
+type CLBBindingCustomValidator struct {
+	Client client.Client
+}
+
+var _ webhook.CustomValidator = &CLBBindingCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type CLBPodBinding and CLBNodeBinding.
+func (v *CLBBindingCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	bd, err := wrapCLBBinding(obj)
+	if err != nil {
+		return nil, err
+	}
+	allErrs, err := validateCLBBindingSpec(ctx, v.Client, bd.GetSpec())
+	if err != nil {
+		return nil, err
+	}
+	return nil, toInvalidError(bd, allErrs)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type CLBPodBinding and CLBNodeBinding.
+func (v *CLBBindingCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldBd, err := wrapCLBBinding(oldObj)
+	if err != nil {
+		return nil, err
+	}
+	bd, err := wrapCLBBinding(newObj)
+	if err != nil {
+		return nil, err
+	}
+	allErrs := validateCLBBindingUpdate(oldBd, bd)
+	// spec 未变化时（如控制器更新 finalizer），不重复校验 spec，避免端口池变化后阻塞已有 CLBBinding 的更新
+	if !equality.Semantic.DeepEqual(oldBd.GetSpec(), bd.GetSpec()) {
+		specErrs, err := validateCLBBindingSpec(ctx, v.Client, bd.GetSpec())
+		if err != nil {
+			return nil, err
+		}
+		allErrs = append(allErrs, specErrs...)
+	}
+	return nil, toInvalidError(bd, allErrs)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type CLBPodBinding and CLBNodeBinding.
+func (v *CLBBindingCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func wrapCLBBinding(obj runtime.Object) (clbbinding.CLBBinding, error) {
+	o, ok := obj.(client.Object)
+	if !ok {
+		return nil, fmt.Errorf("expected a CLBPodBinding or CLBNodeBinding object but got %T", obj)
+	}
+	bd := clbbinding.Wrap(o)
+	if bd == nil {
+		return nil, fmt.Errorf("expected a CLBPodBinding or CLBNodeBinding object but got %T", obj)
+	}
+	return bd, nil
+}
+
+func toInvalidError(bd clbbinding.CLBBinding, allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	clbbindinglog.Info("reject invalid clbbinding", "kind", bd.GetType(), "namespace", bd.GetNamespace(), "name", bd.GetName(), "errors", allErrs.ToAggregate().Error())
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: "networking.cloud.tencent.com", Kind: bd.GetType()},
+		bd.GetName(),
+		allErrs,
+	)
+}
+
+// validateCLBBindingSpec 校验 CLBBinding 的 spec：
+//  1. 同一端口号和协议不能重复声明（TCPUDP 同时占用 TCP 和 UDP）。
+//  2. TCP_SSL 和 QUIC 协议必须指定证书 secret。
+//  3. useSamePortAcrossPools 需要至少两个端口池才有意义。
+//  4. 端口池启用了监听器预创建时，协议必须已预创建（端口池不存在时由控制器处理，这里不校验）。
+//  5. HTTP 和 HTTPS 协议要求端口池配置了 layer7，HTTPS 还要求配置了共享监听器的证书。
+//  6. 健康检查的响应超时时间小于检查间隔时间（与端口池监听器模板中的健康检查配置合并后校验）。
+func validateCLBBindingSpec(ctx context.Context, apiClient client.Client, spec *networkingv1alpha1.CLBBindingSpec) (field.ErrorList, error) {
+	var allErrs field.ErrorList
+	portsPath := field.NewPath("spec").Child("ports")
+	seen := make(map[string]int)
+	pools := make(map[string]*networkingv1alpha1.CLBPortPool)
+	for i, port := range spec.Ports {
+		path := portsPath.Index(i)
+		protocols := []string{port.Protocol}
+		if port.Protocol == constant.ProtocolTCPUDP {
+			protocols = []string{constant.ProtocolTCP, constant.ProtocolUDP}
+		}
+		for _, protocol := range protocols {
+			key := fmt.Sprintf("%d/%s", port.Port, protocol)
+			if j, ok := seen[key]; ok {
+				allErrs = append(allErrs, field.Duplicate(path, fmt.Sprintf("port %d %s already declared in spec.ports[%d]", port.Port, protocol, j)))
+				continue
+			}
+			seen[key] = i
+		}
+		if port.Protocol == "TCP_SSL" || port.Protocol == "QUIC" {
+			if util.GetValue(port.CertSecretName) == "" {
+				allErrs = append(allErrs, field.Required(path.Child("certSecretName"), fmt.Sprintf("certSecretName is required for protocol %s", port.Protocol)))
+			}
+		} else {
+			if len(port.ExtraCertSecretNames) > 0 {
+				allErrs = append(allErrs, field.Forbidden(path.Child("extraCertSecretNames"), fmt.Sprintf("extraCertSecretNames is not supported for protocol %s", port.Protocol)))
+			}
+			if util.GetValue(port.CACertSecretName) != "" {
+				allErrs = append(allErrs, field.Forbidden(path.Child("caCertSecretName"), fmt.Sprintf("caCertSecretName is not supported for protocol %s", port.Protocol)))
+			}
+		}
+		if len(port.Pools) == 0 {
+			allErrs = append(allErrs, field.Required(path.Child("pools"), "at least one port pool is required"))
+		} else if util.GetValue(port.UseSamePortAcrossPools) && len(port.Pools) < 2 {
+			allErrs = append(allErrs, field.Invalid(path.Child("useSamePortAcrossPools"), true, "useSamePortAcrossPools requires at least two pools"))
+		}
+		for j, poolName := range port.Pools {
+			pool, ok := pools[poolName]
+			if !ok {
+				pool = &networkingv1alpha1.CLBPortPool{}
+				if err := apiClient.Get(ctx, client.ObjectKey{Name: poolName}, pool); err != nil {
+					if !apierrors.IsNotFound(err) {
+						return nil, errors.WithStack(err)
+					}
+					pool = nil
+				}
+				pools[poolName] = pool
+			}
+			if pool != nil && !pool.Spec.IsProtocolPrecreated(port.Protocol) {
+				allErrs = append(allErrs, field.Invalid(path.Child("pools").Index(j), poolName, fmt.Sprintf(
+					"protocol %s is not precreated in port pool %s, precreate mode only allows reusing precreated listeners",
+					port.Protocol, poolName,
+				)))
+			}
+			if pool != nil {
+				if msg := validateLayer7Pool(pool, port.Protocol); msg != "" {
+					allErrs = append(allErrs, field.Invalid(path.Child("pools").Index(j), poolName, msg))
+				}
+			}
+		}
+		allErrs = append(allErrs, validatePortHealthCheck(&port, pools, path.Child("healthCheck"))...)
+	}
+	return allErrs, nil
+}
+
+// validatePortHealthCheck 校验端口的健康检查配置，该配置会覆盖各端口池监听器模板中的健康检查配置，合并后的配置都需要合法
+func validatePortHealthCheck(port *networkingv1alpha1.PortEntry, pools map[string]*networkingv1alpha1.CLBPortPool, path *field.Path) field.ErrorList {
+	if port.HealthCheck == nil {
+		return nil
+	}
+	if errs := validateHealthCheck(port.HealthCheck, path); len(errs) > 0 {
+		return errs
+	}
+	for _, poolName := range port.Pools {
+		pool := pools[poolName]
+		if pool == nil || pool.Spec.ListenerTemplate == nil {
+			continue
+		}
+		if errs := validateHealthCheck(clb.MergeHealthCheck(pool.Spec.ListenerTemplate.HealthCheck, port.HealthCheck), path); len(errs) > 0 {
+			return errs
+		}
+	}
+	return nil
+}
+
+// validateCLBBindingUpdate 校验 CLBBinding 的更新：删除中的 CLBBinding 正在释放 status 中记录的端口，
+// 此时修改端口配置会导致已分配的端口与 spec 不一致，端口无法被正确回收。
+func validateCLBBindingUpdate(oldBinding, newBinding clbbinding.CLBBinding) field.ErrorList {
+	var allErrs field.ErrorList
+	deleting := oldBinding.GetDeletionTimestamp() != nil || oldBinding.GetStatus().State == networkingv1alpha1.CLBBindingStateDeleting
+	if deleting && !equality.Semantic.DeepEqual(oldBinding.GetSpec().Ports, newBinding.GetSpec().Ports) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec").Child("ports"), "ports cannot be changed while the binding is being deleted"))
+	}
+	return allErrs
+}
+
+// validateLayer7Pool 校验端口池是否支持七层协议，不支持时返回原因
+func validateLayer7Pool(pool *networkingv1alpha1.CLBPortPool, protocol string) string {
+	if !util.IsLayer7Protocol(protocol) {
+		return ""
+	}
+	if pool.Spec.Layer7 == nil {
+		return fmt.Sprintf("protocol %s requires layer7 config in port pool %s", protocol, pool.Name)
+	}
+	if protocol == constant.ProtocolHTTPS && util.GetValue(pool.Spec.Layer7.CertId) == "" {
+		return fmt.Sprintf("protocol %s requires layer7.certId in port pool %s", protocol, pool.Name)
+	}
+	return ""
+}
@@ -0,0 +1,77 @@
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+)
+
+func TestCLBBindingValidator(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = networkingv1alpha1.AddToScheme(scheme)
+	apiClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&networkingv1alpha1.CLBPortPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "pool-tcp"},
+			Spec: networkingv1alpha1.CLBPortPoolSpec{
+				StartPort:         30000,
+				ListenerPrecreate: &networkingv1alpha1.ListenerPrecreateConfig{Enabled: true, TCP: util.GetPtr(uint16(10))},
+			},
+		},
+		&networkingv1alpha1.CLBPortPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "pool-hc"},
+			Spec: networkingv1alpha1.CLBPortPoolSpec{
+				StartPort:        30000,
+				ListenerTemplate: &networkingv1alpha1.ListenerTemplate{HealthCheck: &networkingv1alpha1.HealthCheck{Enabled: true, IntervalTime: util.GetPtr(int64(3))}},
+			},
+		},
+	).Build()
+	v := &CLBBindingCustomValidator{Client: apiClient}
+	newBinding := func(ports ...networkingv1alpha1.PortEntry) *networkingv1alpha1.CLBPodBinding {
+		return &networkingv1alpha1.CLBPodBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+			Spec:       networkingv1alpha1.CLBBindingSpec{Ports: ports},
+		}
+	}
+	tests := []struct {
+		name  string
+		ports []networkingv1alpha1.PortEntry
+		valid bool
+	}{
+		{"valid", []networkingv1alpha1.PortEntry{{Port: 80, Protocol: "TCP", Pools: []string{"pool-tcp"}}, {Port: 80, Protocol: "UDP", Pools: []string{"pool-udp"}}}, true},
+		{"duplicate", []networkingv1alpha1.PortEntry{{Port: 80, Protocol: "TCP", Pools: []string{"pool-a"}}, {Port: 80, Protocol: "TCPUDP", Pools: []string{"pool-b"}}}, false},
+		{"ssl without cert", []networkingv1alpha1.PortEntry{{Port: 443, Protocol: "TCP_SSL", Pools: []string{"pool-a"}}}, false},
+		{"same port with one pool", []networkingv1alpha1.PortEntry{{Port: 80, Protocol: "TCP", Pools: []string{"pool-a"}, UseSamePortAcrossPools: util.GetPtr(true)}}, false},
+		{"tcpudp not precreated", []networkingv1alpha1.PortEntry{{Port: 80, Protocol: "TCPUDP", Pools: []string{"pool-tcp"}}}, false},
+		{"health check timeout", []networkingv1alpha1.PortEntry{{Port: 80, Protocol: "TCP", Pools: []string{"pool-tcp"}, HealthCheck: &networkingv1alpha1.HealthCheck{Enabled: true, TimeOut: util.GetPtr(int64(4))}}}, true},
+		{"health check timeout merged with pool", []networkingv1alpha1.PortEntry{{Port: 80, Protocol: "TCP", Pools: []string{"pool-hc"}, HealthCheck: &networkingv1alpha1.HealthCheck{Enabled: true, TimeOut: util.GetPtr(int64(4))}}}, false},
+	}
+	for _, tt := range tests {
+		_, err := v.ValidateCreate(context.Background(), newBinding(tt.ports...))
+		if tt.valid && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("%s: expect error", tt.name)
+		}
+	}
+
+	// 删除中不允许修改端口池
+	oldBd := newBinding(networkingv1alpha1.PortEntry{Port: 80, Protocol: "TCP", Pools: []string{"pool-a"}})
+	oldBd.Status.State = networkingv1alpha1.CLBBindingStateDeleting
+	newBd := oldBd.DeepCopy()
+	newBd.Spec.Ports[0].Pools = []string{"pool-b"}
+	if _, err := v.ValidateUpdate(context.Background(), oldBd, newBd); err == nil {
+		t.Error("expect error when changing pools of a deleting binding")
+	}
+	newBd = oldBd.DeepCopy()
+	newBd.Finalizers = nil
+	if _, err := v.ValidateUpdate(context.Background(), oldBd, newBd); err != nil {
+		t.Errorf("unexpected error when updating metadata of a deleting binding: %v", err)
+	}
+}
@@ -0,0 +1,199 @@
+package v1alpha1
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+)
+
+func TestCLBPortPoolValidateDelete(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = networkingv1alpha1.AddToScheme(scheme)
+	pool := &networkingv1alpha1.CLBPortPool{ObjectMeta: metav1.ObjectMeta{Name: "pool-a"}}
+	apiClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		pool,
+		&networkingv1alpha1.CLBNodeBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Status: networkingv1alpha1.CLBBindingStatus{
+				PortBindings: []networkingv1alpha1.PortBindingStatus{{Port: 80, Protocol: "TCP", Pool: "pool-a"}},
+			},
+		},
+	).Build()
+	v := &CLBPortPoolCustomValidator{Client: apiClient}
+	_, err := v.ValidateDelete(context.Background(), pool)
+	if err == nil || !strings.Contains(err.Error(), "CLBNodeBinding node-1") {
+		t.Errorf("expect deletion denied with referencing binding listed, got %v", err)
+	}
+	pool.Annotations = map[string]string{constant.AllowDeleteInUseKey: "true"}
+	if _, err := v.ValidateDelete(context.Background(), pool); err != nil {
+		t.Errorf("unexpected error with %s annotation: %v", constant.AllowDeleteInUseKey, err)
+	}
+}
+
+func TestValidateCreateLBParameters(t *testing.T) {
+	tests := []struct {
+		name   string
+		params networkingv1alpha1.CreateLBParameters
+		errs   int
+	}{
+		{"default", networkingv1alpha1.CreateLBParameters{}, 0},
+		{"ipv6 full chain without subnet", networkingv1alpha1.CreateLBParameters{AddressIPVersion: util.GetPtr("IPv6FullChain")}, 1},
+		{"internal without vpc", networkingv1alpha1.CreateLBParameters{LoadBalancerType: util.GetPtr("INTERNAL"), SubnetId: util.GetPtr("subnet-1")}, 1},
+		{"bandwidth package without id", networkingv1alpha1.CreateLBParameters{
+			InternetAccessible: &networkingv1alpha1.InternetAccessible{InternetChargeType: util.GetPtr("BANDWIDTH_PACKAGE")},
+		}, 1},
+		{"isp without bandwidth package", networkingv1alpha1.CreateLBParameters{
+			VipIsp:             util.GetPtr("CMCC"),
+			InternetAccessible: &networkingv1alpha1.InternetAccessible{InternetChargeType: util.GetPtr("TRAFFIC_POSTPAID_BY_HOUR")},
+		}, 1},
+		{"isp with bandwidth package", networkingv1alpha1.CreateLBParameters{
+			VipIsp:             util.GetPtr("CMCC"),
+			BandwidthPackageId: util.GetPtr("bwp-1"),
+			InternetAccessible: &networkingv1alpha1.InternetAccessible{InternetChargeType: util.GetPtr("BANDWIDTH_PACKAGE")},
+		}, 0},
+		{"sync security group rules without security groups", networkingv1alpha1.CreateLBParameters{SyncSecurityGroupRules: util.GetPtr(true)}, 1},
+		{"sync security group rules", networkingv1alpha1.CreateLBParameters{
+			SecurityGroups:         []string{"sg-1"},
+			SyncSecurityGroupRules: util.GetPtr(true),
+		}, 0},
+	}
+	for _, tt := range tests {
+		if errs := validateCreateLBParameters(&tt.params, nil); len(errs) != tt.errs {
+			t.Errorf("%s: expect %d errors, got %v", tt.name, tt.errs, errs)
+		}
+	}
+}
+
+func TestValidateLayer7Config(t *testing.T) {
+	tests := []struct {
+		name string
+		spec networkingv1alpha1.CLBPortPoolSpec
+		errs int
+	}{
+		{"default ports", networkingv1alpha1.CLBPortPoolSpec{StartPort: 30000, Layer7: &networkingv1alpha1.Layer7Config{Domain: "{{pod}}.{{namespace}}.example.com"}}, 0},
+		{"https port in range", networkingv1alpha1.CLBPortPoolSpec{StartPort: 100, Layer7: &networkingv1alpha1.Layer7Config{Domain: "{{node}}.example.com"}}, 1},
+		{"https port above end port", networkingv1alpha1.CLBPortPoolSpec{StartPort: 100, EndPort: util.GetPtr(uint16(400)), Layer7: &networkingv1alpha1.Layer7Config{Domain: "{{node}}.example.com"}}, 0},
+		{"same port", networkingv1alpha1.CLBPortPoolSpec{StartPort: 30000, Layer7: &networkingv1alpha1.Layer7Config{Domain: "{{node}}.example.com", HTTPPort: util.GetPtr(uint16(8080)), HTTPSPort: util.GetPtr(uint16(8080))}}, 1},
+		{"empty domain", networkingv1alpha1.CLBPortPoolSpec{StartPort: 30000, Layer7: &networkingv1alpha1.Layer7Config{Domain: " "}}, 1},
+		{"placeholders in url", networkingv1alpha1.CLBPortPoolSpec{StartPort: 30000, Layer7: &networkingv1alpha1.Layer7Config{Domain: "games.example.com", Url: util.GetPtr("/{{namespace}}/{{pod}}/")}}, 0},
+		{"pod without namespace", networkingv1alpha1.CLBPortPoolSpec{StartPort: 30000, Layer7: &networkingv1alpha1.Layer7Config{Domain: "{{pod}}.example.com"}}, 1},
+		{"no pod or node", networkingv1alpha1.CLBPortPoolSpec{StartPort: 30000, Layer7: &networkingv1alpha1.Layer7Config{Domain: "games.example.com", Url: util.GetPtr("/{{port}}/")}}, 1},
+	}
+	for _, tt := range tests {
+		if errs := validateLayer7Config(&tt.spec, nil); len(errs) != tt.errs {
+			t.Errorf("%s: expect %d errors, got %v", tt.name, tt.errs, errs)
+		}
+	}
+}
+
+func TestCLBPortPoolUpdateWarnings(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = networkingv1alpha1.AddToScheme(scheme)
+	apiClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&networkingv1alpha1.CLBPodBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-1"},
+			Status: networkingv1alpha1.CLBBindingStatus{
+				PortBindings: []networkingv1alpha1.PortBindingStatus{{Port: 80, Protocol: "TCP", Pool: "pool-a", LoadbalancerId: "lb-1"}},
+			},
+		},
+	).Build()
+	v := &CLBPortPoolCustomValidator{Client: apiClient}
+	oldPool := &networkingv1alpha1.CLBPortPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool-a"},
+		Spec: networkingv1alpha1.CLBPortPoolSpec{
+			StartPort:               30000,
+			ExsistedLoadBalancerIDs: []string{"lb-1", "lb-2"},
+			AutoCreate:              &networkingv1alpha1.AutoCreateConfig{Enabled: true},
+		},
+		Status: networkingv1alpha1.CLBPortPoolStatus{
+			LoadbalancerStatuses: []networkingv1alpha1.LoadBalancerStatus{
+				{LoadbalancerID: "lb-3", AutoCreated: util.GetPtr(true)},
+				{LoadbalancerID: "lb-4", AutoCreated: util.GetPtr(true)},
+			},
+		},
+	}
+	pool := oldPool.DeepCopy()
+	pool.Spec.LbBlacklist = []string{"lb-1"}
+	pool.Spec.ExsistedLoadBalancerIDs = []string{"lb-1"}
+	pool.Spec.AutoCreate.MaxLoadBalancers = util.GetPtr(uint16(1))
+	warnings, err := v.ValidateUpdate(context.Background(), oldPool, pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// lb-1 加入黑名单且有绑定，lb-2 被移除但无绑定；maxLoadBalancers 低于已自动创建数量
+	if len(warnings) != 2 || !strings.Contains(strings.Join(warnings, "\n"), "CLBPodBinding default/pod-1") {
+		t.Errorf("unexpected warnings %v", warnings)
+	}
+}
+
+func TestCLBPortPoolDefaultOnlyOnCreate(t *testing.T) {
+	newPool := func() *networkingv1alpha1.CLBPortPool {
+		return &networkingv1alpha1.CLBPortPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "pool-isp"},
+			Spec: networkingv1alpha1.CLBPortPoolSpec{
+				StartPort: 30000,
+				AutoCreate: &networkingv1alpha1.AutoCreateConfig{
+					Enabled:    true,
+					Parameters: &networkingv1alpha1.CreateLBParameters{VipIsp: util.GetPtr("CMCC")},
+				},
+			},
+		}
+	}
+	withOperation := func(op admissionv1.Operation) context.Context {
+		return admission.NewContextWithRequest(context.Background(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{Operation: op},
+		})
+	}
+	d := &CLBPortPoolCustomDefaulter{}
+
+	pool := newPool()
+	if err := d.Default(withOperation(admissionv1.Create), pool); err != nil {
+		t.Fatal(err)
+	}
+	if ia := pool.Spec.AutoCreate.Parameters.InternetAccessible; ia == nil || util.GetValue(ia.InternetChargeType) != "BANDWIDTH_PACKAGE" {
+		t.Errorf("expect internetChargeType defaulted on create, got %+v", ia)
+	}
+
+	// 存量端口池更新时不补全，否则会因缺少 bandwidthPackageId 被拒绝
+	old := newPool()
+	pool = newPool()
+	if err := d.Default(withOperation(admissionv1.Update), pool); err != nil {
+		t.Fatal(err)
+	}
+	if pool.Spec.AutoCreate.Parameters.InternetAccessible != nil {
+		t.Errorf("expect parameters unchanged on update, got %+v", pool.Spec.AutoCreate.Parameters.InternetAccessible)
+	}
+	v := &CLBPortPoolCustomValidator{}
+	if _, err := v.ValidateUpdate(context.Background(), old, pool); err != nil {
+		t.Errorf("unexpected error updating legacy pool: %v", err)
+	}
+}
+
+func TestValidateHealthCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		hc   *networkingv1alpha1.HealthCheck
+		errs int
+	}{
+		{"disabled", &networkingv1alpha1.HealthCheck{TimeOut: util.GetPtr(int64(10))}, 0},
+		{"default", &networkingv1alpha1.HealthCheck{Enabled: true}, 0},
+		{"timeout equal to default interval", &networkingv1alpha1.HealthCheck{Enabled: true, TimeOut: util.GetPtr(int64(5))}, 1},
+		{"interval less than default timeout", &networkingv1alpha1.HealthCheck{Enabled: true, IntervalTime: util.GetPtr(int64(2))}, 1},
+		{"timeout less than interval", &networkingv1alpha1.HealthCheck{Enabled: true, TimeOut: util.GetPtr(int64(10)), IntervalTime: util.GetPtr(int64(15))}, 0},
+	}
+	for _, tt := range tests {
+		if errs := validateHealthCheck(tt.hc, nil); len(errs) != tt.errs {
+			t.Errorf("%s: expect %d errors, got %v", tt.name, tt.errs, errs)
+		}
+	}
+}
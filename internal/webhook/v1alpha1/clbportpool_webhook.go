@@ -18,18 +18,25 @@ package v1alpha1
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
 	"github.com/tkestack/tke-extend-network-controller/pkg/clusterinfo"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
@@ -41,7 +48,7 @@ var clbportpoollog = logf.Log.WithName("clbportpool-resource")
 // SetupCLBPortPoolWebhookWithManager registers the webhook for CLBPortPool in the manager.
 func SetupCLBPortPoolWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr, &networkingv1alpha1.CLBPortPool{}).
-		WithCustomValidator(&CLBPortPoolCustomValidator{}).
+		WithCustomValidator(&CLBPortPoolCustomValidator{Client: mgr.GetClient()}).
 		WithCustomDefaulter(&CLBPortPoolCustomDefaulter{}).
 		Complete()
 }
@@ -64,15 +71,41 @@ func (d *CLBPortPoolCustomDefaulter) Default(ctx context.Context, obj runtime.Ob
 	clbportpoollog.Info("Defaulting for CLBPortPool", "name", clbportpool.GetName())
 
 	util.SetIfEmpty(clbportpool.Spec.Region, clusterinfo.Region)
+
+	// 与 clb.ConvertCreateLoadBalancerRequest 中的默认行为保持一致，显式写入 spec，便于校验参数组合。
+	// 只在创建时补全：存量端口池更新时补全的参数会与旧对象不同而被重新校验，导致无法更新（包括删除时移除 finalizer）
+	if ac := clbportpool.Spec.AutoCreate; ac != nil && ac.Parameters != nil && isCreateRequest(ctx) {
+		params := ac.Parameters
+		if util.GetValue(params.LoadBalancerType) == "INTERNAL" && util.GetValue(params.VpcId) == "" && clusterinfo.VpcId != "" {
+			params.VpcId = util.GetPtr(clusterinfo.VpcId)
+		}
+		if params.VipIsp != nil {
+			if params.InternetAccessible == nil {
+				params.InternetAccessible = &networkingv1alpha1.InternetAccessible{}
+			}
+			if params.InternetAccessible.InternetChargeType == nil {
+				params.InternetAccessible.InternetChargeType = util.GetPtr("BANDWIDTH_PACKAGE")
+			}
+		}
+	}
 	return nil
 }
 
-// +kubebuilder:webhook:path=/validate-networking-cloud-tencent-com-v1alpha1-clbportpool,mutating=false,failurePolicy=fail,sideEffects=None,groups=networking.cloud.tencent.com,resources=clbportpools,verbs=create;update,versions=v1alpha1,name=vclbportpool-v1alpha1.kb.io,admissionReviewVersions=v1
+// 判断当前准入请求是否为创建操作，上下文中没有准入请求时（直接调用）视为创建
+func isCreateRequest(ctx context.Context) bool {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return true
+	}
+	return req.Operation == admissionv1.Create
+}
+
+// +kubebuilder:webhook:path=/validate-networking-cloud-tencent-com-v1alpha1-clbportpool,mutating=false,failurePolicy=fail,sideEffects=None,groups=networking.cloud.tencent.com,resources=clbportpools,verbs=create;update;delete,versions=v1alpha1,name=vclbportpool-v1alpha1.kb.io,admissionReviewVersions=v1
 
 // CLBPortPoolCustomValidator struct is responsible for validating the CLBPortPool resource
 // when it is created, updated, or deleted.
 type CLBPortPoolCustomValidator struct {
-	// TODO(user): Add more fields as needed for validation
+	Client client.Client
 }
 
 var _ webhook.CustomValidator = &CLBPortPoolCustomValidator{}
@@ -84,7 +117,7 @@ func (v *CLBPortPoolCustomValidator) ValidateCreate(ctx context.Context, obj run
 		return nil, fmt.Errorf("expected a CLBPortPool object but got %T", obj)
 	}
 	clbportpoollog.Info("Validation for CLBPortPool upon creation", "name", clbportpool.GetName())
-	return nil, v.validate(clbportpool)
+	return nil, v.validate(clbportpool, nil)
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type CLBPortPool.
@@ -93,11 +126,74 @@ func (v *CLBPortPoolCustomValidator) ValidateUpdate(ctx context.Context, oldObj,
 	if !ok {
 		return nil, fmt.Errorf("expected a CLBPortPool object for the newObj but got %T", newObj)
 	}
+	oldPool, ok := oldObj.(*networkingv1alpha1.CLBPortPool)
+	if !ok {
+		return nil, fmt.Errorf("expected a CLBPortPool object for the oldObj but got %T", oldObj)
+	}
 	clbportpoollog.Info("Validation for CLBPortPool upon update", "name", clbportpool.GetName())
-	return nil, v.validate(clbportpool)
+	if err := v.validate(clbportpool, oldPool); err != nil {
+		return nil, err
+	}
+	warnings, err := v.getUpdateWarnings(ctx, oldPool, clbportpool)
+	if err != nil { // 告警仅用于提示，查询失败不影响更新
+		clbportpoollog.Error(err, "failed to check CLBPortPool update against live state", "name", clbportpool.GetName())
+	}
+	return warnings, nil
 }
 
-func (v *CLBPortPoolCustomValidator) validate(pool *networkingv1alpha1.CLBPortPool) error {
+// getUpdateWarnings 结合端口池当前的使用情况，对可能影响已绑定 Pod/Node 的更新给出告警
+func (v *CLBPortPoolCustomValidator) getUpdateWarnings(ctx context.Context, oldPool, pool *networkingv1alpha1.CLBPortPool) (admission.Warnings, error) {
+	var warnings admission.Warnings
+	// 自动创建的 CLB 数量超过新的上限
+	if ac := pool.Spec.AutoCreate; ac != nil && ac.MaxLoadBalancers != nil {
+		autoCreated := 0
+		for _, lb := range pool.Status.LoadbalancerStatuses {
+			if util.GetValue(lb.AutoCreated) {
+				autoCreated++
+			}
+		}
+		if int(*ac.MaxLoadBalancers) < autoCreated {
+			warnings = append(warnings, fmt.Sprintf(
+				"autoCreate.maxLoadBalancers (%d) is lower than the number of auto-created loadbalancers (%d), existing loadbalancers will not be deleted but no more will be created",
+				*ac.MaxLoadBalancers, autoCreated,
+			))
+		}
+	}
+	// 从已有 CLB 列表中移除或加入黑名单的 CLB 如果还有绑定，给出告警
+	var lbIds []string
+	for _, lbId := range oldPool.Spec.ExsistedLoadBalancerIDs {
+		if !slices.Contains(pool.Spec.ExsistedLoadBalancerIDs, lbId) {
+			lbIds = append(lbIds, lbId)
+		}
+	}
+	for _, lbId := range pool.Spec.LbBlacklist {
+		if !slices.Contains(oldPool.Spec.LbBlacklist, lbId) && !slices.Contains(lbIds, lbId) {
+			lbIds = append(lbIds, lbId)
+		}
+	}
+	for _, lbId := range lbIds {
+		bindings, err := v.listBindings(ctx, func(spec *networkingv1alpha1.CLBBindingSpec, status *networkingv1alpha1.CLBBindingStatus) bool {
+			for _, binding := range status.PortBindings {
+				if binding.Pool == pool.Name && binding.LoadbalancerId == lbId {
+					return true
+				}
+			}
+			return false
+		})
+		if err != nil {
+			return warnings, err
+		}
+		if len(bindings) > 0 {
+			warnings = append(warnings, fmt.Sprintf(
+				"loadbalancer %s is removed or blacklisted but still bound by %d binding(s): %s",
+				lbId, len(bindings), joinBindings(bindings),
+			))
+		}
+	}
+	return warnings, nil
+}
+
+func (v *CLBPortPoolCustomValidator) validate(pool, oldPool *networkingv1alpha1.CLBPortPool) error {
 	var allErrs field.ErrorList
 	// 确保要有CLB，自动创建或使用已有 CLB，至少有一个指定
 	if len(pool.Spec.ExsistedLoadBalancerIDs) == 0 {
@@ -158,6 +254,28 @@ func (v *CLBPortPoolCustomValidator) validate(pool *networkingv1alpha1.CLBPortPo
 		)
 	}
 
+	// 自动创建参数组合校验。更新时仅在参数变化时校验，避免已有端口池因新增的校验规则无法更新（如移除 finalizer）
+	if ac := pool.Spec.AutoCreate; ac != nil && ac.Parameters != nil {
+		var oldParams *networkingv1alpha1.CreateLBParameters
+		if oldPool != nil && oldPool.Spec.AutoCreate != nil {
+			oldParams = oldPool.Spec.AutoCreate.Parameters
+		}
+		if oldPool == nil || !equality.Semantic.DeepEqual(oldParams, ac.Parameters) {
+			allErrs = append(allErrs, validateCreateLBParameters(ac.Parameters, field.NewPath("spec").Child("autoCreate").Child("parameters"))...)
+		}
+	}
+
+	if pool.Spec.Layer7 != nil {
+		allErrs = append(allErrs, validateLayer7Config(&pool.Spec, field.NewPath("spec").Child("layer7"))...)
+	}
+
+	// 监听器模板的健康检查配置校验，更新时仅在配置变化时校验
+	if tpl := pool.Spec.ListenerTemplate; tpl != nil && tpl.HealthCheck != nil {
+		if oldPool == nil || oldPool.Spec.ListenerTemplate == nil || !equality.Semantic.DeepEqual(oldPool.Spec.ListenerTemplate.HealthCheck, tpl.HealthCheck) {
+			allErrs = append(allErrs, validateHealthCheck(tpl.HealthCheck, field.NewPath("spec").Child("listenerTemplate").Child("healthCheck"))...)
+		}
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -168,6 +286,102 @@ func (v *CLBPortPoolCustomValidator) validate(pool *networkingv1alpha1.CLBPortPo
 	)
 }
 
+// 健康检查响应超时时间和检查间隔时间的默认值，与 CLB API 一致
+const (
+	defaultHealthCheckTimeOut      = 2
+	defaultHealthCheckIntervalTime = 5
+)
+
+// validateHealthCheck 校验开启健康检查时响应超时时间小于检查间隔时间（未指定时使用默认值），
+// 否则创建或修改监听器时才会报错，且只能从事件中发现。
+func validateHealthCheck(hc *networkingv1alpha1.HealthCheck, path *field.Path) field.ErrorList {
+	if hc == nil || !hc.Enabled {
+		return nil
+	}
+	timeOut := int64(defaultHealthCheckTimeOut)
+	if hc.TimeOut != nil {
+		timeOut = *hc.TimeOut
+	}
+	intervalTime := int64(defaultHealthCheckIntervalTime)
+	if hc.IntervalTime != nil {
+		intervalTime = *hc.IntervalTime
+	}
+	if timeOut >= intervalTime {
+		return field.ErrorList{field.Invalid(path.Child("timeOut"), timeOut, fmt.Sprintf("timeOut should be less than intervalTime (%d)", intervalTime))}
+	}
+	return nil
+}
+
+// validateCreateLBParameters 校验自动创建 CLB 参数之间的依赖关系，这些组合在调用 CreateLoadBalancer 时才会报错，
+// 且报错发生在扩容时，难以定位，因此提前拒绝。
+func validateCreateLBParameters(params *networkingv1alpha1.CreateLBParameters, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if strings.EqualFold(util.GetValue(params.AddressIPVersion), "IPv6FullChain") && util.GetValue(params.SubnetId) == "" {
+		allErrs = append(allErrs, field.Required(path.Child("subnetId"), "subnetId is required when addressIPVersion is IPv6FullChain"))
+	}
+	if util.GetValue(params.LoadBalancerType) == "INTERNAL" && util.GetValue(params.VpcId) == "" {
+		allErrs = append(allErrs, field.Required(path.Child("vpcId"), "vpcId is required when loadBalancerType is INTERNAL"))
+	}
+	var chargeType string
+	if params.InternetAccessible != nil {
+		chargeType = util.GetValue(params.InternetAccessible.InternetChargeType)
+	}
+	if chargeType == "BANDWIDTH_PACKAGE" && util.GetValue(params.BandwidthPackageId) == "" {
+		allErrs = append(allErrs, field.Required(path.Child("bandwidthPackageId"), "bandwidthPackageId is required when internetChargeType is BANDWIDTH_PACKAGE"))
+	}
+	if params.VipIsp != nil && chargeType != "BANDWIDTH_PACKAGE" {
+		allErrs = append(allErrs, field.Invalid(
+			path.Child("internetAccessible").Child("internetChargeType"), chargeType,
+			"internetChargeType must be BANDWIDTH_PACKAGE when vipIsp is specified",
+		))
+	}
+	if util.GetValue(params.SyncSecurityGroupRules) && len(params.SecurityGroups) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("securityGroups"), "securityGroups is required when syncSecurityGroupRules is true"))
+	}
+	return allErrs
+}
+
+// validateLayer7Config 校验七层配置：共享监听器的端口不能落在端口池的端口范围内（否则会与分配给四层绑定的端口冲突），
+// HTTP 和 HTTPS 共享监听器的端口不能相同；域名和 URL 模板必须能区分不同的 Pod/Node。
+func validateLayer7Config(spec *networkingv1alpha1.CLBPortPoolSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	l7 := spec.Layer7
+	inRange := func(port uint16) bool {
+		return port >= spec.StartPort && (spec.EndPort == nil || port <= *spec.EndPort)
+	}
+	httpPort := l7.GetListenerPort(constant.ProtocolHTTP)
+	httpsPort := l7.GetListenerPort(constant.ProtocolHTTPS)
+	if inRange(httpPort) {
+		allErrs = append(allErrs, field.Invalid(path.Child("httpPort"), httpPort, "httpPort should not be in the port range of the pool"))
+	}
+	if inRange(httpsPort) {
+		allErrs = append(allErrs, field.Invalid(path.Child("httpsPort"), httpsPort, "httpsPort should not be in the port range of the pool"))
+	}
+	if httpPort == httpsPort {
+		allErrs = append(allErrs, field.Invalid(path.Child("httpsPort"), httpsPort, "httpsPort should be different from httpPort"))
+	}
+	if strings.TrimSpace(l7.Domain) == "" {
+		allErrs = append(allErrs, field.Required(path.Child("domain"), "domain is required"))
+	} else if msg := validateLayer7RuleTemplate(l7.Domain + util.GetValue(l7.Url)); msg != "" {
+		allErrs = append(allErrs, field.Invalid(path.Child("domain"), l7.Domain, msg))
+	}
+	return allErrs
+}
+
+// 转发规则按渲染后的域名 + URL 查找，渲染结果相同的 CLBBinding 会接管同一条转发规则并解绑对方的后端，
+// 所以模板中必须包含 Pod 名称和命名空间，或 Node 名称
+func validateLayer7RuleTemplate(tpl string) string {
+	switch {
+	case strings.Contains(tpl, "{{pod}}"):
+		if !strings.Contains(tpl, "{{namespace}}") {
+			return "domain or url must contain {{namespace}} when {{pod}} is used, otherwise pods with the same name in different namespaces share the same rule"
+		}
+	case !strings.Contains(tpl, "{{node}}"):
+		return "domain or url must contain {{pod}} and {{namespace}}, or {{node}}, otherwise different pods or nodes share the same rule"
+	}
+	return ""
+}
+
 // ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type CLBPortPool.
 func (v *CLBPortPoolCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 	clbportpool, ok := obj.(*networkingv1alpha1.CLBPortPool)
@@ -176,7 +390,88 @@ func (v *CLBPortPoolCustomValidator) ValidateDelete(ctx context.Context, obj run
 	}
 	clbportpoollog.Info("Validation for CLBPortPool upon deletion", "name", clbportpool.GetName())
 
-	// TODO(user): fill in your validation logic upon object deletion.
+	// 显式声明允许删除，跳过检查
+	if clbportpool.GetAnnotations()[constant.AllowDeleteInUseKey] == "true" {
+		return nil, nil
+	}
+	// 端口池被删除时会清理监听器和自动创建的 CLB，如果还有 CLBBinding 在使用，会导致正在服务的 Pod/Node 断流，拒绝删除
+	bindings, err := v.getBindingsUsingPool(ctx, clbportpool.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if len(bindings) == 0 {
+		return nil, nil
+	}
+	msg := fmt.Sprintf(
+		"port pool %s is still used by %d binding(s): %s; set annotation %s=true on the pool to force deletion",
+		clbportpool.GetName(), len(bindings), joinBindings(bindings), constant.AllowDeleteInUseKey,
+	)
+	return nil, apierrors.NewForbidden(
+		schema.GroupResource{Group: "networking.cloud.tencent.com", Resource: "clbportpools"},
+		clbportpool.GetName(),
+		errors.New(msg),
+	)
+}
 
-	return nil, nil
+// joinBindings 拼接 CLBBinding 列表用于提示信息，数量过多时截断
+func joinBindings(bindings []string) string {
+	const maxShown = 10
+	if len(bindings) <= maxShown {
+		return strings.Join(bindings, ", ")
+	}
+	return strings.Join(bindings[:maxShown], ", ") + ", ..."
+}
+
+// getBindingsUsingPool 查找 spec 中声明了该端口池或 status 中已从该端口池分配了端口的 CLBPodBinding、CLBNodeBinding 和 CLBSharedBinding
+func (v *CLBPortPoolCustomValidator) getBindingsUsingPool(ctx context.Context, poolName string) ([]string, error) {
+	bindings, err := v.listBindings(ctx, func(spec *networkingv1alpha1.CLBBindingSpec, status *networkingv1alpha1.CLBBindingStatus) bool {
+		for _, port := range spec.Ports {
+			if slices.Contains(port.Pools, poolName) {
+				return true
+			}
+		}
+		for _, binding := range status.PortBindings {
+			if binding.Pool == poolName {
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	sbl := &networkingv1alpha1.CLBSharedBindingList{}
+	if err := v.Client.List(ctx, sbl); err != nil {
+		return nil, err
+	}
+	for _, sb := range sbl.Items {
+		if sb.Spec.Pool == poolName || (sb.Status.PortBinding != nil && sb.Status.PortBinding.Pool == poolName) {
+			bindings = append(bindings, fmt.Sprintf("CLBSharedBinding %s/%s", sb.Namespace, sb.Name))
+		}
+	}
+	return bindings, nil
+}
+
+// listBindings 列出所有满足条件的 CLBPodBinding 和 CLBNodeBinding
+func (v *CLBPortPoolCustomValidator) listBindings(ctx context.Context, match func(*networkingv1alpha1.CLBBindingSpec, *networkingv1alpha1.CLBBindingStatus) bool) ([]string, error) {
+	var bindings []string
+	pbl := &networkingv1alpha1.CLBPodBindingList{}
+	if err := v.Client.List(ctx, pbl); err != nil {
+		return nil, err
+	}
+	for _, pb := range pbl.Items {
+		if match(&pb.Spec, &pb.Status) {
+			bindings = append(bindings, fmt.Sprintf("CLBPodBinding %s/%s", pb.Namespace, pb.Name))
+		}
+	}
+	nbl := &networkingv1alpha1.CLBNodeBindingList{}
+	if err := v.Client.List(ctx, nbl); err != nil {
+		return nil, err
+	}
+	for _, nb := range nbl.Items {
+		if match(&nb.Spec, &nb.Status) {
+			bindings = append(bindings, fmt.Sprintf("CLBNodeBinding %s", nb.Name))
+		}
+	}
+	return bindings, nil
 }
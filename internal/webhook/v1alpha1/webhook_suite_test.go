@@ -40,6 +40,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+	networkingv1beta1 "github.com/tkestack/tke-extend-network-controller/api/v1beta1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -68,6 +69,8 @@ var _ = BeforeSuite(func() {
 	var err error
 	err = networkingv1alpha1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
+	err = networkingv1beta1.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
 
 	// +kubebuilder:scaffold:scheme
 
@@ -112,6 +115,12 @@ var _ = BeforeSuite(func() {
 	err = SetupCLBPortPoolWebhookWithManager(mgr)
 	Expect(err).NotTo(HaveOccurred())
 
+	err = SetupCLBPodBindingWebhookWithManager(mgr)
+	Expect(err).NotTo(HaveOccurred())
+
+	err = SetupCLBNodeBindingWebhookWithManager(mgr)
+	Expect(err).NotTo(HaveOccurred())
+
 	// +kubebuilder:scaffold:webhook
 
 	go func() {
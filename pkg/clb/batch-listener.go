@@ -16,11 +16,13 @@ type CreateListenerTask struct {
 	Ctx                 context.Context
 	Region              string
 	LbId                string
-	CertId              string
+	Cert                *Certificate
 	Port                int64
 	Protocol            string
 	ExtensiveParameters string
-	Result              chan *ListenerResult
+	// 监听器名称，为空时使用 TkeListenerName
+	ListenerName string
+	Result       chan *ListenerResult
 }
 
 type ListenerResult struct {
@@ -40,7 +42,7 @@ var CreateListenerChan = make(chan *CreateListenerTask, 100)
 
 type listenerKey struct {
 	Protocol            string
-	CertId              string
+	CertKey             string
 	ExtensiveParameters string
 }
 
@@ -50,24 +52,16 @@ const TkeListenerName = "TKE-LISTENER"
 // 腾讯云 API 限制：Length of .Ports should be less than 50
 const maxPortsPerCreateListenerRequest = 49
 
-func doBatchCreateListener(apiName, region, lbId, protocol, certId, extensiveParameters string, tasks []*CreateListenerTask) (listenerIds []string, err error) {
+func doBatchCreateListener(apiName, region, lbId, protocol string, cert *Certificate, extensiveParameters string, tasks []*CreateListenerTask) (listenerIds []string, err error) {
 	res, err := ApiCall(context.Background(), true, apiName, region, func(ctx context.Context, client *clb.Client) (req *clb.CreateListenerRequest, res *clb.CreateListenerResponse, err error) {
 		req = clb.NewCreateListenerRequest()
 		req.LoadBalancerId = &lbId
-		// IPv6 CLB 的健康检查源 IP 只能使用 VIP (SourceIpType=0)
-		sourceIpType := int64(1)
-		if isIPv6CLB(ctx, lbId, region) {
-			sourceIpType = 0
-		}
 		req.HealthCheck = &clb.HealthCheck{
 			HealthSwitch: common.Int64Ptr(0),
-			SourceIpType: &sourceIpType,
+			SourceIpType: healthCheckSourceIpType(ctx, lbId, region),
 		}
-		if certId != "" {
-			req.Certificate = &clb.CertificateInput{
-				SSLMode: common.StringPtr("UNIDIRECTIONAL"),
-				CertId:  &certId,
-			}
+		if cert != nil {
+			req.Certificate, req.MultiCertInfo = cert.Input()
 		}
 		if extensiveParameters != "" {
 			err = json.Unmarshal([]byte(extensiveParameters), req)
@@ -79,7 +73,11 @@ func doBatchCreateListener(apiName, region, lbId, protocol, certId, extensivePar
 		req.Protocol = &protocol
 		for _, task := range tasks {
 			req.Ports = append(req.Ports, common.Int64Ptr(task.Port))
-			req.ListenerNames = append(req.ListenerNames, common.StringPtr(TkeListenerName))
+			listenerName := task.ListenerName
+			if listenerName == "" {
+				listenerName = TkeListenerName
+			}
+			req.ListenerNames = append(req.ListenerNames, common.StringPtr(listenerName))
 		}
 		res, err = client.CreateListener(req)
 		if err == nil && len(res.Response.ListenerIds) != len(tasks) {
@@ -106,7 +104,7 @@ func startCreateListenerProccessor(concurrent int) {
 		for _, task := range tasks {
 			key := listenerKey{
 				Protocol:            task.Protocol,
-				CertId:              task.CertId,
+				CertKey:             task.Cert.Key(),
 				ExtensiveParameters: task.ExtensiveParameters,
 			}
 			groupTask[key] = append(groupTask[key], task)
@@ -118,7 +116,7 @@ func startCreateListenerProccessor(concurrent int) {
 				batch := tasks[:batchSize]
 				tasks = tasks[batchSize:]
 
-				listenerIds, err := doBatchCreateListener(apiName, region, lbId, lis.Protocol, lis.CertId, lis.ExtensiveParameters, batch)
+				listenerIds, err := doBatchCreateListener(apiName, region, lbId, lis.Protocol, batch[0].Cert, lis.ExtensiveParameters, batch)
 				if err != nil {
 					clbLog.Error(
 						err, "batch create listener failed",
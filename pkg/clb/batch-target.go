@@ -39,6 +39,7 @@ func startRegisterTargetsProccessor(concurrent int) {
 					ListenerId: &task.ListenerId,
 					Port:       &task.Target.TargetPort,
 					EniIp:      &task.Target.TargetIP,
+					Weight:     task.Target.Weight,
 				})
 			}
 			res, err = client.BatchRegisterTargets(req)
@@ -122,6 +123,7 @@ func startDescribeTargetsProccessor(concurrent int) {
 					targets = append(targets, &Target{
 						TargetIP:   *ip,
 						TargetPort: *target.Port,
+						Weight:     target.Weight,
 					})
 				}
 			}
@@ -0,0 +1,93 @@
+package clb
+
+import (
+	"slices"
+	"strings"
+
+	clb "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/clb/v20180317"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+	"github.com/tkestack/tke-extend-network-controller/pkg/util"
+)
+
+// Certificate 监听器的证书配置（仅对 TCP_SSL 和 QUIC 监听器有效）
+type Certificate struct {
+	// 服务端证书 ID
+	CertId string
+	// 额外的服务端证书 ID，与 CertId 的算法类型不能重复
+	ExtraCertIds []string
+	// 客户端 CA 证书 ID，不为空时开启双向认证
+	CACertId string
+}
+
+// NewCertificate 根据证书 ID 构造证书配置，服务端证书 ID 为空时返回 nil
+func NewCertificate(certId *string, extraCertIds []string, caCertId *string) *Certificate {
+	if util.GetValue(certId) == "" {
+		return nil
+	}
+	return &Certificate{
+		CertId:       *certId,
+		ExtraCertIds: extraCertIds,
+		CACertId:     util.GetValue(caCertId),
+	}
+}
+
+func (c *Certificate) sslMode() string {
+	if c.CACertId != "" {
+		return "MUTUAL"
+	}
+	return "UNIDIRECTIONAL"
+}
+
+// Key 返回证书配置的唯一标识，用于批量创建监听器时按证书配置分组
+func (c *Certificate) Key() string {
+	if c == nil {
+		return ""
+	}
+	return strings.Join([]string{c.CertId, strings.Join(c.ExtraCertIds, ","), c.CACertId}, "/")
+}
+
+// Equal 判断监听器当前的证书配置是否与期望的一致
+func (c *Certificate) Equal(current *clb.CertificateOutput) bool {
+	if current == nil {
+		return false
+	}
+	if util.GetValue(current.CertId) != c.CertId || util.GetValue(current.CertCaId) != c.CACertId {
+		return false
+	}
+	extIds := util.ConvertPtrSlice(current.ExtCertIds)
+	if len(extIds) != len(c.ExtraCertIds) {
+		return false
+	}
+	for _, id := range c.ExtraCertIds {
+		if !slices.Contains(extIds, id) {
+			return false
+		}
+	}
+	return true
+}
+
+// Input 转换为 CLB API 的证书参数：只有一本服务端证书时使用 Certificate 参数，
+// 有多本服务端证书时使用 MultiCertInfo 参数，双向认证时 CA 证书也放在证书列表中
+func (c *Certificate) Input() (*clb.CertificateInput, *clb.MultiCertInfo) {
+	if len(c.ExtraCertIds) == 0 {
+		input := &clb.CertificateInput{
+			SSLMode: common.StringPtr(c.sslMode()),
+			CertId:  common.StringPtr(c.CertId),
+		}
+		if c.CACertId != "" {
+			input.CertCaId = common.StringPtr(c.CACertId)
+		}
+		return input, nil
+	}
+	info := &clb.MultiCertInfo{
+		SSLMode:  common.StringPtr(c.sslMode()),
+		CertList: []*clb.CertInfo{{CertId: common.StringPtr(c.CertId)}},
+	}
+	for _, id := range c.ExtraCertIds {
+		info.CertList = append(info.CertList, &clb.CertInfo{CertId: common.StringPtr(id)})
+	}
+	if c.CACertId != "" {
+		info.CertList = append(info.CertList, &clb.CertInfo{CertId: common.StringPtr(c.CACertId)})
+	}
+	return nil, info
+}
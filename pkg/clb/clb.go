@@ -27,6 +27,7 @@ func GetClient(region string) *clb.Client {
 	if err != nil {
 		panic(err)
 	}
+	cloudapi.InitClient(&client.Client)
 	clients[region] = client
 	return client
 }
@@ -0,0 +1,270 @@
+package clb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/tkestack/tke-extend-network-controller/pkg/cloudapi"
+)
+
+// 影子模式（--dry-run-cloud）下所有写操作的云 API 调用（CLB、VPC 安全组、SSL 证书等）只记录不执行，读操作仍然调用真实的云 API，
+// 用于新版本控制器上线前与线上版本并行运行，对比其预期执行的操作。
+var dryRun bool
+
+// 记录的不同写操作数量上限，超过后只计数不记录详情
+const maxDryRunActions = 5000
+
+// 影子模式下伪造的 RequestId 前缀，等待该请求对应的异步任务时直接返回成功
+const dryRunRequestIdPrefix = "dry-run-"
+
+// 只读接口的前缀，其余接口都视为写操作
+var readOnlyActionPrefixes = []string{"Describe", "Inquiry", "Get"}
+
+var dryRunCallsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "clb_dry_run_calls_total",
+		Help: "Number of cloud write API calls (CLB, VPC, SSL, etc.) skipped in dry-run-cloud mode",
+	},
+	[]string{"api"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(dryRunCallsTotal)
+}
+
+// SetDryRun 开启影子模式，需在创建云 API client 之前调用
+func SetDryRun(enabled bool) {
+	dryRun = enabled
+	if enabled {
+		cloudapi.SetTransport(&dryRunTransport{next: http.DefaultTransport})
+	} else {
+		cloudapi.SetTransport(nil)
+	}
+}
+
+func IsDryRun() bool {
+	return dryRun
+}
+
+// DryRunAction 影子模式下被跳过的一个写操作，相同的请求会合并计数
+type DryRunAction struct {
+	Api       string    `json:"api"`
+	Region    string    `json:"region"`
+	LbId      string    `json:"lbId,omitempty"`
+	Request   string    `json:"request"`
+	Count     int       `json:"count"`
+	FirstTime time.Time `json:"firstTime"`
+	LastTime  time.Time `json:"lastTime"`
+}
+
+// DryRunReport 影子模式下被跳过的写操作汇总
+type DryRunReport struct {
+	Total   int64           `json:"total"`
+	Dropped int64           `json:"dropped"`
+	Apis    map[string]int  `json:"apis"`
+	Actions []*DryRunAction `json:"actions"`
+}
+
+type dryRunRecorder struct {
+	mu      sync.Mutex
+	actions map[string]*DryRunAction
+	total   int64
+	dropped int64
+	seq     atomic.Int64
+}
+
+var recorder = &dryRunRecorder{actions: make(map[string]*DryRunAction)}
+
+func (r *dryRunRecorder) record(api, region, lbId, body string) {
+	dryRunCallsTotal.WithLabelValues(api).Inc()
+	clbLog.Info("dry-run: skip cloud write API call", "api", api, "region", region, "lbId", lbId, "request", body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.total++
+	now := time.Now()
+	key := strings.Join([]string{api, region, body}, "|")
+	if action, ok := r.actions[key]; ok {
+		action.Count++
+		action.LastTime = now
+		return
+	}
+	if len(r.actions) >= maxDryRunActions {
+		r.dropped++
+		return
+	}
+	r.actions[key] = &DryRunAction{
+		Api:       api,
+		Region:    region,
+		LbId:      lbId,
+		Request:   body,
+		Count:     1,
+		FirstTime: now,
+		LastTime:  now,
+	}
+}
+
+// GetDryRunReport 获取影子模式下被跳过的写操作汇总，按最后一次调用时间倒序
+func GetDryRunReport() *DryRunReport {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	report := &DryRunReport{
+		Total:   recorder.total,
+		Dropped: recorder.dropped,
+		Apis:    make(map[string]int),
+	}
+	for _, action := range recorder.actions {
+		copied := *action
+		report.Actions = append(report.Actions, &copied)
+		report.Apis[action.Api] += action.Count
+	}
+	sort.Slice(report.Actions, func(i, j int) bool {
+		return report.Actions[i].LastTime.After(report.Actions[j].LastTime)
+	})
+	return report
+}
+
+// DryRunHandler 以 JSON 格式输出影子模式的操作汇总，用于 debug 接口
+func DryRunHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(GetDryRunReport()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// dryRunTransport 在 HTTP 层拦截云 API 写操作，记录后返回伪造的成功响应
+type dryRunTransport struct {
+	next http.RoundTripper
+}
+
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	action := req.Header.Get("X-TC-Action")
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	params := map[string]any{}
+	_ = json.Unmarshal(body, &params)
+	if isReadOnlyAction(action) {
+		// 等待伪造的异步任务，直接返回成功
+		if taskId, _ := params["TaskId"].(string); action == "DescribeTaskStatus" && strings.HasPrefix(taskId, dryRunRequestIdPrefix) {
+			return fakeResponse(req, map[string]any{"Status": 0})
+		}
+		return t.next.RoundTrip(req)
+	}
+	recorder.record(action, req.Header.Get("X-TC-Region"), lbIdOfParams(params), redactBody(body, params))
+	return fakeResponse(req, fakeResult(action, params))
+}
+
+// 请求中需要脱敏的参数（如上传证书时的私钥），不能输出到日志和 debug 接口
+var sensitiveParams = []string{"CertificatePrivateKey"}
+
+func redactBody(body []byte, params map[string]any) string {
+	redacted := false
+	for _, key := range sensitiveParams {
+		if _, ok := params[key]; ok {
+			params[key] = "***"
+			redacted = true
+		}
+	}
+	if !redacted {
+		return string(body)
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func isReadOnlyAction(action string) bool {
+	for _, prefix := range readOnlyActionPrefixes {
+		if strings.HasPrefix(action, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// 写操作涉及的 CLB
+func lbIdOfParams(params map[string]any) string {
+	if lbId, ok := params["LoadBalancerId"].(string); ok {
+		return lbId
+	}
+	ids := []string{}
+	for _, key := range []string{"LoadBalancerIds", "ResourceList"} {
+		if list, ok := params[key].([]any); ok {
+			for _, item := range list {
+				if id, ok := item.(string); ok {
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+	return strings.Join(ids, ",")
+}
+
+// 伪造写操作的返回值，保证调用方后续逻辑可以继续执行
+func fakeResult(action string, params map[string]any) map[string]any {
+	seq := recorder.seq.Add(1)
+	result := map[string]any{}
+	switch action {
+	case "CreateListener":
+		ids := []string{}
+		ports, _ := params["Ports"].([]any)
+		for i := range max(len(ports), 1) {
+			ids = append(ids, fmt.Sprintf("lbl-dryrun-%d-%d", seq, i))
+		}
+		result["ListenerIds"] = ids
+	case "CreateLoadBalancer":
+		ids := []string{}
+		num, _ := params["Number"].(float64)
+		for i := range max(int(num), 1) {
+			ids = append(ids, fmt.Sprintf("lb-dryrun-%d-%d", seq, i))
+		}
+		result["LoadBalancerIds"] = ids
+	case "UploadCertificate":
+		result["CertificateId"] = fmt.Sprintf("cert-dryrun-%d", seq)
+	case "DeleteCertificate":
+		result["DeleteResult"] = true
+	}
+	return result
+}
+
+func fakeResponse(req *http.Request, result map[string]any) (*http.Response, error) {
+	result["RequestId"] = fmt.Sprintf("%s%d", dryRunRequestIdPrefix, recorder.seq.Add(1))
+	data, err := json.Marshal(map[string]any{"Response": result})
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode:    http.StatusOK,
+		Status:        "200 OK",
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,118 @@
+package clb
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	clb "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/clb/v20180317"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/profile"
+	vpc "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/vpc/v20170312"
+
+	"github.com/tkestack/tke-extend-network-controller/pkg/cloudapi"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestDryRunTransport(t *testing.T) {
+	forwarded := []string{}
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		forwarded = append(forwarded, req.Header.Get("X-TC-Action"))
+		body := `{"Response":{"TotalCount":0,"Listeners":[],"RequestId":"real"}}`
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(body)), Request: req}, nil
+	})
+	client, err := clb.NewClient(common.NewCredential("id", "key"), "ap-guangzhou", profile.NewClientProfile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.WithHttpTransport(&dryRunTransport{next: next})
+
+	// 写操作被拦截并返回伪造的监听器 ID
+	req := clb.NewCreateListenerRequest()
+	req.LoadBalancerId = common.StringPtr("lb-test")
+	req.Protocol = common.StringPtr("TCP")
+	req.Ports = common.Int64Ptrs([]int64{30000, 30001})
+	res, err := client.CreateListener(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Response.ListenerIds) != 2 || !strings.HasPrefix(*res.Response.RequestId, dryRunRequestIdPrefix) {
+		t.Fatalf("unexpected response: %s", res.ToJsonString())
+	}
+	// 等待伪造的异步任务直接成功
+	statusReq := clb.NewDescribeTaskStatusRequest()
+	statusReq.TaskId = res.Response.RequestId
+	statusRes, err := client.DescribeTaskStatus(statusReq)
+	if err != nil || *statusRes.Response.Status != 0 {
+		t.Fatalf("unexpected task status: %v, err: %v", statusRes, err)
+	}
+	// 读操作调用真实接口
+	if _, err := client.DescribeListeners(clb.NewDescribeListenersRequest()); err != nil {
+		t.Fatal(err)
+	}
+	if len(forwarded) != 1 || forwarded[0] != "DescribeListeners" {
+		t.Fatalf("expect only DescribeListeners forwarded, got %v", forwarded)
+	}
+
+	report := GetDryRunReport()
+	if report.Apis["CreateListener"] != 1 || len(report.Actions) != 1 || report.Actions[0].LbId != "lb-test" {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+}
+
+func TestDryRunOtherProducts(t *testing.T) {
+	forwarded := []string{}
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		forwarded = append(forwarded, req.Header.Get("X-TC-Action"))
+		return nil, io.EOF
+	})
+	cloudapi.SetTransport(&dryRunTransport{next: next})
+	defer cloudapi.SetTransport(nil)
+
+	// 安全组规则的写操作被拦截
+	vpcClient, err := vpc.NewClient(common.NewCredential("id", "key"), "ap-guangzhou", profile.NewClientProfile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cloudapi.InitClient(&vpcClient.Client)
+	sgReq := vpc.NewCreateSecurityGroupPoliciesRequest()
+	sgReq.SecurityGroupId = common.StringPtr("sg-test")
+	if _, err := vpcClient.CreateSecurityGroupPolicies(sgReq); err != nil {
+		t.Fatal(err)
+	}
+
+	// 通过通用客户端调用的 SSL 证书接口同样被拦截，返回伪造的证书 ID，且私钥不会被记录
+	sslClient := common.NewCommonClient(common.NewCredential("id", "key"), "ap-guangzhou", profile.NewClientProfile())
+	cloudapi.InitClient(sslClient)
+	req := tchttp.NewCommonRequest("ssl", "2019-12-05", "UploadCertificate")
+	if err := req.SetActionParameters(map[string]any{"CertificatePublicKey": "cert", "CertificatePrivateKey": "secret-key"}); err != nil {
+		t.Fatal(err)
+	}
+	res := tchttp.NewCommonResponse()
+	if err := sslClient.Send(req, res); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(res.GetBody()), "cert-dryrun-") {
+		t.Fatalf("unexpected response: %s", res.GetBody())
+	}
+	if len(forwarded) != 0 {
+		t.Fatalf("expect no request forwarded, got %v", forwarded)
+	}
+
+	report := GetDryRunReport()
+	if report.Apis["CreateSecurityGroupPolicies"] != 1 || report.Apis["UploadCertificate"] != 1 {
+		t.Fatalf("unexpected report: %+v", report.Apis)
+	}
+	for _, action := range report.Actions {
+		if strings.Contains(action.Request, "secret-key") {
+			t.Errorf("private key should be redacted: %s", action.Request)
+		}
+	}
+}
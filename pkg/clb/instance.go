@@ -103,6 +103,7 @@ func EnsureCLBTags(ctx context.Context, region, lbId string, tags map[string]str
 	if err != nil {
 		return errors.WithStack(err)
 	}
+	cloudapi.InitClient(&client.Client)
 	req := tag.NewTagResourcesRequest()
 	req.ResourceList = []*string{common.StringPtr(fmt.Sprintf("qcs::clb:%s:uin/%s:clb/%s", region, userinfo.OwnerUin, lbId))}
 	for k, v := range tags {
@@ -120,6 +121,21 @@ func EnsureCLBTags(ctx context.Context, region, lbId string, tags map[string]str
 	return nil
 }
 
+// SetSecurityGroups 设置 CLB 绑定的安全组，覆盖已绑定的全部安全组
+func SetSecurityGroups(ctx context.Context, region, lbId string, sgIds []string) error {
+	_, err := ApiCall(ctx, true, "SetLoadBalancerSecurityGroups", region, func(ctx context.Context, client *clb.Client) (req *clb.SetLoadBalancerSecurityGroupsRequest, res *clb.SetLoadBalancerSecurityGroupsResponse, err error) {
+		req = clb.NewSetLoadBalancerSecurityGroupsRequest()
+		req.LoadBalancerId = &lbId
+		req.SecurityGroups = common.StringPtrs(sgIds)
+		res, err = client.SetLoadBalancerSecurityGroupsWithContext(ctx, req)
+		return
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
 func Delete(ctx context.Context, region string, lbIds ...string) error {
 	req := clb.NewDeleteLoadBalancerRequest()
 	for _, lbId := range lbIds {
@@ -178,6 +194,7 @@ type CLBInfo struct {
 	Hostname         *string
 	Tags             map[string]string
 	AddressIPVersion *string
+	SecurityGroups   []string
 }
 
 func getTagsMap(tags []*clb.TagInfo) map[string]string {
@@ -213,10 +230,11 @@ func BatchGetClbInfo(ctx context.Context, lbIds []string, region string) (info m
 		}
 		for _, ins := range res.Response.LoadBalancerSet {
 			lbInfo := &CLBInfo{
-				LoadbalancerID:    *ins.LoadBalancerId,
-				LoadbalancerName:  *ins.LoadBalancerName,
-				Tags:              getTagsMap(ins.Tags),
-				AddressIPVersion:  ins.AddressIPVersion,
+				LoadbalancerID:   *ins.LoadBalancerId,
+				LoadbalancerName: *ins.LoadBalancerName,
+				Tags:             getTagsMap(ins.Tags),
+				AddressIPVersion: ins.AddressIPVersion,
+				SecurityGroups:   util.ConvertPtrSlice(ins.SecureGroups),
 			}
 			if util.GetValue(ins.Domain) != "" {
 				lbInfo.Hostname = ins.Domain
@@ -0,0 +1,87 @@
+package clb
+
+import (
+	"strconv"
+	"strings"
+)
+
+// 监听器名称最大长度（CLB 限制 1-255 个字符）
+const maxListenerNameLength = 255
+
+// 控制器为 CLBBinding 动态创建的监听器名称中各字段的分隔符。
+// 端口池名称需符合 DNS 子域名规范，UID 只包含十六进制字符和 "-"，都不会出现 "_"，
+// 因此用 "_" 分隔可以无歧义地解析。
+const listenerNameSeparator = "_"
+
+// ListenerOwner 描述动态创建的监听器所属的 CLBBinding
+type ListenerOwner struct {
+	// CLBBinding 的 UID
+	UID string
+	// 应用端口
+	Port uint16
+	// 端口池名称（名称过长时可能被截断）
+	Pool string
+}
+
+// BuildListenerName 生成动态创建的监听器名称，格式：TKE-LISTENER_<uid>_<port>_<pool>。
+// 名称中记录了所属 CLBBinding 的 UID、端口池和应用端口，用于：
+// 1. 重试创建时识别并复用自己之前创建的监听器；
+// 2. 识别被其它 CLBBinding 占用的监听器（端口冲突）；
+// 3. 在控制台中通过监听器名称反查到对应的 Pod/Node。
+// 端口池名称放在最后，超长时截断端口池名称，不影响 UID 和端口的解析。
+func BuildListenerName(uid, pool string, port uint16) string {
+	if uid == "" {
+		return TkeListenerName
+	}
+	name := strings.Join([]string{TkeListenerName, uid, strconv.Itoa(int(port)), pool}, listenerNameSeparator)
+	if len(name) > maxListenerNameLength {
+		name = name[:maxListenerNameLength]
+	}
+	return name
+}
+
+// ParseListenerName 从监听器名称中解析出所属的 CLBBinding，如果不是 BuildListenerName
+// 生成的名称（如预创建监听器、旧版本创建的 TKE-LISTENER 或用户手动创建的监听器）返回 nil。
+func ParseListenerName(name string) *ListenerOwner {
+	fields := strings.SplitN(name, listenerNameSeparator, 4)
+	if len(fields) != 4 || fields[0] != TkeListenerName || fields[1] == "" {
+		return nil
+	}
+	port, err := strconv.ParseUint(fields[2], 10, 16)
+	if err != nil {
+		return nil
+	}
+	return &ListenerOwner{
+		UID:  fields[1],
+		Port: uint16(port),
+		Pool: fields[3],
+	}
+}
+
+// IsTkeListener 判断监听器是否由控制器创建（包括预创建监听器和动态创建的监听器）
+func IsTkeListener(name string) bool {
+	return name == TkeListenerName || ParseListenerName(name) != nil
+}
+
+// IsOwnedByPool 判断监听器是否属于指定端口池。
+// 预创建监听器和旧版本创建的监听器名称中没有端口池信息，无法区分，视为属于该端口池。
+func (o *ListenerOwner) IsOwnedByPool(pool string) bool {
+	if o == nil {
+		return true
+	}
+	// 名称超长时端口池名称会被截断，用同样的规则生成名称后再比较
+	return BuildListenerName(o.UID, pool, o.Port) == BuildListenerName(o.UID, o.Pool, o.Port)
+}
+
+// 端口池七层共享监听器名称中的所属者标记，共享监听器被多个 CLBBinding 的转发规则共用，不属于任何一个 CLBBinding
+const Layer7ListenerOwner = "layer7"
+
+// BuildLayer7ListenerName 生成端口池七层共享监听器的名称，格式：TKE-LISTENER_layer7_<port>_<pool>
+func BuildLayer7ListenerName(pool string, port uint16) string {
+	return BuildListenerName(Layer7ListenerOwner, pool, port)
+}
+
+// IsLayer7 判断是否为端口池的七层共享监听器
+func (o *ListenerOwner) IsLayer7() bool {
+	return o != nil && o.UID == Layer7ListenerOwner
+}
@@ -0,0 +1,54 @@
+package clb
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestListenerNameRoundTrip(t *testing.T) {
+	uid := "0b7c1f5e-2d3a-4c8b-9e61-7a5d2f4c3b10"
+	name := BuildListenerName(uid, "pool-a", 8080)
+	owner := ParseListenerName(name)
+	if owner == nil {
+		t.Fatalf("parse %q failed", name)
+	}
+	if owner.UID != uid || owner.Pool != "pool-a" || owner.Port != 8080 {
+		t.Fatalf("unexpected owner: %+v", owner)
+	}
+	if !IsTkeListener(name) {
+		t.Fatalf("%q should be tke listener", name)
+	}
+	if !owner.IsOwnedByPool("pool-a") || owner.IsOwnedByPool("pool-b") {
+		t.Fatalf("unexpected pool ownership: %+v", owner)
+	}
+}
+
+func TestListenerNameTruncated(t *testing.T) {
+	pool := strings.Repeat("p", 300)
+	name := BuildListenerName("uid", pool, 80)
+	if len(name) != maxListenerNameLength {
+		t.Fatalf("expect name length %d, got %d", maxListenerNameLength, len(name))
+	}
+	owner := ParseListenerName(name)
+	if owner == nil || owner.UID != "uid" || owner.Port != 80 {
+		t.Fatalf("unexpected owner: %+v", owner)
+	}
+	if !owner.IsOwnedByPool(pool) {
+		t.Fatalf("truncated pool name should match %q", pool)
+	}
+}
+
+func TestParseLegacyListenerName(t *testing.T) {
+	for _, name := range []string{TkeListenerName, "", "my-listener", "TKE-LISTENER_uid_notport_pool"} {
+		if owner := ParseListenerName(name); owner != nil {
+			t.Fatalf("%q should not be parsed, got %+v", name, owner)
+		}
+	}
+	if !IsTkeListener(TkeListenerName) || IsTkeListener("my-listener") {
+		t.Fatal("unexpected IsTkeListener result")
+	}
+	var owner *ListenerOwner
+	if !owner.IsOwnedByPool("any") {
+		t.Fatal("legacy listener should be treated as owned by pool")
+	}
+}
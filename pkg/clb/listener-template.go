@@ -0,0 +1,236 @@
+package clb
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	clb "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/clb/v20180317"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+)
+
+// MergeHealthCheck 合并端口池监听器模板和端口配置中的健康检查配置，端口配置中的 enabled 总是生效，其它字段非空时生效。
+func MergeHealthCheck(base, override *networkingv1alpha1.HealthCheck) *networkingv1alpha1.HealthCheck {
+	if override == nil {
+		return base
+	}
+	if base == nil {
+		return override
+	}
+	hc := base.DeepCopy()
+	hc.Enabled = override.Enabled
+	setIfNotNil := func(dst **int64, src *int64) {
+		if src != nil {
+			*dst = src
+		}
+	}
+	setStrIfNotNil := func(dst **string, src *string) {
+		if src != nil {
+			*dst = src
+		}
+	}
+	setIfNotNil(&hc.TimeOut, override.TimeOut)
+	setIfNotNil(&hc.IntervalTime, override.IntervalTime)
+	setIfNotNil(&hc.HealthNum, override.HealthNum)
+	setIfNotNil(&hc.UnHealthNum, override.UnHealthNum)
+	setIfNotNil(&hc.CheckPort, override.CheckPort)
+	setStrIfNotNil(&hc.CheckType, override.CheckType)
+	setStrIfNotNil(&hc.ContextType, override.ContextType)
+	setStrIfNotNil(&hc.SendContext, override.SendContext)
+	setStrIfNotNil(&hc.RecvContext, override.RecvContext)
+	return hc
+}
+
+// ConvertHealthCheck 将健康检查配置转换为 CLB API 的参数（不包含由控制器决定的 SourceIpType）
+func ConvertHealthCheck(hc *networkingv1alpha1.HealthCheck) *clb.HealthCheck {
+	if hc == nil {
+		return nil
+	}
+	ret := &clb.HealthCheck{
+		HealthSwitch: common.Int64Ptr(0),
+	}
+	if !hc.Enabled {
+		return ret
+	}
+	ret.HealthSwitch = common.Int64Ptr(1)
+	ret.TimeOut = hc.TimeOut
+	ret.IntervalTime = hc.IntervalTime
+	ret.HealthNum = hc.HealthNum
+	ret.UnHealthNum = hc.UnHealthNum
+	ret.CheckPort = hc.CheckPort
+	ret.CheckType = hc.CheckType
+	ret.ContextType = hc.ContextType
+	ret.SendContext = hc.SendContext
+	ret.RecvContext = hc.RecvContext
+	return ret
+}
+
+// 由监听器模板生成的 CreateListener/ModifyListener 请求参数，字段名与 API 参数一致
+type listenerParameters struct {
+	HealthCheck         *clb.HealthCheck `json:"HealthCheck,omitempty"`
+	Scheduler           *string          `json:"Scheduler,omitempty"`
+	SessionExpireTime   *int64           `json:"SessionExpireTime,omitempty"`
+	SessionType         *string          `json:"SessionType,omitempty"`
+	IdleConnectTimeout  *int64           `json:"IdleConnectTimeout,omitempty"`
+	DeregisterTargetRst *bool            `json:"DeregisterTargetRst,omitempty"`
+}
+
+// 会话保持、空闲连接超时等参数仅 TCP/UDP 监听器支持
+func isTCPOrUDP(protocol string) bool {
+	return protocol == "TCP" || protocol == "UDP"
+}
+
+func convertListenerTemplate(tpl *networkingv1alpha1.ListenerTemplate, protocol string) *listenerParameters {
+	if tpl == nil {
+		return nil
+	}
+	params := &listenerParameters{
+		HealthCheck: ConvertHealthCheck(tpl.HealthCheck),
+		Scheduler:   tpl.Scheduler,
+	}
+	if isTCPOrUDP(protocol) {
+		params.SessionExpireTime = tpl.SessionExpireTime
+		params.SessionType = tpl.SessionType
+		params.IdleConnectTimeout = tpl.IdleConnectTimeout
+		params.DeregisterTargetRst = tpl.DeregisterTargetRst
+	}
+	return params
+}
+
+// BuildExtensiveParameters 根据监听器模板生成创建指定协议监听器的扩展参数（CreateListener 请求的 JSON 片段）
+func BuildExtensiveParameters(tpl *networkingv1alpha1.ListenerTemplate, protocol string) (string, error) {
+	params := convertListenerTemplate(tpl, protocol)
+	if params == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	if string(data) == "{}" {
+		return "", nil
+	}
+	return string(data), nil
+}
+
+// GetListenerDrift 对比监听器实际的配置与模板，返回不符合预期的配置项，只比较模板中指定了的配置
+func GetListenerDrift(lis *clb.Listener, tpl *networkingv1alpha1.ListenerTemplate, protocol string) []string {
+	params := convertListenerTemplate(tpl, protocol)
+	if params == nil {
+		return nil
+	}
+	drift := []string{}
+	if !IsHealthCheckExpected(lis.HealthCheck, tpl.HealthCheck) {
+		drift = append(drift, "HealthCheck")
+	}
+	if params.Scheduler != nil && (lis.Scheduler == nil || *lis.Scheduler != *params.Scheduler) {
+		drift = append(drift, "Scheduler")
+	}
+	if params.SessionExpireTime != nil && (lis.SessionExpireTime == nil || *lis.SessionExpireTime != *params.SessionExpireTime) {
+		drift = append(drift, "SessionExpireTime")
+	}
+	if params.SessionType != nil && (lis.SessionType == nil || *lis.SessionType != *params.SessionType) {
+		drift = append(drift, "SessionType")
+	}
+	if params.IdleConnectTimeout != nil && (lis.IdleConnectTimeout == nil || *lis.IdleConnectTimeout != *params.IdleConnectTimeout) {
+		drift = append(drift, "IdleConnectTimeout")
+	}
+	if params.DeregisterTargetRst != nil && (lis.DeregisterTargetRst == nil || *lis.DeregisterTargetRst != *params.DeregisterTargetRst) {
+		drift = append(drift, "DeregisterTargetRst")
+	}
+	return drift
+}
+
+// IsHealthCheckExpected 判断监听器实际的健康检查配置是否符合预期，只比较预期中指定了的字段
+func IsHealthCheckExpected(actual *clb.HealthCheck, expected *networkingv1alpha1.HealthCheck) bool {
+	if expected == nil {
+		return true
+	}
+	if actual == nil {
+		return false
+	}
+	enabled := actual.HealthSwitch != nil && *actual.HealthSwitch == 1
+	if enabled != expected.Enabled {
+		return false
+	}
+	if !expected.Enabled { // 关闭了健康检查，其它参数不需要比较
+		return true
+	}
+	int64Equal := func(a, b *int64) bool {
+		return b == nil || (a != nil && *a == *b)
+	}
+	strEqual := func(a, b *string) bool {
+		return b == nil || (a != nil && *a == *b)
+	}
+	return int64Equal(actual.TimeOut, expected.TimeOut) &&
+		int64Equal(actual.IntervalTime, expected.IntervalTime) &&
+		int64Equal(actual.HealthNum, expected.HealthNum) &&
+		int64Equal(actual.UnHealthNum, expected.UnHealthNum) &&
+		int64Equal(actual.CheckPort, expected.CheckPort) &&
+		strEqual(actual.CheckType, expected.CheckType) &&
+		strEqual(actual.ContextType, expected.ContextType) &&
+		strEqual(actual.SendContext, expected.SendContext) &&
+		strEqual(actual.RecvContext, expected.RecvContext)
+}
+
+// ModifyListener 按监听器模板修改已有监听器的配置
+func ModifyListener(ctx context.Context, region, lbId, listenerId, protocol string, tpl *networkingv1alpha1.ListenerTemplate) error {
+	params := convertListenerTemplate(tpl, protocol)
+	if params == nil {
+		return nil
+	}
+	mu := getLbLock(lbId)
+	mu.Lock()
+	defer mu.Unlock()
+	res, err := ApiCall(ctx, true, "ModifyListener", region, func(ctx context.Context, client *clb.Client) (req *clb.ModifyListenerRequest, res *clb.ModifyListenerResponse, err error) {
+		req = clb.NewModifyListenerRequest()
+		req.LoadBalancerId = &lbId
+		req.ListenerId = &listenerId
+		req.HealthCheck = params.HealthCheck
+		if req.HealthCheck != nil { // 修改健康检查时需指定与创建时相同的源 IP 类型，否则会被重置为默认值
+			hc := *req.HealthCheck
+			hc.SourceIpType = healthCheckSourceIpType(ctx, lbId, region)
+			req.HealthCheck = &hc
+		}
+		req.Scheduler = params.Scheduler
+		req.SessionExpireTime = params.SessionExpireTime
+		req.SessionType = params.SessionType
+		req.IdleConnectTimeout = params.IdleConnectTimeout
+		req.DeregisterTargetRst = params.DeregisterTargetRst
+		res, err = client.ModifyListenerWithContext(ctx, req)
+		return
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := Wait(ctx, region, *res.Response.RequestId, "ModifyListener", DefaultWaitInterval); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// RenameListener 修改监听器名称，并更新监听器缓存
+func RenameListener(ctx context.Context, region, lbId string, lis *Listener, name string) error {
+	mu := getLbLock(lbId)
+	mu.Lock()
+	defer mu.Unlock()
+	res, err := ApiCall(ctx, true, "ModifyListener", region, func(ctx context.Context, client *clb.Client) (req *clb.ModifyListenerRequest, res *clb.ModifyListenerResponse, err error) {
+		req = clb.NewModifyListenerRequest()
+		req.LoadBalancerId = &lbId
+		req.ListenerId = &lis.ListenerId
+		req.ListenerName = &name
+		res, err = client.ModifyListenerWithContext(ctx, req)
+		return
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := Wait(ctx, region, *res.Response.RequestId, "ModifyListener", DefaultWaitInterval); err != nil {
+		return errors.WithStack(err)
+	}
+	renamed := *lis
+	renamed.ListenerName = name
+	GetListenerCache(LBKey{LbId: lbId, Region: region}).Set(&renamed)
+	return nil
+}
@@ -0,0 +1,105 @@
+package clb
+
+import (
+	"testing"
+
+	clb "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/clb/v20180317"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+)
+
+func TestMergeHealthCheck(t *testing.T) {
+	base := &networkingv1alpha1.HealthCheck{
+		Enabled:      true,
+		CheckType:    common.StringPtr("CUSTOM"),
+		IntervalTime: common.Int64Ptr(5),
+	}
+	override := &networkingv1alpha1.HealthCheck{
+		Enabled:   true,
+		CheckPort: common.Int64Ptr(9000),
+	}
+	hc := MergeHealthCheck(base, override)
+	if !hc.Enabled || *hc.CheckType != "CUSTOM" || *hc.IntervalTime != 5 || *hc.CheckPort != 9000 {
+		t.Fatalf("unexpected merged health check: %+v", hc)
+	}
+	if base.CheckPort != nil {
+		t.Fatal("base should not be modified")
+	}
+	if hc := MergeHealthCheck(base, &networkingv1alpha1.HealthCheck{Enabled: false}); hc.Enabled {
+		t.Fatal("expect health check disabled by override")
+	}
+	if MergeHealthCheck(nil, nil) != nil {
+		t.Fatal("expect nil")
+	}
+}
+
+func TestIsHealthCheckExpected(t *testing.T) {
+	actual := &clb.HealthCheck{
+		HealthSwitch: common.Int64Ptr(1),
+		CheckType:    common.StringPtr("PING"),
+		IntervalTime: common.Int64Ptr(5),
+		TimeOut:      common.Int64Ptr(2),
+	}
+	cases := []struct {
+		expected *networkingv1alpha1.HealthCheck
+		want     bool
+	}{
+		{nil, true},
+		{&networkingv1alpha1.HealthCheck{Enabled: true}, true},
+		{&networkingv1alpha1.HealthCheck{Enabled: true, CheckType: common.StringPtr("PING")}, true},
+		{&networkingv1alpha1.HealthCheck{Enabled: true, IntervalTime: common.Int64Ptr(10)}, false},
+		{&networkingv1alpha1.HealthCheck{Enabled: true, CheckPort: common.Int64Ptr(9000)}, false},
+		{&networkingv1alpha1.HealthCheck{Enabled: false}, false},
+	}
+	for i, c := range cases {
+		if got := IsHealthCheckExpected(actual, c.expected); got != c.want {
+			t.Errorf("case %d: expect %v, got %v", i, c.want, got)
+		}
+	}
+	if !IsHealthCheckExpected(&clb.HealthCheck{HealthSwitch: common.Int64Ptr(0)}, &networkingv1alpha1.HealthCheck{Enabled: false, CheckPort: common.Int64Ptr(1)}) {
+		t.Error("disabled health check should ignore other fields")
+	}
+}
+
+func TestBuildExtensiveParameters(t *testing.T) {
+	tpl := &networkingv1alpha1.ListenerTemplate{
+		Scheduler:          common.StringPtr("LEAST_CONN"),
+		SessionExpireTime:  common.Int64Ptr(30),
+		IdleConnectTimeout: common.Int64Ptr(120),
+	}
+	if got, _ := BuildExtensiveParameters(nil, "TCP"); got != "" {
+		t.Errorf("expect empty parameters, got %s", got)
+	}
+	got, err := BuildExtensiveParameters(tpl, "UDP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"Scheduler":"LEAST_CONN","SessionExpireTime":30,"IdleConnectTimeout":120}`; got != want {
+		t.Errorf("expect %s, got %s", want, got)
+	}
+	// TCP_SSL 不支持会话保持等参数
+	got, _ = BuildExtensiveParameters(tpl, "TCP_SSL")
+	if want := `{"Scheduler":"LEAST_CONN"}`; got != want {
+		t.Errorf("expect %s, got %s", want, got)
+	}
+}
+
+func TestGetListenerDrift(t *testing.T) {
+	lis := &clb.Listener{
+		Scheduler:         common.StringPtr("WRR"),
+		SessionExpireTime: common.Int64Ptr(0),
+		HealthCheck:       &clb.HealthCheck{HealthSwitch: common.Int64Ptr(1)},
+	}
+	if drift := GetListenerDrift(lis, nil, "TCP"); len(drift) != 0 {
+		t.Errorf("expect no drift, got %v", drift)
+	}
+	tpl := &networkingv1alpha1.ListenerTemplate{
+		Scheduler:         common.StringPtr("WRR"),
+		SessionExpireTime: common.Int64Ptr(30),
+		HealthCheck:       &networkingv1alpha1.HealthCheck{Enabled: false},
+	}
+	drift := GetListenerDrift(lis, tpl, "TCP")
+	if len(drift) != 2 || drift[0] != "HealthCheck" || drift[1] != "SessionExpireTime" {
+		t.Errorf("unexpected drift %v", drift)
+	}
+}
@@ -33,12 +33,23 @@ func isIPv6CLB(ctx context.Context, lbId, region string) bool {
 	return ipv6
 }
 
+// healthCheckSourceIpType 返回监听器健康检查的源 IP 类型，默认使用 100.64 网段 (SourceIpType=1)，
+// IPv6 CLB 的健康检查源 IP 只能使用 VIP (SourceIpType=0)，创建和修改监听器时需保持一致
+func healthCheckSourceIpType(ctx context.Context, lbId, region string) *int64 {
+	if isIPv6CLB(ctx, lbId, region) {
+		return common.Int64Ptr(0)
+	}
+	return common.Int64Ptr(1)
+}
+
 type Listener struct {
 	Port         int64
 	EndPort      int64
 	Protocol     string
 	ListenerId   string
 	ListenerName string
+	// 监听器详情，仅从 CLB API 查询到的监听器有值，用于对账监听器的配置
+	Detail *clb.Listener
 }
 
 func convertListener(lbLis *clb.Listener) *Listener {
@@ -47,6 +58,7 @@ func convertListener(lbLis *clb.Listener) *Listener {
 		ListenerName: *lbLis.ListenerName,
 		Protocol:     *lbLis.Protocol,
 		Port:         *lbLis.Port,
+		Detail:       lbLis,
 	}
 	if lbLis.EndPort != nil {
 		lis.EndPort = *lbLis.EndPort
@@ -131,7 +143,7 @@ func GetListenerByPort(ctx context.Context, region, lbId string, port int64, pro
 // "Length of .Ports should be less than 50"），因此当预创建监听器数量
 // 超过 maxPortsPerCreateListenerRequest 时分批调用，合并所有批次的返回结果。
 // 返回的 lisIds 与传入 ports 一一对应（顺序一致），供调用方按端口回填缓存。
-func BatchCreateListener(ctx context.Context, region, lbId, protocol string, ports, endports []int64) (lisIds []string, err error) {
+func BatchCreateListener(ctx context.Context, region, lbId, protocol, extensiveParameters string, ports, endports []int64) (lisIds []string, err error) {
 	mu := getLbLock(lbId)
 	mu.Lock()
 	defer mu.Unlock()
@@ -140,14 +152,15 @@ func BatchCreateListener(ctx context.Context, region, lbId, protocol string, por
 		res, err := ApiCall(context.Background(), true, "CreateListener", region, func(ctx context.Context, client *clb.Client) (req *clb.CreateListenerRequest, res *clb.CreateListenerResponse, err error) {
 			req = clb.NewCreateListenerRequest()
 			req.LoadBalancerId = &lbId
-			// IPv6 CLB 的健康检查源 IP 只能使用 VIP (SourceIpType=0)
-			sourceIpType := int64(1)
-			if isIPv6CLB(ctx, lbId, region) {
-				sourceIpType = 0
-			}
 			req.HealthCheck = &clb.HealthCheck{
 				HealthSwitch: common.Int64Ptr(0),
-				SourceIpType: &sourceIpType,
+				SourceIpType: healthCheckSourceIpType(ctx, lbId, region),
+			}
+			if extensiveParameters != "" {
+				if err = json.Unmarshal([]byte(extensiveParameters), req); err != nil {
+					err = errors.WithStack(err)
+					return
+				}
 			}
 			req.Protocol = &protocol
 			for _, port := range batchPorts {
@@ -194,13 +207,12 @@ func BatchCreateListener(ctx context.Context, region, lbId, protocol string, por
 	return lisIds, nil
 }
 
-func CreateListenerTryBatch(ctx context.Context, region, lbId string, port, endPort int64, protocol string, certId *string, extensiveParameters string) (id string, err error) {
-	cid := ""
-	if certId != nil {
-		cid = *certId
+func CreateListenerTryBatch(ctx context.Context, region, lbId string, port, endPort int64, protocol string, cert *Certificate, extensiveParameters, listenerName string) (id string, err error) {
+	if listenerName == "" {
+		listenerName = TkeListenerName
 	}
 	if endPort > 0 {
-		id, err = CreateListener(ctx, region, lbId, port, endPort, protocol, cid, extensiveParameters, TkeListenerName)
+		id, err = CreateListener(ctx, region, lbId, port, endPort, protocol, cert, extensiveParameters, listenerName)
 		if err != nil {
 			err = errors.WithStack(err)
 		}
@@ -210,10 +222,11 @@ func CreateListenerTryBatch(ctx context.Context, region, lbId string, port, endP
 		Ctx:                 ctx,
 		Region:              region,
 		LbId:                lbId,
-		CertId:              cid,
+		Cert:                cert,
 		Port:                port,
 		Protocol:            protocol,
 		ExtensiveParameters: extensiveParameters,
+		ListenerName:        listenerName,
 		Result:              make(chan *ListenerResult),
 	}
 	startTime := time.Now()
@@ -225,22 +238,14 @@ func CreateListenerTryBatch(ctx context.Context, region, lbId string, port, endP
 	return
 }
 
-func CreateListener(ctx context.Context, region, lbId string, port, endPort int64, protocol, certId, extensiveParameters, listenerName string) (id string, err error) {
+func CreateListener(ctx context.Context, region, lbId string, port, endPort int64, protocol string, cert *Certificate, extensiveParameters, listenerName string) (id string, err error) {
 	req := clb.NewCreateListenerRequest()
-	// IPv6 CLB 的健康检查源 IP 只能使用 VIP (SourceIpType=0)
-	sourceIpType := int64(1)
-	if isIPv6CLB(ctx, lbId, region) {
-		sourceIpType = 0
-	}
 	req.HealthCheck = &clb.HealthCheck{
 		HealthSwitch: common.Int64Ptr(0),
-		SourceIpType: &sourceIpType,
+		SourceIpType: healthCheckSourceIpType(ctx, lbId, region),
 	}
-	if certId != "" {
-		req.Certificate = &clb.CertificateInput{
-			SSLMode: common.StringPtr("UNIDIRECTIONAL"),
-			CertId:  &certId,
-		}
+	if cert != nil {
+		req.Certificate, req.MultiCertInfo = cert.Input()
 	}
 	if extensiveParameters != "" {
 		err = json.Unmarshal([]byte(extensiveParameters), req)
@@ -352,3 +357,34 @@ func DeleteListener(ctx context.Context, region, lbId, listenerId string) error
 	}
 	return nil
 }
+
+// ModifyListenersCert 原地更新同一个 lb 上多个监听器的证书（listenerId -> 证书配置），持有一次 lb 锁批量发起修改后统一等待任务完成
+func ModifyListenersCert(ctx context.Context, region, lbId string, certs map[string]*Certificate) error {
+	if len(certs) == 0 {
+		return nil
+	}
+	mu := getLbLock(lbId)
+	mu.Lock()
+	defer mu.Unlock()
+	reqIds := []string{}
+	for listenerId, cert := range certs {
+		res, err := ApiCall(ctx, true, "ModifyListener", region, func(ctx context.Context, client *clb.Client) (req *clb.ModifyListenerRequest, res *clb.ModifyListenerResponse, err error) {
+			req = clb.NewModifyListenerRequest()
+			req.LoadBalancerId = &lbId
+			req.ListenerId = &listenerId
+			req.Certificate, req.MultiCertInfo = cert.Input()
+			res, err = client.ModifyListenerWithContext(ctx, req)
+			return
+		})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		reqIds = append(reqIds, *res.Response.RequestId)
+	}
+	for _, reqId := range reqIds {
+		if _, err := Wait(ctx, region, reqId, "ModifyListener", DefaultWaitInterval); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
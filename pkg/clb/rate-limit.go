@@ -18,6 +18,8 @@ var envMap = map[string]string{
 	"API_RATELIMIT_DESCRIBE_TARGETS":               "DescribeTargets",
 	"API_RATELIMIT_BATCH_DEREGISTER_TARGETS":       "BatchDeregisterTargets",
 	"API_RATELIMIT_DESCRIBE_TASK_STATUS":           "DescribeTaskStatus",
+	"API_RATELIMIT_MODIFY_LISTENER":                "ModifyListener",
+	"API_RATELIMIT_MODIFY_TARGET_WEIGHT":           "ModifyTargetWeight",
 }
 
 func init() {
@@ -0,0 +1,287 @@
+package clb
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+	clb "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/clb/v20180317"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+)
+
+// Rule 七层监听器上的转发规则
+type Rule struct {
+	LocationId string
+	Domain     string
+	Url        string
+}
+
+// 七层共享监听器的创建锁，key 为 lbId/port
+var layer7ListenerLocks sync.Map
+
+// EnsureLayer7Listener 确保 lb 上存在指定端口的七层（HTTP/HTTPS）监听器，不存在则创建，返回监听器 ID。
+// 七层监听器被多个转发规则共用，同一 lb 端口的并发调用串行执行，避免重复创建。
+func EnsureLayer7Listener(ctx context.Context, region, lbId string, port uint16, protocol, certId, listenerName string) (string, error) {
+	v, _ := layer7ListenerLocks.LoadOrStore(fmt.Sprintf("%s/%d", lbId, port), &sync.Mutex{})
+	mu := v.(*sync.Mutex)
+	mu.Lock()
+	defer mu.Unlock()
+
+	lisCache := GetListenerCache(LBKey{LbId: lbId, Region: region})
+	lis, err := lisCache.Get(ctx, port, protocol, false)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	if lis != nil {
+		return lis.ListenerId, nil
+	}
+	lisId, err := createLayer7Listener(ctx, region, lbId, port, protocol, certId, listenerName)
+	if err != nil {
+		if !IsPortCheckFailedError(err) {
+			return "", errors.WithStack(err)
+		}
+		// 端口已被占用（缓存过期），重新查询
+		lis, err = GetListenerByPort(ctx, region, lbId, int64(port), protocol)
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		if lis == nil {
+			return "", errors.Errorf("port %d of lb %s is occupied by other non-%s listener", port, lbId, protocol)
+		}
+		lisCache.Set(lis)
+		return lis.ListenerId, nil
+	}
+	lisCache.Set(&Listener{
+		Port:         int64(port),
+		Protocol:     protocol,
+		ListenerId:   lisId,
+		ListenerName: listenerName,
+	})
+	return lisId, nil
+}
+
+// 创建七层监听器，七层监听器的健康检查在转发规则上配置，HTTPS 监听器需指定服务端证书
+func createLayer7Listener(ctx context.Context, region, lbId string, port uint16, protocol, certId, listenerName string) (string, error) {
+	mu := getLbLock(lbId)
+	mu.Lock()
+	defer mu.Unlock()
+	res, err := ApiCall(ctx, true, "CreateListener", region, func(ctx context.Context, client *clb.Client) (req *clb.CreateListenerRequest, res *clb.CreateListenerResponse, err error) {
+		req = clb.NewCreateListenerRequest()
+		req.LoadBalancerId = &lbId
+		req.Ports = []*int64{common.Int64Ptr(int64(port))}
+		req.Protocol = &protocol
+		req.ListenerNames = []*string{&listenerName}
+		if certId != "" {
+			req.Certificate = &clb.CertificateInput{
+				SSLMode: common.StringPtr("UNIDIRECTIONAL"),
+				CertId:  &certId,
+			}
+		}
+		res, err = client.CreateListenerWithContext(ctx, req)
+		return
+	})
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	if len(res.Response.ListenerIds) != 1 {
+		return "", errors.Errorf("found %d listeners created", len(res.Response.ListenerIds))
+	}
+	if _, err := Wait(ctx, region, *res.Response.RequestId, "CreateListener", DefaultWaitInterval); err != nil {
+		return "", errors.WithStack(err)
+	}
+	return *res.Response.ListenerIds[0], nil
+}
+
+// GetRule 通过域名和 URL 查询七层监听器上的转发规则，不存在返回 nil
+func GetRule(ctx context.Context, region, lbId, listenerId, domain, url string) (*Rule, error) {
+	res, err := ApiCall(ctx, false, "DescribeListeners", region, func(ctx context.Context, client *clb.Client) (req *clb.DescribeListenersRequest, res *clb.DescribeListenersResponse, err error) {
+		req = clb.NewDescribeListenersRequest()
+		req.LoadBalancerId = &lbId
+		req.ListenerIds = []*string{&listenerId}
+		res, err = client.DescribeListenersWithContext(ctx, req)
+		return
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for _, lis := range res.Response.Listeners {
+		for _, rule := range lis.Rules {
+			if rule.LocationId != nil && rule.Domain != nil && *rule.Domain == domain && rule.Url != nil && *rule.Url == url {
+				return &Rule{LocationId: *rule.LocationId, Domain: domain, Url: url}, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// CreateRule 在七层监听器上创建转发规则，返回转发规则 ID。转发规则默认关闭健康检查，与四层监听器保持一致。
+func CreateRule(ctx context.Context, region, lbId, listenerId, domain, url string) (string, error) {
+	mu := getLbLock(lbId)
+	mu.Lock()
+	defer mu.Unlock()
+	res, err := ApiCall(ctx, true, "CreateRule", region, func(ctx context.Context, client *clb.Client) (req *clb.CreateRuleRequest, res *clb.CreateRuleResponse, err error) {
+		req = clb.NewCreateRuleRequest()
+		req.LoadBalancerId = &lbId
+		req.ListenerId = &listenerId
+		req.Rules = []*clb.RuleInput{
+			{
+				Domain: &domain,
+				Url:    &url,
+				HealthCheck: &clb.HealthCheck{
+					HealthSwitch: common.Int64Ptr(0),
+				},
+			},
+		}
+		res, err = client.CreateRuleWithContext(ctx, req)
+		return
+	})
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	if len(res.Response.LocationIds) != 1 {
+		return "", errors.Errorf("found %d rules created", len(res.Response.LocationIds))
+	}
+	if _, err := Wait(ctx, region, *res.Response.RequestId, "CreateRule", DefaultWaitInterval); err != nil {
+		return "", errors.WithStack(err)
+	}
+	return *res.Response.LocationIds[0], nil
+}
+
+// DeleteRule 删除七层监听器上的转发规则
+func DeleteRule(ctx context.Context, region, lbId, listenerId, locationId string) error {
+	mu := getLbLock(lbId)
+	mu.Lock()
+	defer mu.Unlock()
+	res, err := ApiCall(ctx, true, "DeleteRule", region, func(ctx context.Context, client *clb.Client) (req *clb.DeleteRuleRequest, res *clb.DeleteRuleResponse, err error) {
+		req = clb.NewDeleteRuleRequest()
+		req.LoadBalancerId = &lbId
+		req.ListenerId = &listenerId
+		req.LocationIds = []*string{&locationId}
+		res, err = client.DeleteRuleWithContext(ctx, req)
+		return
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := Wait(ctx, region, *res.Response.RequestId, "DeleteRule", DefaultWaitInterval); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// DescribeRuleTargets 查询转发规则绑定的后端
+func DescribeRuleTargets(ctx context.Context, region, lbId, listenerId, locationId string) (targets []*Target, err error) {
+	res, err := ApiCall(ctx, false, "DescribeTargets", region, func(ctx context.Context, client *clb.Client) (req *clb.DescribeTargetsRequest, res *clb.DescribeTargetsResponse, err error) {
+		req = clb.NewDescribeTargetsRequest()
+		req.LoadBalancerId = &lbId
+		req.ListenerIds = []*string{&listenerId}
+		res, err = client.DescribeTargetsWithContext(ctx, req)
+		return
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for _, lis := range res.Response.Listeners {
+		for _, rule := range lis.Rules {
+			if rule.LocationId == nil || *rule.LocationId != locationId {
+				continue
+			}
+			for _, backend := range rule.Targets {
+				for _, ip := range backend.PrivateIpAddresses {
+					targets = append(targets, &Target{TargetIP: *ip, TargetPort: *backend.Port, Weight: backend.Weight})
+				}
+			}
+		}
+	}
+	return
+}
+
+// RegisterRuleTargets 将后端绑定到转发规则
+func RegisterRuleTargets(ctx context.Context, region, lbId, listenerId, locationId string, targets ...Target) error {
+	if len(targets) == 0 {
+		return nil
+	}
+	mu := getLbLock(lbId)
+	mu.Lock()
+	defer mu.Unlock()
+	res, err := ApiCall(ctx, true, "RegisterTargets", region, func(ctx context.Context, client *clb.Client) (req *clb.RegisterTargetsRequest, res *clb.RegisterTargetsResponse, err error) {
+		req = clb.NewRegisterTargetsRequest()
+		req.LoadBalancerId = &lbId
+		req.ListenerId = &listenerId
+		req.LocationId = &locationId
+		req.Targets = getClbTargets(targets)
+		res, err = client.RegisterTargetsWithContext(ctx, req)
+		return
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := Wait(ctx, region, *res.Response.RequestId, "RegisterTargets", DefaultWaitInterval); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// DeregisterRuleTargets 将后端从转发规则解绑
+func DeregisterRuleTargets(ctx context.Context, region, lbId, listenerId, locationId string, targets ...*Target) error {
+	if len(targets) == 0 {
+		return nil
+	}
+	mu := getLbLock(lbId)
+	mu.Lock()
+	defer mu.Unlock()
+	res, err := ApiCall(ctx, true, "DeregisterTargets", region, func(ctx context.Context, client *clb.Client) (req *clb.DeregisterTargetsRequest, res *clb.DeregisterTargetsResponse, err error) {
+		req = clb.NewDeregisterTargetsRequest()
+		req.LoadBalancerId = &lbId
+		req.ListenerId = &listenerId
+		req.LocationId = &locationId
+		for _, target := range targets {
+			req.Targets = append(req.Targets, &clb.Target{
+				Port:  &target.TargetPort,
+				EniIp: &target.TargetIP,
+			})
+		}
+		res, err = client.DeregisterTargetsWithContext(ctx, req)
+		return
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := Wait(ctx, region, *res.Response.RequestId, "DeregisterTargets", DefaultWaitInterval); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// ModifyRuleTargetWeight 修改转发规则后端的权重，权重为 0 时 CLB 不再向后端转发新请求
+func ModifyRuleTargetWeight(ctx context.Context, region, lbId, listenerId, locationId string, weight int64, targets ...*Target) error {
+	if len(targets) == 0 {
+		return nil
+	}
+	mu := getLbLock(lbId)
+	mu.Lock()
+	defer mu.Unlock()
+	res, err := ApiCall(ctx, true, "ModifyTargetWeight", region, func(ctx context.Context, client *clb.Client) (req *clb.ModifyTargetWeightRequest, res *clb.ModifyTargetWeightResponse, err error) {
+		req = clb.NewModifyTargetWeightRequest()
+		req.LoadBalancerId = &lbId
+		req.ListenerId = &listenerId
+		req.LocationId = &locationId
+		req.Weight = &weight
+		for _, target := range targets {
+			req.Targets = append(req.Targets, &clb.Target{
+				Port:  &target.TargetPort,
+				EniIp: &target.TargetIP,
+			})
+		}
+		res, err = client.ModifyTargetWeightWithContext(ctx, req)
+		return
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := Wait(ctx, region, *res.Response.RequestId, "ModifyTargetWeight", DefaultWaitInterval); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
@@ -54,12 +54,22 @@ type BatchTarget struct {
 type Target struct {
 	TargetIP   string
 	TargetPort int64
+	// 权重，为空时使用 CLB 的默认权重
+	Weight *int64
 }
 
 func (t Target) String() string {
 	return fmt.Sprintf("%s:%d", t.TargetIP, t.TargetPort)
 }
 
+// IsSameBackend 判断是否为同一个后端（忽略权重）
+func (t Target) IsSameBackend(other Target) bool {
+	return t.TargetIP == other.TargetIP && t.TargetPort == other.TargetPort
+}
+
+// CLB 后端的默认权重
+const DefaultTargetWeight int64 = 10
+
 func DeregisterAllTargetsTryBatch(ctx context.Context, region, lbId, listenerId string) error {
 	targets, err := DescribeTargetsTryBatch(ctx, region, lbId, listenerId)
 	if err != nil {
@@ -94,8 +104,9 @@ func DeregisterTargetsForListener(ctx context.Context, region, lbId, listenerId
 func getClbTargets(targets []Target) (clbTargets []*clb.Target) {
 	for _, target := range targets {
 		clbTargets = append(clbTargets, &clb.Target{
-			Port:  &target.TargetPort,
-			EniIp: &target.TargetIP,
+			Port:   &target.TargetPort,
+			EniIp:  &target.TargetIP,
+			Weight: target.Weight,
 		})
 	}
 	return
@@ -121,6 +132,37 @@ func RegisterTargets(ctx context.Context, region, lbId, listenerId string, targe
 	return err
 }
 
+// ModifyTargetWeight 修改监听器后端的权重，权重为 0 时 CLB 不再向后端转发新连接，存量连接不受影响
+func ModifyTargetWeight(ctx context.Context, region, lbId, listenerId string, weight int64, targets ...*Target) error {
+	if len(targets) == 0 {
+		return nil
+	}
+	mu := getLbLock(lbId)
+	mu.Lock()
+	defer mu.Unlock()
+	res, err := ApiCall(ctx, true, "ModifyTargetWeight", region, func(ctx context.Context, client *clb.Client) (req *clb.ModifyTargetWeightRequest, res *clb.ModifyTargetWeightResponse, err error) {
+		req = clb.NewModifyTargetWeightRequest()
+		req.LoadBalancerId = &lbId
+		req.ListenerId = &listenerId
+		req.Weight = &weight
+		for _, target := range targets {
+			req.Targets = append(req.Targets, &clb.Target{
+				Port:  &target.TargetPort,
+				EniIp: &target.TargetIP,
+			})
+		}
+		res, err = client.ModifyTargetWeightWithContext(ctx, req)
+		return
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := Wait(ctx, region, *res.Response.RequestId, "ModifyTargetWeight", DefaultWaitInterval); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
 func RegisterTarget(ctx context.Context, region, lbId, listenerId string, target Target) error {
 	task := &RegisterTargetTask{
 		Ctx:        ctx,
@@ -168,7 +210,7 @@ func DescribeTargets(ctx context.Context, region, lbId, listenerId string) (targ
 	for _, lis := range resp.Response.Listeners {
 		for _, backend := range lis.Targets {
 			for _, ip := range backend.PrivateIpAddresses {
-				targets = append(targets, Target{TargetIP: *ip, TargetPort: *backend.Port})
+				targets = append(targets, Target{TargetIP: *ip, TargetPort: *backend.Port, Weight: backend.Weight})
 			}
 		}
 	}
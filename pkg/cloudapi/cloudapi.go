@@ -2,6 +2,7 @@ package cloudapi
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
 	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/profile"
@@ -14,6 +15,8 @@ var (
 	credential *common.Credential
 	// endpointSuffix 云 API 域名后缀，测试环境为 "test"（如 clb.test.tencentcloudapi.com），为空表示现网
 	endpointSuffix string
+	// transport 所有云 API client 使用的 HTTP transport，为空时使用 SDK 默认的 transport（影子模式下用于拦截写操作）
+	transport http.RoundTripper
 )
 
 func Init(secretId, secretKey string) {
@@ -46,3 +49,16 @@ func NewClientProfile(service string) *profile.ClientProfile {
 	}
 	return p
 }
+
+// SetTransport 设置所有云 API client 使用的 HTTP transport，需在创建云 API client 之前调用
+func SetTransport(t http.RoundTripper) {
+	transport = t
+}
+
+// InitClient 对新创建的云 API client 应用统一的设置，所有会调用写操作的云 API client 创建后都需要调用，
+// 保证影子模式下 CLB、VPC、SSL 等产品的写操作都能被拦截
+func InitClient(c *common.Client) {
+	if transport != nil {
+		c.WithHttpTransport(transport)
+	}
+}
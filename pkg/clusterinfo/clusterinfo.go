@@ -5,4 +5,8 @@ var Region, VpcId, ClusterId string
 var (
 	AgonesSupported bool
 	OKGSupported    bool
+	// 集群中安装了 Gateway API 的 GatewayClass 和 Gateway CRD
+	GatewayAPISupported bool
+	// 集群中已安装的 Gateway API 路由类型（TCPRoute/UDPRoute/TLSRoute）
+	GatewayRouteKinds []string
 )
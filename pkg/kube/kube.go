@@ -11,6 +11,11 @@ var (
 )
 
 func Init(mgr ctrl.Manager) {
-	apiClient = mgr.GetClient()
-	apiReader = mgr.GetAPIReader()
+	SetClient(mgr.GetClient(), mgr.GetAPIReader())
+}
+
+// SetClient 直接设置使用的 client，用于不启动 Manager 的场景（如命令行子命令）
+func SetClient(c client.Client, reader client.Reader) {
+	apiClient = c
+	apiReader = reader
 }
@@ -2,12 +2,22 @@ package kube
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/pkg/errors"
+	"github.com/tkestack/tke-extend-network-controller/internal/constant"
+	"github.com/tkestack/tke-extend-network-controller/pkg/ssl"
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// GetCertIdFromSecret 获取 Secret 中的证书 ID：优先使用 qcloud_cert_id 字段，kubernetes.io/tls 类型的 Secret
+// 自动将证书上传到 SSL 证书服务，证书 ID 和指纹记录在 Secret 的注解中，证书不变时直接复用
 func GetCertIdFromSecret(ctx context.Context, apiClient client.Client, key client.ObjectKey) (string, error) {
 	secret := &corev1.Secret{}
 	if err := apiClient.Get(ctx, key, secret); err != nil {
@@ -17,5 +27,146 @@ func GetCertIdFromSecret(ctx context.Context, apiClient client.Client, key clien
 	if len(certId) > 0 {
 		return string(certId), nil
 	}
+	if IsTLSSecret(secret) {
+		id, err := ensureCertUploaded(ctx, apiClient, secret, &uploadCert{
+			certType:       ssl.CertificateTypeServer,
+			certPem:        secret.Data[corev1.TLSCertKey],
+			keyPem:         secret.Data[corev1.TLSPrivateKeyKey],
+			idKey:          constant.CertIdKey,
+			fingerprintKey: constant.CertFingerprintKey,
+		})
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		return id, nil
+	}
+	return "", nil
+}
+
+// GetCACertIdFromSecret 获取 Secret 中用于双向认证的客户端 CA 证书 ID：优先使用 qcloud_ca_cert_id 字段，
+// 否则将 ca.crt 字段中的 CA 证书自动上传到 SSL 证书服务，证书 ID 和指纹同样记录在 Secret 的注解中
+func GetCACertIdFromSecret(ctx context.Context, apiClient client.Client, key client.ObjectKey) (string, error) {
+	secret := &corev1.Secret{}
+	if err := apiClient.Get(ctx, key, secret); err != nil {
+		return "", errors.WithStack(err)
+	}
+	certId := secret.Data["qcloud_ca_cert_id"]
+	if len(certId) > 0 {
+		return string(certId), nil
+	}
+	if len(secret.Data[caCertKey]) > 0 {
+		id, err := ensureCertUploaded(ctx, apiClient, secret, &uploadCert{
+			certType:       ssl.CertificateTypeCA,
+			certPem:        secret.Data[caCertKey],
+			idKey:          constant.CACertIdKey,
+			fingerprintKey: constant.CACertFingerprintKey,
+		})
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		return id, nil
+	}
 	return "", nil
 }
+
+// cert-manager 等签发证书时 CA 证书所在的字段
+const caCertKey = "ca.crt"
+
+// HasCACert 判断 Secret 中是否包含客户端 CA 证书：qcloud_ca_cert_id 字段或 ca.crt 字段中的 CA 证书
+func HasCACert(secret *corev1.Secret) bool {
+	return len(secret.Data["qcloud_ca_cert_id"]) > 0 || len(secret.Data[caCertKey]) > 0
+}
+
+// HasCert 判断 Secret 中是否包含证书：qcloud_cert_id 字段或 kubernetes.io/tls 类型 Secret 中的证书和私钥
+func HasCert(secret *corev1.Secret) bool {
+	return len(secret.Data["qcloud_cert_id"]) > 0 || IsTLSSecret(secret)
+}
+
+// IsTLSSecret 判断是否为包含证书和私钥的 kubernetes.io/tls 类型 Secret（如 cert-manager 签发的证书）
+func IsTLSSecret(secret *corev1.Secret) bool {
+	return secret.Type == corev1.SecretTypeTLS && len(secret.Data[corev1.TLSCertKey]) > 0 && len(secret.Data[corev1.TLSPrivateKeyKey]) > 0
+}
+
+// CertFingerprint 计算 kubernetes.io/tls 类型 Secret 中证书的指纹，用于判断证书是否变化
+func CertFingerprint(secret *corev1.Secret) string {
+	return fingerprintOf(secret.Data[corev1.TLSCertKey])
+}
+
+func fingerprintOf(certPem []byte) string {
+	sum := sha256.Sum256(certPem)
+	return hex.EncodeToString(sum[:])
+}
+
+var (
+	// 上传证书的锁，避免多个 CLBBinding 并发上传同一个证书
+	certUploadLock sync.Mutex
+	// 已上传的证书，key 为证书类型和指纹，value 为证书 ID，避免 Secret 注解还未同步到缓存时重复上传
+	uploadedCerts sync.Map
+)
+
+// 待上传的证书，服务端证书和 CA 证书的 ID 和指纹记录在不同的注解中，同一个 Secret 可以同时提供两者
+type uploadCert struct {
+	certType       string
+	certPem        []byte
+	keyPem         []byte
+	idKey          string
+	fingerprintKey string
+}
+
+func ensureCertUploaded(ctx context.Context, apiClient client.Client, secret *corev1.Secret, cert *uploadCert) (string, error) {
+	fingerprint := fingerprintOf(cert.certPem)
+	annotations := secret.GetAnnotations()
+	if certId := annotations[cert.idKey]; certId != "" && annotations[cert.fingerprintKey] == fingerprint {
+		return certId, nil
+	}
+	certUploadLock.Lock()
+	defer certUploadLock.Unlock()
+	certId := ""
+	cacheKey := cert.certType + "/" + fingerprint
+	if v, ok := uploadedCerts.Load(cacheKey); ok {
+		certId = v.(string)
+	} else {
+		id, err := ssl.UploadCertificate(ctx, cert.certType, string(cert.certPem), string(cert.keyPem), fmt.Sprintf("%s/%s", secret.Namespace, secret.Name))
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		certId = id
+		uploadedCerts.Store(cacheKey, certId)
+	}
+	// 记录证书 ID 和指纹，证书变化时旧证书记录到待清理列表，没有监听器引用后由 GC 清理；
+	// 打上标签方便 GC 只查询上传过证书的 Secret
+	newAnnotations := map[string]string{
+		cert.idKey:          certId,
+		cert.fingerprintKey: fingerprint,
+	}
+	if oldCertId := annotations[cert.idKey]; oldCertId != "" && oldCertId != certId {
+		staleCertIds := GetStaleCertIds(secret)
+		if !slices.Contains(staleCertIds, oldCertId) {
+			staleCertIds = append(staleCertIds, oldCertId)
+		}
+		newAnnotations[constant.StaleCertIdsKey] = strings.Join(staleCertIds, ",")
+	}
+	patchMap := map[string]any{
+		"metadata": map[string]any{
+			"labels": map[string]string{
+				constant.ManagedCertLabelKey: "true",
+			},
+			"annotations": newAnnotations,
+		},
+	}
+	if err := PatchMap(ctx, apiClient, secret, patchMap); err != nil {
+		return "", errors.WithStack(err)
+	}
+	return certId, nil
+}
+
+// GetStaleCertIds 获取 Secret 中记录的待清理的旧证书 ID
+func GetStaleCertIds(secret *corev1.Secret) []string {
+	ids := []string{}
+	for _, id := range strings.Split(secret.GetAnnotations()[constant.StaleCertIdsKey], ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
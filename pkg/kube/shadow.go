@@ -0,0 +1,260 @@
+package kube
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// NewShadowClient 创建影子模式（--dry-run-cloud）下使用的 client：所有写操作都以 dry-run 方式提交，不会修改集群中的对象，
+// 写入后的对象保存在内存中。读取时如果集群中的对象没有被其它组件（如线上的控制器）修改过，返回内存中的版本，
+// 从而影子控制器能看到自己写入的 status 和 finalizer，不会因为写入没有落盘而在每次对账时重复分配端口、重复记录相同的云 API 写操作。
+// 集群中的对象被修改后以集群中的为准，丢弃内存中的版本。
+func NewShadowClient(c client.Client) client.Client {
+	return &shadowClient{
+		Client:  client.NewDryRunClient(c),
+		objects: make(map[shadowKey]*shadowObject),
+	}
+}
+
+type shadowKey struct {
+	gvk schema.GroupVersionKind
+	key client.ObjectKey
+}
+
+// 影子控制器写入的对象
+type shadowObject struct {
+	typ reflect.Type
+	// 写入时集群中对象的 resourceVersion，集群中的对象 resourceVersion 变化后内存中的版本失效
+	baseRV string
+	// 对象只在内存中创建过，集群中不存在
+	created bool
+	// 写入后的对象，为空表示已被删除
+	obj client.Object
+}
+
+type shadowClient struct {
+	client.Client
+	mu      sync.Mutex
+	objects map[shadowKey]*shadowObject
+}
+
+func (c *shadowClient) keyOf(obj runtime.Object, key client.ObjectKey) (shadowKey, bool) {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return shadowKey{}, false
+	}
+	return shadowKey{gvk: gvk, key: key}, true
+}
+
+// 根据内存中的版本决定读取到的对象，返回 false 表示对象不存在，调用时需持有锁
+func (c *shadowClient) resolve(sk shadowKey, real client.Object, found bool) (client.Object, bool) {
+	so := c.objects[sk]
+	if so == nil || so.typ != reflect.TypeOf(real) {
+		return real, found
+	}
+	switch {
+	case !found && !so.created: // 集群中的对象已被删除
+		delete(c.objects, sk)
+		return nil, false
+	case found && (so.created || real.GetResourceVersion() != so.baseRV): // 集群中的对象已被其它组件创建或修改
+		delete(c.objects, sk)
+		return real, true
+	case so.obj == nil:
+		return nil, false
+	default:
+		return so.obj.DeepCopyObject().(client.Object), true
+	}
+}
+
+// 保存写入后的对象，没有 finalizer 的删除中对象视为已删除
+func (c *shadowClient) store(sk shadowKey, obj client.Object, baseRV string, created bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	so := &shadowObject{typ: reflect.TypeOf(obj), baseRV: baseRV, created: created}
+	if obj.GetDeletionTimestamp() == nil || len(obj.GetFinalizers()) > 0 {
+		so.obj = obj.DeepCopyObject().(client.Object)
+		so.obj.SetResourceVersion(baseRV)
+	} else if created {
+		delete(c.objects, sk)
+		return
+	}
+	c.objects[sk] = so
+}
+
+func (c *shadowClient) isCreated(sk shadowKey) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	so := c.objects[sk]
+	return so != nil && so.created
+}
+
+func (c *shadowClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	err := c.Client.Get(ctx, key, obj, opts...)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	sk, ok := c.keyOf(obj, key)
+	if !ok {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result, exists := c.resolve(sk, obj, err == nil)
+	if !exists {
+		if err != nil {
+			return err
+		}
+		return apierrors.NewNotFound(schema.GroupResource{Group: sk.gvk.Group, Resource: strings.ToLower(sk.gvk.Kind)}, key.Name)
+	}
+	if result != obj {
+		reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(result).Elem())
+	}
+	return nil
+}
+
+func (c *shadowClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if err := c.Client.List(ctx, list, opts...); err != nil {
+		return err
+	}
+	gvk, err := apiutil.GVKForObject(list, c.Scheme())
+	if err != nil {
+		return nil
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.objects) == 0 {
+		return nil
+	}
+	result := make([]runtime.Object, 0, len(items))
+	seen := make(map[client.ObjectKey]bool)
+	for _, item := range items {
+		obj, ok := item.(client.Object)
+		if !ok {
+			result = append(result, item)
+			continue
+		}
+		key := client.ObjectKeyFromObject(obj)
+		seen[key] = true
+		if o, exists := c.resolve(shadowKey{gvk: gvk, key: key}, obj, true); exists {
+			result = append(result, o)
+		}
+	}
+	// 补充只在内存中创建的对象，无法判断字段选择器是否匹配，使用字段选择器时不补充
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	if _, isMeta := list.(*metav1.PartialObjectMetadataList); !isMeta && listOpts.FieldSelector == nil {
+		for sk, so := range c.objects {
+			if sk.gvk != gvk || !so.created || so.obj == nil || seen[sk.key] {
+				continue
+			}
+			if listOpts.Namespace != "" && sk.key.Namespace != listOpts.Namespace {
+				continue
+			}
+			if listOpts.LabelSelector != nil && !listOpts.LabelSelector.Matches(labels.Set(so.obj.GetLabels())) {
+				continue
+			}
+			result = append(result, so.obj.DeepCopyObject())
+		}
+	}
+	return meta.SetList(list, result)
+}
+
+func (c *shadowClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if err := c.Client.Create(ctx, obj, opts...); err != nil {
+		return err
+	}
+	if sk, ok := c.keyOf(obj, client.ObjectKeyFromObject(obj)); ok {
+		c.store(sk, obj, "", true)
+	}
+	return nil
+}
+
+// 提交 dry-run 写操作并保存写入后的对象，只在内存中创建的对象不提交到 apiserver
+func (c *shadowClient) write(obj client.Object, do func() error) error {
+	sk, ok := c.keyOf(obj, client.ObjectKeyFromObject(obj))
+	if ok && c.isCreated(sk) {
+		c.store(sk, obj, "", true)
+		return nil
+	}
+	baseRV := obj.GetResourceVersion()
+	if err := do(); err != nil {
+		return err
+	}
+	if ok && baseRV != "" {
+		obj.SetResourceVersion(baseRV)
+		c.store(sk, obj, baseRV, false)
+	}
+	return nil
+}
+
+func (c *shadowClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return c.write(obj, func() error { return c.Client.Update(ctx, obj, opts...) })
+}
+
+// Patch 写入后的对象取自 apiserver 返回的 dry-run 结果，只在内存中创建的对象直接保存调用方传入的对象
+func (c *shadowClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return c.write(obj, func() error { return c.Client.Patch(ctx, obj, patch, opts...) })
+}
+
+func (c *shadowClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	sk, ok := c.keyOf(obj, client.ObjectKeyFromObject(obj))
+	if ok && c.isCreated(sk) {
+		c.mu.Lock()
+		delete(c.objects, sk)
+		c.mu.Unlock()
+		return nil
+	}
+	if err := c.Client.Delete(ctx, obj, opts...); err != nil {
+		return err
+	}
+	if !ok || obj.GetResourceVersion() == "" {
+		return nil
+	}
+	// 有 finalizer 的对象只标记删除
+	deleted := obj.DeepCopyObject().(client.Object)
+	if deleted.GetDeletionTimestamp() == nil {
+		now := metav1.Now()
+		deleted.SetDeletionTimestamp(&now)
+	}
+	if len(deleted.GetFinalizers()) > 0 {
+		c.store(sk, deleted, obj.GetResourceVersion(), false)
+	} else {
+		c.mu.Lock()
+		c.objects[sk] = &shadowObject{typ: reflect.TypeOf(obj), baseRV: obj.GetResourceVersion()}
+		c.mu.Unlock()
+	}
+	return nil
+}
+
+func (c *shadowClient) Status() client.SubResourceWriter {
+	return &shadowStatusWriter{SubResourceWriter: c.Client.Status(), c: c}
+}
+
+type shadowStatusWriter struct {
+	client.SubResourceWriter
+	c *shadowClient
+}
+
+func (w *shadowStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	return w.c.write(obj, func() error { return w.SubResourceWriter.Update(ctx, obj, opts...) })
+}
+
+func (w *shadowStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	return w.c.write(obj, func() error { return w.SubResourceWriter.Patch(ctx, obj, patch, opts...) })
+}
@@ -0,0 +1,112 @@
+package kube
+
+import (
+	"context"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	networkingv1alpha1 "github.com/tkestack/tke-extend-network-controller/api/v1alpha1"
+)
+
+func TestShadowClient(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := networkingv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	pb := &networkingv1alpha1.CLBPodBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-0"}}
+	cluster := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pb).WithStatusSubresource(pb).Build()
+	c := NewShadowClient(cluster)
+
+	// 写入的 status 和 finalizer 不落盘，但影子 client 读取到的是自己写入的版本
+	got := &networkingv1alpha1.CLBPodBinding{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pb), got); err != nil {
+		t.Fatal(err)
+	}
+	got.Finalizers = []string{"test-finalizer"}
+	if err := c.Update(ctx, got); err != nil {
+		t.Fatal(err)
+	}
+	got.Status.State = networkingv1alpha1.CLBBindingStateBound
+	if err := c.Status().Update(ctx, got); err != nil {
+		t.Fatal(err)
+	}
+	stored := &networkingv1alpha1.CLBPodBinding{}
+	if err := cluster.Get(ctx, client.ObjectKeyFromObject(pb), stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status.State != "" || len(stored.Finalizers) != 0 {
+		t.Fatalf("expect writes not persisted, got %+v", stored)
+	}
+	got = &networkingv1alpha1.CLBPodBinding{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pb), got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.State != networkingv1alpha1.CLBBindingStateBound || len(got.Finalizers) != 1 || got.ResourceVersion != stored.ResourceVersion {
+		t.Errorf("expect shadow version returned, got %+v", got)
+	}
+	list := &networkingv1alpha1.CLBPodBindingList{}
+	if err := c.List(ctx, list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Status.State != networkingv1alpha1.CLBBindingStateBound {
+		t.Errorf("expect shadow version listed, got %+v", list.Items)
+	}
+
+	// 集群中的对象被其它组件修改后，以集群中的为准
+	stored.Status.State = networkingv1alpha1.CLBBindingStateFailed
+	if err := cluster.Status().Update(ctx, stored); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pb), got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.State != networkingv1alpha1.CLBBindingStateFailed || len(got.Finalizers) != 0 {
+		t.Errorf("expect cluster version returned after changed by others, got %+v", got)
+	}
+
+	// 只在内存中创建的对象可以读取、更新和删除
+	created := &networkingv1alpha1.CLBPodBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-1"}}
+	if err := c.Create(ctx, created); err != nil {
+		t.Fatal(err)
+	}
+	created.Status.State = networkingv1alpha1.CLBBindingStateAllocated
+	if err := c.Status().Update(ctx, created); err != nil {
+		t.Fatal(err)
+	}
+	got = &networkingv1alpha1.CLBPodBinding{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(created), got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.State != networkingv1alpha1.CLBBindingStateAllocated {
+		t.Errorf("expect created object updated in memory, got %+v", got)
+	}
+	if err := c.List(ctx, list, client.InNamespace("default")); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 2 {
+		t.Errorf("expect created object listed, got %d items", len(list.Items))
+	}
+	if err := c.Delete(ctx, got); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(created), got); !apierrors.IsNotFound(err) {
+		t.Errorf("expect created object deleted, got %v", err)
+	}
+
+	// 删除集群中的对象后读取不到，集群中的对象不受影响
+	if err := c.Delete(ctx, stored); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pb), got); !apierrors.IsNotFound(err) {
+		t.Errorf("expect deleted object not found, got %v", err)
+	}
+	if err := cluster.Get(ctx, client.ObjectKeyFromObject(pb), stored); err != nil {
+		t.Errorf("expect object not deleted in cluster, got %v", err)
+	}
+}
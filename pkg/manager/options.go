@@ -39,6 +39,12 @@ func GetOptions(scheme *runtime.Scheme, metricsAddr, probeAddr string, enableLea
 		Cache: cache.Options{
 			ByObject: byObject,
 		},
+		// 证书 Secret 按需直接从 apiserver 读取，不缓存集群中所有 Secret 的内容（Secret 的变化通过只缓存元数据的 watch 感知）
+		Client: client.Options{
+			Cache: &client.CacheOptions{
+				DisableFor: []client.Object{&corev1.Secret{}},
+			},
+		},
 		Scheme: scheme,
 		// Metrics endpoint is enabled in 'config/default/kustomization.yaml'. The Metrics options configure the server.
 		// More info:
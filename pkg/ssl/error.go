@@ -0,0 +1,10 @@
+package ssl
+
+import (
+	"strings"
+)
+
+// IsCertificateNotFoundError 判断是否为证书不存在的错误
+func IsCertificateNotFoundError(err error) bool {
+	return strings.Contains(err.Error(), "FailedOperation.CertificateNotFound")
+}
@@ -0,0 +1,114 @@
+package ssl
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+	"github.com/tkestack/tke-extend-network-controller/pkg/cloudapi"
+	"github.com/tkestack/tke-extend-network-controller/pkg/clusterinfo"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// SSL 证书服务的 API 版本
+const apiVersion = "2019-12-05"
+
+var (
+	client     *common.Client
+	clientOnce sync.Once
+)
+
+// SSL 证书服务的接口较少，使用通用客户端调用，避免引入额外的 SDK 依赖
+func getClient() *common.Client {
+	clientOnce.Do(func() {
+		client = common.NewCommonClient(cloudapi.GetCredential(), clusterinfo.Region, cloudapi.NewClientProfile("ssl"))
+		cloudapi.InitClient(client)
+	})
+	return client
+}
+
+func call(ctx context.Context, action string, params map[string]any, res tchttp.Response) error {
+	req := tchttp.NewCommonRequest("ssl", apiVersion, action)
+	req.SetContext(ctx)
+	if err := req.SetActionParameters(params); err != nil {
+		return errors.WithStack(err)
+	}
+	before := time.Now()
+	err := getClient().Send(req, res)
+	// 请求中包含私钥，不打印请求内容
+	log.FromContext(ctx).V(3).Info("ssl api call", "action", action, "cost", time.Since(before).String(), "err", err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+type uploadCertificateResponse struct {
+	*tchttp.BaseResponse
+	Response *struct {
+		CertificateId *string `json:"CertificateId,omitempty"`
+		RepeatCertId  *string `json:"RepeatCertId,omitempty"`
+		RequestId     *string `json:"RequestId,omitempty"`
+	} `json:"Response"`
+}
+
+// 证书类型
+const (
+	// 服务端证书
+	CertificateTypeServer = "SVR"
+	// 客户端 CA 证书，用于双向认证
+	CertificateTypeCA = "CA"
+)
+
+// UploadCertificate 上传证书到 SSL 证书服务，返回证书 ID，相同的证书已上传过时返回已有证书的 ID。CA 证书没有私钥，keyPem 为空
+func UploadCertificate(ctx context.Context, certType, certPem, keyPem, alias string) (string, error) {
+	res := &uploadCertificateResponse{BaseResponse: &tchttp.BaseResponse{}}
+	params := map[string]any{
+		"CertificatePublicKey": certPem,
+		"CertificateType":      certType,
+		"Alias":                alias,
+		"Repeatable":           false,
+	}
+	if keyPem != "" {
+		params["CertificatePrivateKey"] = keyPem
+	}
+	if err := call(ctx, "UploadCertificate", params, res); err != nil {
+		return "", errors.WithStack(err)
+	}
+	if res.Response == nil {
+		return "", errors.New("empty response of UploadCertificate")
+	}
+	if id := res.Response.RepeatCertId; id != nil && *id != "" {
+		return *id, nil
+	}
+	if id := res.Response.CertificateId; id != nil && *id != "" {
+		return *id, nil
+	}
+	return "", errors.New("no certificate id returned by UploadCertificate")
+}
+
+type deleteCertificateResponse struct {
+	*tchttp.BaseResponse
+	Response *struct {
+		DeleteResult *bool   `json:"DeleteResult,omitempty"`
+		RequestId    *string `json:"RequestId,omitempty"`
+	} `json:"Response"`
+}
+
+// DeleteCertificate 删除 SSL 证书服务中的证书，证书仍被云资源关联时删除失败
+func DeleteCertificate(ctx context.Context, certId string) error {
+	res := &deleteCertificateResponse{BaseResponse: &tchttp.BaseResponse{}}
+	if err := call(ctx, "DeleteCertificate", map[string]any{"CertificateId": certId}, res); err != nil {
+		if IsCertificateNotFoundError(err) {
+			return nil
+		}
+		return errors.WithStack(err)
+	}
+	if res.Response == nil || res.Response.DeleteResult == nil || !*res.Response.DeleteResult {
+		return errors.Errorf("delete certificate %s failed", certId)
+	}
+	return nil
+}
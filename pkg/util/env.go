@@ -3,6 +3,7 @@ package util
 import (
 	"os"
 	"strconv"
+	"strings"
 )
 
 func GetWorkerCount(name string) int {
@@ -16,3 +17,19 @@ func GetWorkerCount(name string) int {
 	}
 	return count
 }
+
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// GetCurrentNamespace 获取控制器所在的命名空间，优先使用 POD_NAMESPACE 环境变量，
+// 其次读取 ServiceAccount 挂载的 namespace 文件，都获取不到时返回 kube-system。
+func GetCurrentNamespace() string {
+	if ns := os.Getenv("POD_NAMESPACE"); ns != "" {
+		return ns
+	}
+	if data, err := os.ReadFile(serviceAccountNamespaceFile); err == nil {
+		if ns := strings.TrimSpace(string(data)); ns != "" {
+			return ns
+		}
+	}
+	return "kube-system"
+}
@@ -20,3 +20,8 @@ func IsIPv6LB(addressIPVersion *string) bool {
 	v := strings.ToLower(*addressIPVersion)
 	return v == "ipv6" || v == "ipv6fullchain"
 }
+
+// IsLayer7Protocol 判断是否为七层协议（HTTP/HTTPS），七层协议的端口映射分配的是共享监听器上的转发规则而非端口
+func IsLayer7Protocol(protocol string) bool {
+	return protocol == "HTTP" || protocol == "HTTPS"
+}
@@ -0,0 +1,50 @@
+package util
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// HasReadinessGate 判断 Pod 是否声明了指定的 readiness gate
+func HasReadinessGate(pod *corev1.Pod, conditionType corev1.PodConditionType) bool {
+	for _, gate := range pod.Spec.ReadinessGates {
+		if gate.ConditionType == conditionType {
+			return true
+		}
+	}
+	return false
+}
+
+// HasSchedulingGate 判断 Pod 是否有指定的 scheduling gate
+func HasSchedulingGate(pod *corev1.Pod, name string) bool {
+	for _, gate := range pod.Spec.SchedulingGates {
+		if gate.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveSchedulingGate 移除 Pod 指定的 scheduling gate，返回是否有移除
+func RemoveSchedulingGate(pod *corev1.Pod, name string) bool {
+	gates := []corev1.PodSchedulingGate{}
+	for _, gate := range pod.Spec.SchedulingGates {
+		if gate.Name != name {
+			gates = append(gates, gate)
+		}
+	}
+	if len(gates) == len(pod.Spec.SchedulingGates) {
+		return false
+	}
+	pod.Spec.SchedulingGates = gates
+	return true
+}
+
+// GetPodCondition 获取 Pod 指定类型的 condition，不存在返回 nil
+func GetPodCondition(pod *corev1.Pod, conditionType corev1.PodConditionType) *corev1.PodCondition {
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == conditionType {
+			return &pod.Status.Conditions[i]
+		}
+	}
+	return nil
+}
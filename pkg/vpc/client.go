@@ -22,6 +22,7 @@ func GetClient(region string) *vpc.Client {
 	if err != nil {
 		panic(err)
 	}
+	cloudapi.InitClient(&client.Client)
 	clients[region] = client
 	return client
 }
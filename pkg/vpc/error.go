@@ -0,0 +1,10 @@
+package vpc
+
+import (
+	"strings"
+)
+
+// IsSecurityGroupNotFoundError 判断是否为安全组不存在的错误
+func IsSecurityGroupNotFoundError(err error) bool {
+	return strings.Contains(err.Error(), "ResourceNotFound")
+}
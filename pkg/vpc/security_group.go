@@ -0,0 +1,127 @@
+package vpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+	vpc "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/vpc/v20170312"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// 端口池维护的入站规则放通的协议
+var ingressProtocols = []string{"TCP", "UDP"}
+
+// EnsureIngressPortRange 确保安全组中存在放通指定端口范围（如 30000-30999）的 TCP 和 UDP 入站规则（来源 0.0.0.0/0），
+// 规则通过描述识别，端口范围变化时原地替换，缺少的规则插入到最前面，避免被已有的拒绝规则覆盖。返回是否修改了规则。
+func EnsureIngressPortRange(ctx context.Context, region, sgId, description, portRange string) (changed bool, err error) {
+	client := GetClient(region)
+	req := vpc.NewDescribeSecurityGroupPoliciesRequest()
+	req.SecurityGroupId = &sgId
+	before := time.Now()
+	resp, err := client.DescribeSecurityGroupPoliciesWithContext(ctx, req)
+	log.FromContext(ctx).V(3).Info("vpc api call", "action", "DescribeSecurityGroupPolicies", "sgId", sgId, "cost", time.Since(before).String(), "err", err)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	policySet := resp.Response.SecurityGroupPolicySet
+	if policySet == nil {
+		return false, errors.Errorf("no policy set of security group %s returned", sgId)
+	}
+	// 先原地替换已有的规则再插入缺少的规则，插入规则会改变已有规则的索引
+	missing := []*vpc.SecurityGroupPolicy{}
+	for _, protocol := range ingressProtocols {
+		var current *vpc.SecurityGroupPolicy
+		for _, policy := range policySet.Ingress {
+			if policy.PolicyDescription != nil && *policy.PolicyDescription == description && policy.Protocol != nil && *policy.Protocol == protocol {
+				current = policy
+				break
+			}
+		}
+		expected := &vpc.SecurityGroupPolicy{
+			Protocol:          common.StringPtr(protocol),
+			Port:              common.StringPtr(portRange),
+			CidrBlock:         common.StringPtr("0.0.0.0/0"),
+			Action:            common.StringPtr("ACCEPT"),
+			PolicyDescription: common.StringPtr(description),
+		}
+		if current == nil {
+			missing = append(missing, expected)
+			continue
+		}
+		if current.Port != nil && *current.Port == portRange && current.Action != nil && *current.Action == "ACCEPT" && current.CidrBlock != nil && *current.CidrBlock == "0.0.0.0/0" {
+			continue
+		}
+		// 规则被修改或端口范围变化，原地替换
+		expected.PolicyIndex = current.PolicyIndex
+		req := vpc.NewReplaceSecurityGroupPolicyRequest()
+		req.SecurityGroupId = &sgId
+		req.SecurityGroupPolicySet = &vpc.SecurityGroupPolicySet{Ingress: []*vpc.SecurityGroupPolicy{expected}}
+		before := time.Now()
+		_, err := client.ReplaceSecurityGroupPolicyWithContext(ctx, req)
+		log.FromContext(ctx).V(3).Info("vpc api call", "action", "ReplaceSecurityGroupPolicy", "sgId", sgId, "protocol", protocol, "port", portRange, "cost", time.Since(before).String(), "err", err)
+		if err != nil {
+			return changed, errors.WithStack(err)
+		}
+		changed = true
+	}
+	for _, policy := range missing {
+		policy.PolicyIndex = common.Int64Ptr(0)
+		req := vpc.NewCreateSecurityGroupPoliciesRequest()
+		req.SecurityGroupId = &sgId
+		req.SecurityGroupPolicySet = &vpc.SecurityGroupPolicySet{Ingress: []*vpc.SecurityGroupPolicy{policy}}
+		before := time.Now()
+		_, err := client.CreateSecurityGroupPoliciesWithContext(ctx, req)
+		log.FromContext(ctx).V(3).Info("vpc api call", "action", "CreateSecurityGroupPolicies", "sgId", sgId, "protocol", *policy.Protocol, "port", portRange, "cost", time.Since(before).String(), "err", err)
+		if err != nil {
+			return changed, errors.WithStack(err)
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+// DeleteIngressRules 删除安全组中描述为 description 的入站规则（即 EnsureIngressPortRange 维护的规则），
+// 安全组不存在时忽略。返回是否删除了规则。
+func DeleteIngressRules(ctx context.Context, region, sgId, description string) (deleted bool, err error) {
+	client := GetClient(region)
+	req := vpc.NewDescribeSecurityGroupPoliciesRequest()
+	req.SecurityGroupId = &sgId
+	before := time.Now()
+	resp, err := client.DescribeSecurityGroupPoliciesWithContext(ctx, req)
+	log.FromContext(ctx).V(3).Info("vpc api call", "action", "DescribeSecurityGroupPolicies", "sgId", sgId, "cost", time.Since(before).String(), "err", err)
+	if err != nil {
+		if IsSecurityGroupNotFoundError(err) {
+			return false, nil
+		}
+		return false, errors.WithStack(err)
+	}
+	policySet := resp.Response.SecurityGroupPolicySet
+	if policySet == nil {
+		return false, nil
+	}
+	policies := []*vpc.SecurityGroupPolicy{}
+	for _, policy := range policySet.Ingress {
+		if policy.PolicyDescription != nil && *policy.PolicyDescription == description {
+			policies = append(policies, &vpc.SecurityGroupPolicy{PolicyIndex: policy.PolicyIndex})
+		}
+	}
+	if len(policies) == 0 {
+		return false, nil
+	}
+	// 按索引删除时需带上查询到的版本号，规则在此期间被修改时删除失败，下次对账重试
+	delReq := vpc.NewDeleteSecurityGroupPoliciesRequest()
+	delReq.SecurityGroupId = &sgId
+	delReq.SecurityGroupPolicySet = &vpc.SecurityGroupPolicySet{Version: policySet.Version, Ingress: policies}
+	before = time.Now()
+	_, err = client.DeleteSecurityGroupPoliciesWithContext(ctx, delReq)
+	log.FromContext(ctx).V(3).Info("vpc api call", "action", "DeleteSecurityGroupPolicies", "sgId", sgId, "description", description, "count", len(policies), "cost", time.Since(before).String(), "err", err)
+	if err != nil {
+		if IsSecurityGroupNotFoundError(err) {
+			return false, nil
+		}
+		return false, errors.WithStack(err)
+	}
+	return true, nil
+}